@@ -0,0 +1,981 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/export"
+	"github.com/ApesJs/go-migration-app/history"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/ledger"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/notify"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/runner"
+	"github.com/ApesJs/go-migration-app/service/audit"
+	"github.com/ApesJs/go-migration-app/service/inspect"
+	inspectHelper "github.com/ApesJs/go-migration-app/service/inspect/helper"
+	"github.com/ApesJs/go-migration-app/service/invariant"
+	"github.com/ApesJs/go-migration-app/service/legacy"
+	legacyHelper "github.com/ApesJs/go-migration-app/service/legacy/helper"
+	"github.com/ApesJs/go-migration-app/service/monitor"
+	"github.com/ApesJs/go-migration-app/service/orphan"
+	orphanHelper "github.com/ApesJs/go-migration-app/service/orphan/helper"
+	"github.com/ApesJs/go-migration-app/service/profile"
+	"github.com/ApesJs/go-migration-app/service/reconcile"
+	reconcileHelper "github.com/ApesJs/go-migration-app/service/reconcile/helper"
+	"github.com/ApesJs/go-migration-app/service/verify"
+	verifyHelper "github.com/ApesJs/go-migration-app/service/verify/helper"
+	"github.com/ApesJs/go-migration-app/tracing"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func runCommand(name string, args []string) {
+	switch name {
+	case "generate-legacy":
+		generateLegacyCommand(args)
+	case "run":
+		runMigrationsCommand(args)
+	case "reconcile":
+		reconcileCommand(args)
+	case "verify":
+		verifyCommand(args)
+	case "checksum":
+		checksumCommand(args)
+	case "audit":
+		auditCommand(args)
+	case "orphans":
+		orphansCommand(args)
+	case "inspect":
+		inspectCommand(args)
+	case "invariants":
+		invariantsCommand(args)
+	case "profile":
+		profileCommand(args)
+	case "ledger":
+		ledgerCommand(args)
+	case "monitor":
+		monitorCommand(args)
+	case "html-report":
+		htmlReportCommand(args)
+	case "history":
+		historyCommand(args)
+	case "notify-test":
+		notifyTestCommand(args)
+	case runner.ChildCommand:
+		runSingleMigrationCommand(args)
+	case "help", "-h", "--help":
+		printUsage()
+	default:
+		fmt.Printf("%s\n\n", i18n.T("cmd.unknown_command", name))
+		printUsage()
+		os.Exit(2)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: go-migration-app [--lang en|id] [--trace otlp|file] <command> [options]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  generate-legacy          Create and fill a synthetic legacy database for load testing")
+	fmt.Println("  run [name...]            Run migrations, independent ones in parallel (--list shows the order)")
+	fmt.Println("  reconcile <entity|all>   Compare source and target keys: missing, extra and matched")
+	fmt.Println("  verify <mapping|all>     Compare mapped field values row by row and show the differences")
+	fmt.Println("  checksum [name...]       Compare sums, min/max and distribution of money fields per organization")
+	fmt.Println("  audit                    Find placeholder values and dangling references in the targets")
+	fmt.Println("  orphans [reference...]   List legacy rows whose foreign keys point to missing rows")
+	fmt.Println("  inspect <entity>         Show legacy records next to their migrated rows as JSON")
+	fmt.Println("  invariants [name...]     Check post-migration invariants declared in a file")
+	fmt.Println("  profile [migration...]   Profile the legacy columns each migration reads")
+	fmt.Println("  ledger [migration...]    Summarize or export data altered by the last run")
+	fmt.Println("  monitor [entity...]      Repeat reconciliation on a schedule and record drift")
+	fmt.Println("  html-report [run-id]     Build a self-contained HTML report from a run's JSON reports")
+	fmt.Println("  history [diff <a> <b>]   List recorded runs or compare the counts of two runs")
+	fmt.Println("  notify-test              Send a sample notification to the configured webhooks")
+	fmt.Println()
+	fmt.Println("Run without a command to execute the service enabled in main.go.")
+	fmt.Println("Console language: --lang or MIGRATION_LANG (en, id), default en.")
+	fmt.Println("Tracing: --trace or TRACE_EXPORTER (otlp, file or otlp,file), off by default.")
+}
+
+func generateLegacyCommand(args []string) {
+	fs := flag.NewFlagSet("generate-legacy", flag.ExitOnError)
+	scale := fs.Int("scale", 1, "row multiplier (1 = ~2000 users, 300 packages)")
+	seed := fs.Int64("seed", 1, "random seed, the same seed gives the same dataset")
+	anomalyRate := fs.Float64("anomaly-rate", 0.02, "chance (0-1) of injecting duplicates, NULLs and over-long values")
+	reset := fs.Bool("reset", false, "drop existing legacy tables before generating")
+	fs.Parse(args)
+
+	legacy.GenerateLegacyService(legacyHelper.GenerateOptions{
+		Scale:       *scale,
+		Seed:        *seed,
+		AnomalyRate: *anomalyRate,
+		Reset:       *reset,
+	})
+}
+
+func runMigrationsCommand(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	maxConnsPerDB := fs.Int("max-conns-per-db", 8, "total connections to one database across running migrations (0 = unlimited)")
+	connsPerMigration := fs.Int("conns-per-migration", 4, "connections one migration may open to each database it uses")
+	list := fs.Bool("list", false, "print the migrations grouped by dependency level and exit")
+	invariantsPath := fs.String("invariants", defaultInvariantsPath, "invariants file checked after the run (empty = skip)")
+	exportDir := fs.String("export-dir", "", "write actionable lists (duplicates, generated slugs, ...) as CSV to this directory")
+	exportXLSX := fs.Bool("export-xlsx", false, "with --export-dir, also write every list as .xlsx")
+	metricsListen := fs.String("metrics-listen", "", "serve Prometheus metrics at http://<addr>/metrics while the run lasts, e.g. :9464")
+	metricsDir := fs.String("metrics-textfile-dir", "", "write Prometheus textfile-collector files (*.prom) to this directory")
+	recordHistory := fs.Bool("history", true, "record the run in the migration_history table of HISTORY_DB")
+	notifyWebhook := fs.String("notify-webhook", notify.WebhookURL(), "POST a JSON event on start, completion, failure and error threshold")
+	notifySlack := fs.String("notify-slack", notify.SlackURL(), "Slack-compatible incoming webhook for the same events")
+	notifyThreshold := fs.Int("notify-error-threshold", notify.ErrorThreshold(), "notify once when a migration has logged this many errors (0 = off)")
+	fs.Parse(args)
+
+	// Dengan satu koneksi per database migrasi menunggu dirinya sendiri selamanya
+	if *connsPerMigration < runner.MinConnsPerMigration {
+		fmt.Println(i18n.T("cmd.conns_too_low", "conns-per-migration", runner.MinConnsPerMigration))
+		os.Exit(2)
+	}
+	if *maxConnsPerDB > 0 && *maxConnsPerDB < runner.MinConnsPerMigration {
+		fmt.Println(i18n.T("cmd.conns_too_low", "max-conns-per-db", runner.MinConnsPerMigration))
+		os.Exit(2)
+	}
+
+	selected, err := runner.Select(migrations, fs.Args())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	levels, err := runner.Levels(selected)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if *list {
+		for i, level := range levels {
+			fmt.Println(i18n.T("cmd.level", i+1, strings.Join(level, ", ")))
+		}
+		return
+	}
+
+	// Export snapshot sumber sekali di sini agar semua proses migrasi membaca data yang sama
+	if usesDatabase(selected, dbProdExistingUmrah) && os.Getenv("PROD_EXISTING_DB_SNAPSHOT_ID") == "" {
+		prodExistingUmrahDB := database.ConnectionProdExistingUmrahDB()
+		defer prodExistingUmrahDB.Close()
+		os.Setenv("PROD_EXISTING_DB_SNAPSHOT_ID", database.CurrentSourceSnapshot().ID)
+	}
+
+	// Proses migrasi membaca folder export dari environment, seperti LEDGER_DIR
+	if *exportDir != "" {
+		os.Setenv("MIGRATION_EXPORT_DIR", *exportDir)
+		if *exportXLSX {
+			os.Setenv("MIGRATION_EXPORT_XLSX", "true")
+		}
+	}
+
+	// Setiap proses migrasi menulis metriknya ke file .prom, endpoint /metrics menggabungkannya
+	if *metricsDir != "" {
+		os.Setenv("METRICS_TEXTFILE_DIR", *metricsDir)
+	} else if *metricsListen != "" {
+		dir, err := os.MkdirTemp("", "migration-metrics")
+		if err != nil {
+			logging.Fatal("Error creating metrics directory", "error", err)
+		}
+		defer os.RemoveAll(dir)
+		os.Setenv("METRICS_TEXTFILE_DIR", dir)
+	}
+	if *metricsListen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		go func() {
+			if err := http.ListenAndServe(*metricsListen, mux); err != nil {
+				logging.Fatal("Error starting metrics endpoint", "error", err)
+			}
+		}()
+		fmt.Println(i18n.T("cmd.metrics_listening", *metricsListen))
+	}
+
+	// Proses migrasi membaca tujuan notifikasi dari environment untuk event batas error
+	os.Setenv("NOTIFY_WEBHOOK_URL", *notifyWebhook)
+	os.Setenv("NOTIFY_SLACK_WEBHOOK_URL", *notifySlack)
+	os.Setenv("NOTIFY_ERROR_THRESHOLD", strconv.Itoa(*notifyThreshold))
+
+	startTime := time.Now()
+
+	// Riwayat run di database target, gagal mencatat tidak menghentikan migrasi
+	var recorder *history.Recorder
+	if *recordHistory {
+		recorder, err = history.Begin(report.RunID(), startTime, runOptions(fs, selected))
+		if err != nil {
+			fmt.Println(i18n.T("cmd.history_not_recorded", err))
+		}
+	}
+
+	started := notify.Event{Event: notify.EventStarted, RunID: report.RunID(), StartedAt: startTime}
+	for _, m := range selected {
+		started.Migrations = append(started.Migrations, notify.Migration{Name: m.Name, Status: "pending"})
+	}
+	notify.Send(started)
+
+	names := make([]string, len(selected))
+	for i, m := range selected {
+		names[i] = m.Name
+	}
+	tracing.Root().SetAttr("migration.selected", strings.Join(names, ","))
+
+	results, runErr := runner.Run(selected, runner.Options{
+		MaxConnsPerDB:     *maxConnsPerDB,
+		ConnsPerMigration: *connsPerMigration,
+	})
+
+	fmt.Printf("\n%s\n", i18n.T("cmd.run_summary"))
+	for _, result := range results {
+		line := fmt.Sprintf("- %-22s %-8s %s", result.Name, result.Status, result.Duration.Round(time.Second))
+		if result.Err != nil {
+			line += fmt.Sprintf(" (%v)", result.Err)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println(i18n.T("cmd.total_duration", time.Since(startTime).Round(time.Second)))
+
+	printLedgerSummary(results)
+
+	// Invariant diperiksa sebelum report, riwayat dan notifikasi ditulis supaya
+	// pelanggaran ikut tercatat di status run
+	invariantsOK := checkRunInvariants(*invariantsPath, runErr)
+	run := writeRunReport(results, startTime, runErr, invariantsOK)
+	writeRunMetrics(results)
+	if err := recorder.Finish(run); err != nil {
+		fmt.Println(i18n.T("cmd.history_error", err))
+	} else if recorder != nil {
+		fmt.Println(i18n.T("cmd.history_recorded", run.RunID))
+	}
+	notifyRunFinished(run, runErr)
+	printTrace()
+
+	if runErr != nil {
+		fmt.Println(runErr)
+		tracing.Root().SetError(runErr)
+		logging.Exit(1)
+	}
+	if !invariantsOK {
+		tracing.Root().SetError(fmt.Errorf("invariants violated"))
+		logging.Exit(1)
+	}
+}
+
+// checkRunInvariants memeriksa invariant setelah run, hanya jika semua migrasi
+// selesai. File default boleh tidak ada. Mengembalikan false jika ada yang dilanggar.
+func checkRunInvariants(path string, runErr error) bool {
+	if path == "" || runErr != nil {
+		return true
+	}
+	if _, err := os.Stat(path); err != nil && path == defaultInvariantsPath {
+		fmt.Println(i18n.T("cmd.no_invariants", defaultInvariantsPath))
+		return true
+	}
+	if !invariant.InvariantService(path, nil, 20) {
+		fmt.Println(i18n.T("cmd.invariants_violated"))
+		return false
+	}
+	return true
+}
+
+// printTrace mencetak id trace run dan tujuannya supaya bisa dicari di backend tracing
+func printTrace() {
+	id := tracing.TraceID()
+	if id == "" {
+		return
+	}
+	for _, exporter := range tracing.Exporters() {
+		switch exporter {
+		case tracing.ExporterOTLP:
+			fmt.Println(i18n.T("cmd.trace_sent", id, tracing.Endpoint()))
+		case tracing.ExporterFile:
+			fmt.Println(i18n.T("cmd.trace_written", id, filepath.Join(tracing.Dir(), report.RunID())))
+		}
+	}
+}
+
+// runSingleMigrationCommand dipanggil runner untuk menjalankan satu migrasi di proses anak
+func runSingleMigrationCommand(args []string) {
+	if len(args) != 1 {
+		fmt.Printf("Usage: go-migration-app %s <name>\n", runner.ChildCommand)
+		os.Exit(2)
+	}
+
+	m, ok := runner.Find(migrations, args[0])
+	if !ok {
+		fmt.Println(i18n.T("cmd.unknown_migration", args[0]))
+		os.Exit(2)
+	}
+
+	if err := ledger.Open(m.Name); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	report.Start(m.Name)
+	export.Start(m.Name)
+	metrics.Start(m.Name)
+
+	// logging.Fatal di service keluar lewat logging.Exit, jadi report aborted,
+	// metrik, export dan ledger tetap ditulis sebelum proses berhenti
+	var (
+		once    sync.Once
+		aborted bool
+	)
+	finish := func() {
+		once.Do(func() { aborted = finishMigration(m.Name) })
+	}
+	logging.OnExit(finish)
+
+	m.Run()
+	finish()
+
+	// Exit code adalah satu-satunya tanda gagal bagi runner, migrasi yang bergantung
+	// pada migrasi ini tidak boleh jalan di atas transaksi yang di-rollback
+	if aborted {
+		logging.Exit(1)
+	}
+}
+
+// finishMigration menutup report, metrik, export dan ledger satu proses migrasi.
+// Hasilnya true jika migrasi berhenti sebelum selesai.
+func finishMigration(name string) bool {
+	aborted := report.Close()
+	metrics.Close()
+
+	files, err := export.Close()
+	if err != nil {
+		fmt.Println(i18n.T("cmd.export_error", err))
+	}
+	for _, file := range files {
+		fmt.Println(i18n.T("cmd.exported", file))
+	}
+
+	if recorded := ledger.Close(); recorded > 0 {
+		fmt.Println(i18n.T("cmd.lossy_recorded", recorded, ledger.Dir()))
+	}
+
+	if aborted {
+		fmt.Println(i18n.T("cmd.migration_aborted", name))
+		tracing.Root().SetError(fmt.Errorf("migration %s aborted", name))
+	}
+	return aborted
+}
+
+func usesDatabase(selected []runner.Migration, name string) bool {
+	for _, m := range selected {
+		for _, db := range m.Databases {
+			if db == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func reconcileCommand(args []string) {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "maximum missing/extra rows printed per entity (0 = all)")
+	runID := fs.String("run-id", "", "save the result to the run report folder of this run ('latest' = newest run)")
+	fs.Usage = func() {
+		fmt.Println("Usage: go-migration-app reconcile [--limit N] [--run-id ID|latest] <entity|all>...")
+		fmt.Println()
+		fmt.Println("Entities:")
+		for _, entity := range reconcileHelper.Entities {
+			fmt.Printf("  %-18s %s\n", entity.Name, entity.Description)
+		}
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if *runID == "latest" {
+		latest, err := report.LatestRunID()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		*runID = latest
+	}
+
+	reconcile.ReconcileService(fs.Args(), *limit, *runID)
+}
+
+func verifyCommand(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "maximum differing rows printed per mapping (0 = all)")
+	fs.Usage = func() {
+		fmt.Println("Usage: go-migration-app verify [--limit N] <mapping|all>...")
+		fmt.Println()
+		fmt.Println("Mappings:")
+		for _, mapping := range verifyHelper.Mappings {
+			fmt.Printf("  %-22s %s (%d fields)\n", mapping.Name, mapping.Description, len(mapping.Fields))
+		}
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	verify.VerifyService(fs.Args(), *limit)
+}
+
+func checksumCommand(args []string) {
+	fs := flag.NewFlagSet("checksum", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "maximum organizations printed per check (0 = all)")
+	fs.Usage = func() {
+		fmt.Println("Usage: go-migration-app checksum [--limit N] [name|all]...")
+		fmt.Println()
+		fmt.Println("Checks:")
+		for _, check := range verifyHelper.MoneyChecks {
+			fmt.Printf("  %-16s %s\n", check.Name, check.Description)
+		}
+	}
+	fs.Parse(args)
+
+	// Exit code 1 jika ada nilai uang yang berubah, supaya bisa dipakai sebagai gate go-live
+	if verify.ChecksumService(fs.Args(), *limit) {
+		os.Exit(1)
+	}
+}
+
+func auditCommand(args []string) {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "maximum rows printed per cause (0 = all)")
+	export := fs.String("export", "", "write all findings to a .csv or .json file")
+	fs.Parse(args)
+
+	if ext := strings.ToLower(filepath.Ext(*export)); *export != "" && ext != ".csv" && ext != ".json" {
+		fmt.Println(i18n.T("cmd.export_format"))
+		os.Exit(2)
+	}
+
+	audit.AuditService(*limit, *export)
+}
+
+func orphansCommand(args []string) {
+	fs := flag.NewFlagSet("orphans", flag.ExitOnError)
+	config := fs.String("config", "", "JSON file with extra or replacement references")
+	limit := fs.Int("limit", 20, "maximum rows printed per reference (0 = all)")
+	export := fs.String("export", "", "write all orphans to a .csv or .json file")
+	fs.Usage = func() {
+		fmt.Println("Usage: go-migration-app orphans [--config file.json] [--limit N] [--export file] [reference...]")
+		fmt.Println()
+		fmt.Println("References:")
+		for _, fk := range orphanHelper.DefaultForeignKeys {
+			fmt.Printf("  %-32s -> %s.%s\n", fk.Name, fk.ParentTable, fk.ParentColumn)
+		}
+	}
+	fs.Parse(args)
+
+	if ext := strings.ToLower(filepath.Ext(*export)); *export != "" && ext != ".csv" && ext != ".json" {
+		fmt.Println(i18n.T("cmd.export_format"))
+		os.Exit(2)
+	}
+
+	orphan.OrphanReportService(fs.Args(), *config, *limit, *export)
+}
+
+func inspectCommand(args []string) {
+	usage := func() {
+		fmt.Println("Usage: go-migration-app inspect <entity> [--sample N | --id X]")
+		fmt.Println()
+		fmt.Println("Entities:")
+		for _, inspector := range inspectHelper.Inspectors {
+			fmt.Printf("  %-14s %s\n", inspector.Name, inspector.Description)
+		}
+	}
+
+	// Entitas ditulis sebelum flag: inspect package --sample 20
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		usage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	sample := fs.Int("sample", 20, "number of random records to show")
+	id := fs.String("id", "", "show the record with this legacy key instead of a sample")
+	fs.Usage = usage
+	fs.Parse(args[1:])
+
+	inspect.InspectService(args[0], *sample, *id)
+}
+
+// defaultInvariantsPath adalah file invariant yang diperiksa setelah command run
+const defaultInvariantsPath = "invariants.json"
+
+func invariantsCommand(args []string) {
+	fs := flag.NewFlagSet("invariants", flag.ExitOnError)
+	file := fs.String("file", defaultInvariantsPath, "invariants file (JSON)")
+	limit := fs.Int("limit", 20, "violations printed per invariant (0 = all)")
+	fs.Parse(args)
+
+	if !invariant.InvariantService(*file, fs.Args(), *limit) {
+		os.Exit(1)
+	}
+}
+
+func profileCommand(args []string) {
+	fs := flag.NewFlagSet("profile", flag.ExitOnError)
+	samples := fs.Int("samples", 5, "example values printed per column that exceed the target limit")
+	strict := fs.Bool("strict", false, "exit with status 1 if any value would be truncated or has an invalid format")
+	fs.Parse(args)
+
+	if !profile.ProfileService(fs.Args(), *samples) && *strict {
+		os.Exit(1)
+	}
+}
+
+// runOptions mengumpulkan flag yang diisi dan migrasi yang dipilih untuk migration_history
+func runOptions(fs *flag.FlagSet, selected []runner.Migration) map[string]string {
+	options := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		options[f.Name] = f.Value.String()
+	})
+	var names []string
+	for _, m := range selected {
+		names = append(names, m.Name)
+	}
+	options["migrations"] = strings.Join(names, ",")
+	if id := tracing.TraceID(); id != "" {
+		options["trace_id"] = id
+	}
+	return options
+}
+
+// writeRunReport menulis run.json yang merangkum status semua migrasi di run ini
+func writeRunReport(results []runner.Result, startTime time.Time, runErr error, invariantsOK bool) *report.Run {
+	run := &report.Run{
+		RunID:      report.RunID(),
+		Status:     report.StatusCompleted,
+		GitVersion: report.GitVersion(),
+		StartedAt:  startTime,
+		FinishedAt: time.Now(),
+		Snapshot:   report.SourceSnapshot(),
+	}
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+	if runErr != nil {
+		run.Status = report.StatusCompletedWithErrors
+	}
+
+	for _, result := range results {
+		item := report.MigrationResult{
+			Name:       result.Name,
+			Status:     result.Status,
+			DurationMs: result.Duration.Milliseconds(),
+		}
+		if result.Err != nil {
+			item.Error = result.Err.Error()
+		}
+		if r, err := report.Load(report.Path(run.RunID, result.Name)); err == nil {
+			item.Report = result.Name + ".json"
+			if r.Status != report.StatusCompleted {
+				run.Status = report.StatusCompletedWithErrors
+			}
+		}
+		run.Migrations = append(run.Migrations, item)
+	}
+	if !invariantsOK {
+		run.Status = report.StatusInvariantsViolated
+	}
+
+	path, err := report.WriteRun(run)
+	if err != nil {
+		fmt.Println(i18n.T("cmd.run_report_error", err))
+		return run
+	}
+	fmt.Println(i18n.T("cmd.run_report_written", path))
+	return run
+}
+
+// notifyRunFinished mengirim event completed atau failed dengan ringkasan setiap migrasi
+func notifyRunFinished(run *report.Run, runErr error) {
+	if !notify.Enabled() {
+		return
+	}
+	event := notify.Event{
+		Event:      notify.EventCompleted,
+		RunID:      run.RunID,
+		Status:     run.Status,
+		StartedAt:  run.StartedAt,
+		FinishedAt: &run.FinishedAt,
+		DurationMs: run.DurationMs,
+		Report:     report.RunPath(run.RunID),
+	}
+	if runErr != nil || run.Status == report.StatusInvariantsViolated {
+		event.Event = notify.EventFailed
+	}
+	if abs, err := filepath.Abs(event.Report); err == nil {
+		event.Report = abs
+	}
+
+	for _, result := range run.Migrations {
+		m := notify.Migration{Name: result.Name, Status: result.Status, DurationMs: result.DurationMs, Error: result.Error}
+		if result.Report != "" {
+			if r, err := report.Load(report.Path(run.RunID, result.Name)); err == nil {
+				m.Processed = r.Processed()
+				m.Failed = r.Outcome(report.OutcomeFailed)
+				m.Errors = r.ErrorCount
+			}
+		}
+		event.Migrations = append(event.Migrations, m)
+	}
+	notify.Send(event)
+}
+
+// writeRunMetrics menulis status dan durasi setiap migrasi ke migration_run.prom
+func writeRunMetrics(results []runner.Result) {
+	if metrics.TextfileDir() == "" {
+		return
+	}
+	for _, result := range results {
+		metrics.Set("migration_duration_seconds", "Wall-clock duration of the migration in the last run.", result.Duration.Seconds(), "migration", result.Name)
+		metrics.Set("migration_status", "Status of the migration in the last run (1 for the current status).", 1, "migration", result.Name, "status", result.Status)
+	}
+	if err := metrics.WriteFile(metrics.TextfilePath("run")); err != nil {
+		fmt.Println(i18n.T("cmd.run_metrics_error", err))
+	}
+}
+
+// printLedgerSummary mencetak jumlah perubahan lossy per migrasi yang baru selesai
+func printLedgerSummary(results []runner.Result) {
+	var names []string
+	for _, result := range results {
+		if result.Status == "done" || result.Status == "failed" {
+			names = append(names, result.Name)
+		}
+	}
+
+	counts := make(map[string]int)
+	total := 0
+	for _, name := range names {
+		events, err := ledger.Load(ledger.Dir(), []string{name})
+		if err != nil {
+			continue
+		}
+		counts[name] = len(events)
+		total += len(events)
+	}
+	if total == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("cmd.lossy_title", ledger.Dir()))
+	for _, name := range names {
+		if counts[name] > 0 {
+			fmt.Printf("- %-22s %d\n", name, counts[name])
+		}
+	}
+	fmt.Println(i18n.T("cmd.lossy_hint"))
+}
+
+func ledgerCommand(args []string) {
+	fs := flag.NewFlagSet("ledger", flag.ExitOnError)
+	dir := fs.String("dir", ledger.Dir(), "ledger directory")
+	limit := fs.Int("limit", 0, "also print the first N events per migration")
+	export := fs.String("export", "", "write all events to a .csv or .json file")
+	fs.Parse(args)
+
+	if ext := strings.ToLower(filepath.Ext(*export)); *export != "" && ext != ".csv" && ext != ".json" {
+		fmt.Println(i18n.T("cmd.ledger_format", filepath.Ext(*export)))
+		os.Exit(2)
+	}
+
+	events, err := ledger.Load(*dir, fs.Args())
+	if err != nil {
+		logging.Fatal("Error loading ledger", "error", err)
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("cmd.ledger_title", *dir))
+	fmt.Printf("------------------------\n")
+
+	migration := ""
+	for _, group := range ledger.Summarize(events) {
+		if group.Migration != migration {
+			migration = group.Migration
+			fmt.Printf("\n%s\n", migration)
+		}
+		fmt.Printf("- %-16s %-16s %-10s %d\n", group.Entity, group.Field, group.Reason, group.Count)
+	}
+
+	if *limit > 0 {
+		printed := make(map[string]int)
+		for _, e := range events {
+			if printed[e.Migration] >= *limit {
+				continue
+			}
+			if printed[e.Migration] == 0 {
+				fmt.Printf("\n%s\n", i18n.T("cmd.ledger_events", e.Migration))
+			}
+			printed[e.Migration]++
+			fmt.Printf("%d. %s %s %s: %q -> %q (%s)\n", printed[e.Migration], e.Entity, e.SourceID, e.Field, e.Original, e.New, e.Reason)
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("cmd.ledger_total", len(events)))
+
+	if *export != "" {
+		if err := ledger.Export(*export, events); err != nil {
+			logging.Fatal("Error exporting ledger", "error", err)
+		}
+		fmt.Println(i18n.T("cmd.ledger_exported", *export))
+	}
+}
+
+func monitorCommand(args []string) {
+	fs := flag.NewFlagSet("monitor", flag.ExitOnError)
+	interval := fs.Duration("interval", 15*time.Minute, "time between drift checks")
+	statusFile := fs.String("status-file", "drift-status.json", "file with the latest drift (empty = none)")
+	listen := fs.String("listen", "", "address for the /status endpoint, e.g. :8090")
+	once := fs.Bool("once", false, "run one check and exit, for cron")
+	fs.Usage = func() {
+		fmt.Println("Usage: go-migration-app monitor [--interval D] [--status-file F] [--listen ADDR] [--once] [entity...]")
+		fmt.Println()
+		fmt.Printf("Entities (default: %s):\n", strings.Join(monitor.DefaultEntities, ", "))
+		for _, entity := range reconcileHelper.Entities {
+			fmt.Printf("  %-18s %s\n", entity.Name, entity.Description)
+		}
+	}
+	fs.Parse(args)
+
+	if *interval <= 0 {
+		fmt.Println(i18n.T("cmd.interval_positive"))
+		os.Exit(2)
+	}
+
+	monitor.MonitorService(fs.Args(), monitor.Options{
+		Interval:   *interval,
+		StatusFile: *statusFile,
+		Listen:     *listen,
+		Once:       *once,
+	})
+}
+
+func htmlReportCommand(args []string) {
+	fs := flag.NewFlagSet("html-report", flag.ExitOnError)
+	out := fs.String("out", "", "output file (default <report dir>/<run-id>/report.html)")
+	fs.Usage = func() {
+		fmt.Println("Usage: go-migration-app html-report [--out file.html] [run-id]")
+		fmt.Println()
+		fmt.Println("Without a run id the newest run in the report directory is used.")
+	}
+	fs.Parse(args)
+
+	runID := fs.Arg(0)
+	if runID == "" {
+		latest, err := report.LatestRunID()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		runID = latest
+	}
+
+	run, err := report.LoadRun(runID)
+	if err != nil {
+		logging.Fatal("Error loading run report", "error", err)
+	}
+	reports, err := report.LoadRunReports(run)
+	if err != nil {
+		logging.Fatal("Error loading migration report", "error", err)
+	}
+
+	recon, err := report.LoadReconciliation(runID)
+	if err != nil {
+		logging.Fatal("Error loading reconciliation report", "error", err)
+	}
+
+	path := *out
+	if path == "" {
+		path = filepath.Join(report.Dir(), runID, "report.html")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		logging.Fatal("Error creating HTML report", "error", err)
+	}
+	defer f.Close()
+
+	if err := report.WriteHTML(f, run, reports, recon); err != nil {
+		logging.Fatal("Error writing HTML report", "error", err)
+	}
+	fmt.Println(i18n.T("cmd.html_written", path))
+}
+
+func historyCommand(args []string) {
+	if len(args) > 0 && args[0] == "diff" {
+		historyDiffCommand(args[1:])
+		return
+	}
+
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "number of runs to show (0 = all)")
+	env := fs.String("env", "", "only show runs of this environment (MIGRATION_ENV)")
+	fs.Usage = func() {
+		fmt.Println("Usage: go-migration-app history [--limit N] [--env ENV]")
+		fmt.Println("       go-migration-app history diff <run-a> <run-b>")
+		fmt.Println()
+		fmt.Println("Runs are read from the migration_history table of HISTORY_DB.")
+		fmt.Println("A run can be given as its id, latest or latest:<env>.")
+	}
+	fs.Parse(args)
+
+	db, config, err := history.Open()
+	if err != nil {
+		logging.Fatal("Error opening run history", "error", err)
+	}
+	defer db.Close()
+
+	runs, err := history.List(db, *env, *limit)
+	if err != nil {
+		logging.Fatal("Error reading run history", "error", err)
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("cmd.history_title", config.HistoryDB))
+	fmt.Printf("------------------------\n")
+	if len(runs) == 0 {
+		fmt.Println(i18n.T("cmd.history_empty"))
+		return
+	}
+	fmt.Printf("%-16s %-8s %-22s %-19s %-9s %-10s %-8s %s\n", "RUN ID", "ENV", "STATUS", "STARTED", "DURATION", "PROCESSED", "FAILED", "BY")
+	for _, run := range runs {
+		duration := "-"
+		if run.FinishedAt.Valid {
+			duration = run.Duration().Round(time.Second).String()
+		}
+		fmt.Printf("%-16s %-8s %-22s %-19s %-9s %-10d %-8d %s@%s (%s)\n",
+			run.RunID, run.Environment, run.Status, run.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			duration, run.Processed(), run.Failed(), run.RunBy, run.Host, run.GitRevision)
+	}
+}
+
+func historyDiffCommand(args []string) {
+	if len(args) != 2 {
+		fmt.Println("Usage: go-migration-app history diff <run-a> <run-b>")
+		fmt.Println()
+		fmt.Println("A run can be given as its id, latest or latest:<env>, e.g. history diff latest:dev latest:prod")
+		os.Exit(2)
+	}
+
+	db, _, err := history.Open()
+	if err != nil {
+		logging.Fatal("Error opening run history", "error", err)
+	}
+	defer db.Close()
+
+	a, err := history.Find(db, args[0])
+	if err != nil {
+		logging.Fatal("Error finding run", "run", args[0], "error", err)
+	}
+	b, err := history.Find(db, args[1])
+	if err != nil {
+		logging.Fatal("Error finding run", "run", args[1], "error", err)
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("cmd.history_compare"))
+	fmt.Printf("------------------------\n")
+	for _, item := range []struct {
+		label string
+		run   *history.Run
+	}{{"A", a}, {"B", b}} {
+		run := item.run
+		fmt.Printf("%s: %s  env=%s  status=%s  git=%s  by=%s@%s  duration=%s\n", item.label, run.RunID,
+			run.Environment, run.Status, run.GitRevision, run.RunBy, run.Host, run.Duration().Round(time.Second))
+	}
+	for _, name := range optionNames(a, b) {
+		if a.Options[name] != b.Options[name] {
+			fmt.Printf("option %s: A=%q B=%q\n", name, a.Options[name], b.Options[name])
+		}
+	}
+
+	changes := history.Diff(a, b)
+	if len(changes) == 0 {
+		fmt.Printf("\n%s\n", i18n.T("cmd.history_no_diff"))
+		return
+	}
+
+	fmt.Printf("\n%-22s %-34s %12s %12s %10s\n", "MIGRATION", "VALUE", "A", "B", "B-A")
+	for _, c := range changes {
+		delta := ""
+		if c.Name != "status" {
+			delta = fmt.Sprintf("%+d", c.Delta)
+		}
+		fmt.Printf("%-22s %-34s %12s %12s %10s\n", c.Migration, c.Name, c.A, c.B, delta)
+	}
+}
+
+// optionNames mengembalikan nama opsi kedua run, terurut
+func optionNames(a, b *history.Run) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, run := range []*history.Run{a, b} {
+		for name := range run.Options {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// notifyTestCommand mengirim contoh event supaya webhook bisa dicoba tanpa menjalankan migrasi
+func notifyTestCommand(args []string) {
+	fs := flag.NewFlagSet("notify-test", flag.ExitOnError)
+	webhook := fs.String("webhook", notify.WebhookURL(), "generic JSON webhook")
+	slack := fs.String("slack", notify.SlackURL(), "Slack-compatible incoming webhook")
+	event := fs.String("event", notify.EventCompleted, "event to send: started, completed, failed or error_threshold")
+	fs.Parse(args)
+
+	os.Setenv("NOTIFY_WEBHOOK_URL", *webhook)
+	os.Setenv("NOTIFY_SLACK_WEBHOOK_URL", *slack)
+	if !notify.Enabled() {
+		fmt.Println(i18n.T("cmd.notify_none"))
+		os.Exit(2)
+	}
+
+	startedAt := time.Now().Add(-90 * time.Minute)
+	finishedAt := time.Now()
+	sample := notify.Event{
+		Event:      *event,
+		RunID:      report.RunID(),
+		Status:     report.StatusCompletedWithErrors,
+		StartedAt:  startedAt,
+		FinishedAt: &finishedAt,
+		DurationMs: finishedAt.Sub(startedAt).Milliseconds(),
+		Report:     report.RunPath(report.RunID()),
+		Migrations: []notify.Migration{
+			{Name: "user", Status: "done", DurationMs: 3_600_000, Processed: 12000, Failed: 3, Errors: 3},
+			{Name: "package", Status: "failed", DurationMs: 1_800_000, Processed: 450, Failed: 0, Errors: 1, Error: "exit status 1"},
+		},
+	}
+	switch *event {
+	case notify.EventStarted:
+		sample.Status, sample.FinishedAt, sample.DurationMs = "", nil, 0
+	case notify.EventErrorThreshold:
+		sample.Status, sample.FinishedAt, sample.DurationMs = "", nil, 0
+		sample.Migration, sample.ErrorCount, sample.Threshold = "user", 50, 50
+		sample.Migrations = nil
+		sample.Report = report.Path(report.RunID(), "user")
+	}
+
+	notify.Send(sample)
+	fmt.Println(i18n.T("cmd.notify_sent", *event))
+}
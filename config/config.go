@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/joho/godotenv"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -36,10 +38,28 @@ type Config struct {
 	ProdExistingDBPort     string
 	ProdExistingDBUser     string
 	ProdExistingDBPassword string
+
+	// Pembatasan beban baca ke prod existing (opsional)
+	ProdExistingReplicaDSN           string
+	ProdExistingMaxRowsPerSecond     int
+	ProdExistingMaxConcurrentQueries int
+	ProdExistingStatementTimeout     time.Duration
+	ProdExistingSnapshotID           string
+
+	// Batas koneksi per database untuk satu proses (opsional, 0 = tanpa batas)
+	DBMaxOpenConns int
+
+	// Database legacy hasil generate-legacy (opsional)
+	LocalLegacyDBName string
+
+	// Riwayat run: database target tempat tabel migration_history dan label environment run ini
+	HistoryDB   string
+	Environment string
 }
 
 func LoadConfig() (Config, error) {
-	if err := godotenv.Load(); err != nil {
+	err := godotenv.Load()
+	if err != nil {
 		return Config{}, fmt.Errorf("error loading .env file: %v", err)
 	}
 
@@ -73,6 +93,14 @@ func LoadConfig() (Config, error) {
 		ProdExistingDBPort:     os.Getenv("PROD_EXISTING_DB_PORT"),
 		ProdExistingDBUser:     os.Getenv("PROD_EXISTING_DB_USER"),
 		ProdExistingDBPassword: os.Getenv("PROD_EXISTING_DB_PASSWORD"),
+
+		ProdExistingReplicaDSN: os.Getenv("PROD_EXISTING_DB_REPLICA_DSN"),
+		ProdExistingSnapshotID: os.Getenv("PROD_EXISTING_DB_SNAPSHOT_ID"),
+
+		LocalLegacyDBName: os.Getenv("LOCAL_LEGACY_DB_NAME"),
+
+		HistoryDB:   os.Getenv("HISTORY_DB"),
+		Environment: os.Getenv("MIGRATION_ENV"),
 	}
 
 	// Validasi konfigurasi
@@ -83,5 +111,55 @@ func LoadConfig() (Config, error) {
 		return Config{}, fmt.Errorf("missing required environment variables")
 	}
 
+	if config.ProdExistingMaxRowsPerSecond, err = intEnv("PROD_EXISTING_DB_MAX_ROWS_PER_SECOND"); err != nil {
+		return Config{}, err
+	}
+	if config.ProdExistingMaxConcurrentQueries, err = intEnv("PROD_EXISTING_DB_MAX_CONCURRENT_QUERIES"); err != nil {
+		return Config{}, err
+	}
+	if config.ProdExistingStatementTimeout, err = durationEnv("PROD_EXISTING_DB_STATEMENT_TIMEOUT"); err != nil {
+		return Config{}, err
+	}
+
+	if config.DBMaxOpenConns, err = intEnv("DB_MAX_OPEN_CONNS"); err != nil {
+		return Config{}, err
+	}
+
+	if config.LocalLegacyDBName == "" {
+		config.LocalLegacyDBName = "umrah_legacy"
+	}
+	if config.HistoryDB == "" {
+		config.HistoryDB = "local-identity"
+	}
+	if config.Environment == "" {
+		config.Environment = "local"
+	}
+
 	return config, nil
 }
+
+// intEnv membaca env var angka opsional, kosong berarti 0
+func intEnv(key string) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return n, nil
+}
+
+// durationEnv membaca env var durasi opsional (contoh: 30s, 2m), kosong berarti 0
+func durationEnv(key string) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return d, nil
+}
@@ -4,51 +4,69 @@ import (
 	"database/sql"
 	"fmt"
 	configApp "github.com/ApesJs/go-migration-app/config"
-	"log"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/lib/pq"
+	"strings"
+	"time"
 )
 
-func ConnectionLocalIdentityDB() *sql.DB {
+// OpenLocalIdentityDB sama dengan ConnectionLocalIdentityDB tetapi mengembalikan error, bukan log.Fatal
+func OpenLocalIdentityDB() (*sql.DB, error) {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		return nil, fmt.Errorf("failed to load configuration: %v", err)
 	}
 
 	localIdentityConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.LocalDBHost, config.LocalDBPort, config.LocalDBUser, config.LocalDBPassword, config.LocalIdentityDBName)
 
-	LocalIdentityDB, err := sql.Open("postgres", localIdentityConnStr)
+	LocalIdentityDB, err := openDB(localIdentityConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to local identity database:", err)
+		return nil, fmt.Errorf("error connecting to local identity database: %v", err)
 	}
 
+	limitOpenConns(LocalIdentityDB, config)
+
 	if err := LocalIdentityDB.Ping(); err != nil {
-		log.Fatal("Error connecting to local identity database:", err)
+		LocalIdentityDB.Close()
+		return nil, fmt.Errorf("error connecting to local identity database: %v", err)
 	}
 
-	fmt.Println("Successfully connected to local identity databases")
+	fmt.Println(i18n.T("db.connected", "local identity"))
 
-	return LocalIdentityDB
+	return LocalIdentityDB, nil
+}
+
+func ConnectionLocalIdentityDB() *sql.DB {
+	db, err := OpenLocalIdentityDB()
+	if err != nil {
+		logging.Fatal("Database connection failed", "error", err)
+	}
+	return db
 }
 
 func ConnectionLocalUmrahDB() *sql.DB {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		logging.Fatal("Failed to load configuration", "error", err)
 	}
 
 	localUmrahConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.LocalDBHost, config.LocalDBPort, config.LocalDBUser, config.LocalDBPassword, config.LocalUmrahDBName)
 
-	LocalUmrahDB, err := sql.Open("postgres", localUmrahConnStr)
+	LocalUmrahDB, err := openDB(localUmrahConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to local umrah database:", err)
+		logging.Fatal("Error connecting to local umrah database", "error", err)
 	}
 
+	limitOpenConns(LocalUmrahDB, config)
+
 	if err := LocalUmrahDB.Ping(); err != nil {
-		log.Fatal("Error connecting to local umrah database:", err)
+		logging.Fatal("Error connecting to local umrah database", "error", err)
 	}
 
-	fmt.Println("Successfully connected to local umrah databases")
+	fmt.Println(i18n.T("db.connected", "local umrah"))
 
 	return LocalUmrahDB
 }
@@ -56,22 +74,24 @@ func ConnectionLocalUmrahDB() *sql.DB {
 func ConnectionLocalGeneralDB() *sql.DB {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		logging.Fatal("Failed to load configuration", "error", err)
 	}
 
 	localGeneralConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.LocalDBHost, config.LocalDBPort, config.LocalDBUser, config.LocalDBPassword, config.LocalGeneralDBName)
 
-	LocalGeneralDB, err := sql.Open("postgres", localGeneralConnStr)
+	LocalGeneralDB, err := openDB(localGeneralConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to local general database:", err)
+		logging.Fatal("Error connecting to local general database", "error", err)
 	}
 
+	limitOpenConns(LocalGeneralDB, config)
+
 	if err := LocalGeneralDB.Ping(); err != nil {
-		log.Fatal("Error connecting to local general database:", err)
+		logging.Fatal("Error connecting to local general database", "error", err)
 	}
 
-	fmt.Println("Successfully connected to local general databases")
+	fmt.Println(i18n.T("db.connected", "local general"))
 
 	return LocalGeneralDB
 }
@@ -80,139 +100,313 @@ func ConnectionDevIdentityDB() *sql.DB {
 	// Load konfigurasi dari file .env
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration ConnectionDevIdentityDB: %v", err)
+		logging.Fatal("Failed to load configuration ConnectionDevIdentityDB", "error", err)
 	}
 
 	// Koneksi ke database sumber dan target (kode koneksi tetap sama)
 	devIdentityConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.DevDBHost, config.DevDBPort, config.DevDBUser, config.DevDBPassword, config.DevIdentityDBName)
 
-	devIdentityDB, err := sql.Open("postgres", devIdentityConnStr)
+	devIdentityDB, err := openDB(devIdentityConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to dev identity database:", err)
+		logging.Fatal("Error connecting to dev identity database", "error", err)
 	}
 
 	// Test koneksi kedua database
+	limitOpenConns(devIdentityDB, config)
+
 	if err := devIdentityDB.Ping(); err != nil {
-		log.Fatal("Error connecting to dev identity database:", err)
+		logging.Fatal("Error connecting to dev identity database", "error", err)
 	}
 
-	fmt.Println("Successfully connected to dev identity databases")
+	fmt.Println(i18n.T("db.connected", "dev identity"))
 
 	return devIdentityDB
 }
 
-func ConnectionDevUmrahDB() *sql.DB {
+// OpenDevUmrahDB sama dengan ConnectionDevUmrahDB tetapi mengembalikan error, bukan log.Fatal
+func OpenDevUmrahDB() (*sql.DB, error) {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration ConnectionDevUmrahDB: %v", err)
+		return nil, fmt.Errorf("failed to load configuration: %v", err)
 	}
 
 	devUmrahConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.DevDBHost, config.DevDBPort, config.DevDBUser, config.DevDBPassword, config.DevUmrahDBName)
 
-	devUmrahDB, err := sql.Open("postgres", devUmrahConnStr)
+	devUmrahDB, err := openDB(devUmrahConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to dev umrah database:", err)
+		return nil, fmt.Errorf("error connecting to dev umrah database: %v", err)
 	}
 
+	limitOpenConns(devUmrahDB, config)
+
 	if err := devUmrahDB.Ping(); err != nil {
-		log.Fatal("Error connecting to dev umrah database:", err)
+		devUmrahDB.Close()
+		return nil, fmt.Errorf("error connecting to dev umrah database: %v", err)
 	}
 
-	fmt.Println("Successfully connected to dev umrah databases")
+	fmt.Println(i18n.T("db.connected", "dev umrah"))
 
-	return devUmrahDB
+	return devUmrahDB, nil
 }
 
-func ConnectionDevGeneralDB() *sql.DB {
+func ConnectionDevUmrahDB() *sql.DB {
+	db, err := OpenDevUmrahDB()
+	if err != nil {
+		logging.Fatal("Database connection failed", "error", err)
+	}
+	return db
+}
+
+// OpenDevGeneralDB sama dengan ConnectionDevGeneralDB tetapi mengembalikan error, bukan log.Fatal
+func OpenDevGeneralDB() (*sql.DB, error) {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		return nil, fmt.Errorf("failed to load configuration: %v", err)
 	}
 
 	devGeneralConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.DevDBHost, config.DevDBPort, config.DevDBUser, config.DevDBPassword, config.DevGeneralDBName)
 
-	devGeneralDB, err := sql.Open("postgres", devGeneralConnStr)
+	devGeneralDB, err := openDB(devGeneralConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to dev general database:", err)
+		return nil, fmt.Errorf("error connecting to dev general database: %v", err)
 	}
 
+	limitOpenConns(devGeneralDB, config)
+
 	if err := devGeneralDB.Ping(); err != nil {
-		log.Fatal("Error connecting to dev general database:", err)
+		devGeneralDB.Close()
+		return nil, fmt.Errorf("error connecting to dev general database: %v", err)
 	}
 
-	fmt.Println("Successfully connected to dev general databases")
+	fmt.Println(i18n.T("db.connected", "dev general"))
 
-	return devGeneralDB
+	return devGeneralDB, nil
 }
 
-func ConnectionProdExistingUmrahDB() *sql.DB {
+func ConnectionDevGeneralDB() *sql.DB {
+	db, err := OpenDevGeneralDB()
+	if err != nil {
+		logging.Fatal("Database connection failed", "error", err)
+	}
+	return db
+}
+
+// OpenProdExistingUmrahDB sama dengan ConnectionProdExistingUmrahDB tetapi mengembalikan error, bukan log.Fatal
+func OpenProdExistingUmrahDB() (*sql.DB, error) {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration ConnectionProdExistingUmrahDB: %v", err)
+		return nil, fmt.Errorf("failed to load configuration: %v", err)
 	}
 
 	prodExistingUmrahConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.ProdExistingDBHost, config.ProdExistingDBPort, config.ProdExistingDBUser, config.ProdExistingDBPassword, config.ProdExistingDBName)
 
-	prodExistingUmrahDB, err := sql.Open("postgres", prodExistingUmrahConnStr)
+	// Baca dari replica jika tersedia agar tidak membebani database produksi
+	databaseLabel := "prod existing umrah"
+	if config.ProdExistingReplicaDSN != "" {
+		prodExistingUmrahConnStr = config.ProdExistingReplicaDSN
+		databaseLabel = "prod existing umrah replica"
+	}
+
+	prodExistingUmrahDB, snapshot, err := OpenSourceDB(prodExistingUmrahConnStr, SourceOptions{
+		MaxRowsPerSecond:     config.ProdExistingMaxRowsPerSecond,
+		MaxConcurrentQueries: config.ProdExistingMaxConcurrentQueries,
+		StatementTimeout:     config.ProdExistingStatementTimeout,
+		SnapshotID:           config.ProdExistingSnapshotID,
+	})
 	if err != nil {
-		log.Fatal("Error connecting to prod existing umrah database:", err)
+		return nil, fmt.Errorf("error connecting to %s database: %v", databaseLabel, err)
 	}
 
+	limitOpenConns(prodExistingUmrahDB, config)
+
 	if err := prodExistingUmrahDB.Ping(); err != nil {
-		log.Fatal("Error connecting to prod existing umrah database:", err)
+		prodExistingUmrahDB.Close()
+		return nil, fmt.Errorf("error connecting to %s database: %v", databaseLabel, err)
 	}
 
-	fmt.Println("Successfully connected to prod existing umrah databases")
+	fmt.Println(i18n.T("db.connected_readonly", databaseLabel))
+	fmt.Println(i18n.T("db.snapshot", snapshot.ID, snapshot.LSN, snapshot.TakenAt.Format(time.RFC3339)))
 
-	return prodExistingUmrahDB
+	return prodExistingUmrahDB, nil
 }
 
-func ConnectionProdIdentityDB() *sql.DB {
+func ConnectionProdExistingUmrahDB() *sql.DB {
+	db, err := OpenProdExistingUmrahDB()
+	if err != nil {
+		logging.Fatal("Database connection failed", "error", err)
+	}
+	return db
+}
+
+// OpenProdIdentityDB sama dengan ConnectionProdIdentityDB tetapi mengembalikan error, bukan log.Fatal
+func OpenProdIdentityDB() (*sql.DB, error) {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		return nil, fmt.Errorf("failed to load configuration: %v", err)
 	}
 
 	prodIdentityConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.ProdDBHost, config.ProdDBPort, config.ProdDBUser, config.ProdDBPassword, config.ProdIdentityDBName)
 
-	prodIdentityDB, err := sql.Open("postgres", prodIdentityConnStr)
+	prodIdentityDB, err := openDB(prodIdentityConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to prod identity database:", err)
+		return nil, fmt.Errorf("error connecting to prod identity database: %v", err)
 	}
 
+	limitOpenConns(prodIdentityDB, config)
+
 	if err := prodIdentityDB.Ping(); err != nil {
-		log.Fatal("Error connecting to prod identity database:", err)
+		prodIdentityDB.Close()
+		return nil, fmt.Errorf("error connecting to prod identity database: %v", err)
 	}
 
-	fmt.Println("Successfully connected to prod identity databases")
+	fmt.Println(i18n.T("db.connected", "prod identity"))
 
-	return prodIdentityDB
+	return prodIdentityDB, nil
+}
+
+func ConnectionProdIdentityDB() *sql.DB {
+	db, err := OpenProdIdentityDB()
+	if err != nil {
+		logging.Fatal("Database connection failed", "error", err)
+	}
+	return db
 }
 
 func ConnectionProdUmrahDB() *sql.DB {
 	config, err := configApp.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		logging.Fatal("Failed to load configuration", "error", err)
 	}
 
 	prodUmrahConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.ProdDBHost, config.ProdDBPort, config.ProdDBUser, config.ProdDBPassword, config.ProdUmrahDBName)
 
-	prodUmrahDB, err := sql.Open("postgres", prodUmrahConnStr)
+	prodUmrahDB, err := openDB(prodUmrahConnStr)
 	if err != nil {
-		log.Fatal("Error connecting to prod umrah database:", err)
+		logging.Fatal("Error connecting to prod umrah database", "error", err)
 	}
 
+	limitOpenConns(prodUmrahDB, config)
+
 	if err := prodUmrahDB.Ping(); err != nil {
-		log.Fatal("Error connecting to prod umrah database:", err)
+		logging.Fatal("Error connecting to prod umrah database", "error", err)
 	}
 
-	fmt.Println("Successfully connected to prod umrah databases")
+	fmt.Println(i18n.T("db.connected", "prod umrah"))
 
 	return prodUmrahDB
 }
+
+func ConnectionLocalLegacyDB() *sql.DB {
+	config, err := configApp.LoadConfig()
+	if err != nil {
+		logging.Fatal("Failed to load configuration ConnectionLocalLegacyDB", "error", err)
+	}
+
+	localLegacyConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		config.LocalDBHost, config.LocalDBPort, config.LocalDBUser, config.LocalDBPassword, config.LocalLegacyDBName)
+
+	localLegacyDB, err := openDB(localLegacyConnStr)
+	if err != nil {
+		logging.Fatal("Error connecting to local legacy database", "error", err)
+	}
+
+	limitOpenConns(localLegacyDB, config)
+
+	if err := localLegacyDB.Ping(); err != nil {
+		logging.Fatal("Error connecting to local legacy database", "error", err)
+	}
+
+	fmt.Println(i18n.T("db.connected", "local legacy"))
+
+	return localLegacyDB
+}
+
+// CreateLocalLegacyDB membuat database legacy lokal jika belum ada.
+func CreateLocalLegacyDB() {
+	config, err := configApp.LoadConfig()
+	if err != nil {
+		logging.Fatal("Failed to load configuration CreateLocalLegacyDB", "error", err)
+	}
+
+	maintenanceConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=postgres sslmode=disable",
+		config.LocalDBHost, config.LocalDBPort, config.LocalDBUser, config.LocalDBPassword)
+
+	maintenanceDB, err := openDB(maintenanceConnStr)
+	if err != nil {
+		logging.Fatal("Error connecting to local postgres database", "error", err)
+	}
+	defer maintenanceDB.Close()
+
+	var exists bool
+	err = maintenanceDB.QueryRow(`SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)`, config.LocalLegacyDBName).Scan(&exists)
+	if err != nil {
+		logging.Fatal("Error checking local legacy database", "error", err)
+	}
+
+	if exists {
+		return
+	}
+
+	_, err = maintenanceDB.Exec(fmt.Sprintf(`CREATE DATABASE %s`, pq.QuoteIdentifier(config.LocalLegacyDBName)))
+	if err != nil {
+		logging.Fatal("Error creating local legacy database", "error", err)
+	}
+
+	fmt.Println(i18n.T("db.created_legacy", config.LocalLegacyDBName))
+}
+
+// OpenTargetDB membuka database target berdasarkan nama (local-identity, dev-umrah, prod-general, ...).
+// Berbeda dengan fungsi Connection*, error dikembalikan supaya pemanggil bisa tetap jalan tanpa database ini.
+func OpenTargetDB(config configApp.Config, name string) (*sql.DB, error) {
+	var host, port, user, password string
+	switch {
+	case strings.HasPrefix(name, "local-"):
+		host, port, user, password = config.LocalDBHost, config.LocalDBPort, config.LocalDBUser, config.LocalDBPassword
+	case strings.HasPrefix(name, "dev-"):
+		host, port, user, password = config.DevDBHost, config.DevDBPort, config.DevDBUser, config.DevDBPassword
+	case strings.HasPrefix(name, "prod-"):
+		host, port, user, password = config.ProdDBHost, config.ProdDBPort, config.ProdDBUser, config.ProdDBPassword
+	}
+
+	dbNames := map[string]string{
+		"local-identity": config.LocalIdentityDBName,
+		"local-umrah":    config.LocalUmrahDBName,
+		"local-general":  config.LocalGeneralDBName,
+		"dev-identity":   config.DevIdentityDBName,
+		"dev-umrah":      config.DevUmrahDBName,
+		"dev-general":    config.DevGeneralDBName,
+		"prod-identity":  config.ProdIdentityDBName,
+		"prod-umrah":     config.ProdUmrahDBName,
+		"prod-general":   config.ProdGeneralDBName,
+	}
+	dbName, ok := dbNames[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown target database %q", name)
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		host, port, user, password, dbName)
+	db, err := openDB(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s database: %v", name, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error connecting to %s database: %v", name, err)
+	}
+	return db, nil
+}
+
+// limitOpenConns membatasi jumlah koneksi per database, diisi oleh runner
+// saat beberapa migrasi berjalan bersamaan (DB_MAX_OPEN_CONNS)
+func limitOpenConns(db *sql.DB, config configApp.Config) {
+	if config.DBMaxOpenConns > 0 {
+		db.SetMaxOpenConns(config.DBMaxOpenConns)
+	}
+}
@@ -0,0 +1,407 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/lib/pq"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SourceOptions membatasi beban baca ke database sumber (prod existing)
+type SourceOptions struct {
+	MaxRowsPerSecond     int           // 0 = tanpa batas
+	MaxConcurrentQueries int           // 0 = tanpa batas
+	StatementTimeout     time.Duration // 0 = ikut setting server
+	SnapshotID           string        // snapshot dari proses lain, kosong = export snapshot baru
+}
+
+// SourceSnapshot adalah snapshot REPEATABLE READ yang dipakai semua baca sumber dalam satu run
+type SourceSnapshot struct {
+	ID       string    // hasil pg_export_snapshot(), bisa dipakai worker lain via PROD_EXISTING_DB_SNAPSHOT_ID
+	LSN      string    // posisi WAL saat snapshot diambil
+	TakenAt  time.Time // waktu mulai transaksi snapshot
+	Imported bool      // true jika snapshot berasal dari SnapshotID
+}
+
+// Transaksi pemegang snapshot dibiarkan terbuka sampai proses selesai, karena snapshot
+// hanya bisa di-import selama transaksi yang meng-export-nya masih terbuka.
+var (
+	snapshotMu       sync.Mutex
+	sourceSnapshot   *SourceSnapshot
+	snapshotHolder   *sql.Conn
+	snapshotHolderDB *sql.DB
+)
+
+// CurrentSourceSnapshot mengembalikan snapshot sumber run ini, nil jika belum ada koneksi sumber
+func CurrentSourceSnapshot() *SourceSnapshot {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+	return sourceSnapshot
+}
+
+// ReleaseSourceSnapshot menutup transaksi pemegang snapshot. Koneksi sumber yang
+// dibuka setelahnya meng-export snapshot baru, dipakai proses yang berjalan lama
+// (misalnya monitor) supaya setiap putaran membaca data terbaru. Koneksi sumber
+// yang masih terbuka harus ditutup dulu.
+func ReleaseSourceSnapshot() {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	if snapshotHolder != nil {
+		snapshotHolder.ExecContext(context.Background(), `ROLLBACK`)
+		snapshotHolder.Close()
+		snapshotHolderDB.Close()
+	}
+	sourceSnapshot = nil
+	snapshotHolder = nil
+	snapshotHolderDB = nil
+}
+
+// OpenSourceDB membuka koneksi sumber yang selalu read-only, dengan statement_timeout
+// per statement, dan membatasi jumlah query bersamaan serta baris per detik.
+// Setiap koneksi di pool berjalan di dalam snapshot REPEATABLE READ yang sama,
+// sehingga count dan data yang dibaca di pass berbeda tetap konsisten.
+func OpenSourceDB(connStr string, opts SourceOptions) (*sql.DB, *SourceSnapshot, error) {
+	dsn, err := sourceDSN(connStr, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot, err := shareSnapshot(connector, opts.SnapshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	throttled := &sourceConnector{connector: connector, snapshotID: snapshot.ID}
+	if opts.MaxRowsPerSecond > 0 {
+		throttled.limiter = &rowLimiter{interval: time.Second / time.Duration(opts.MaxRowsPerSecond)}
+	}
+	if opts.MaxConcurrentQueries > 0 {
+		throttled.querySlots = make(chan struct{}, opts.MaxConcurrentQueries)
+	}
+
+	return sql.OpenDB(traced(throttled, dsn)), snapshot, nil
+}
+
+// shareSnapshot meng-export snapshot sekali per proses. Pemanggilan berikutnya
+// (misalnya service yang membuka koneksi sumber dua kali) memakai snapshot yang sama.
+func shareSnapshot(connector *pq.Connector, importID string) (*SourceSnapshot, error) {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	if sourceSnapshot != nil {
+		return sourceSnapshot, nil
+	}
+
+	ctx := context.Background()
+	holderDB := sql.OpenDB(connector)
+	holderDB.SetMaxOpenConns(1)
+
+	conn, err := holderDB.Conn(ctx)
+	if err != nil {
+		holderDB.Close()
+		return nil, fmt.Errorf("error opening snapshot connection: %v", err)
+	}
+
+	fail := func(step string, err error) (*SourceSnapshot, error) {
+		conn.Close()
+		holderDB.Close()
+		return nil, fmt.Errorf("error %s: %v", step, err)
+	}
+
+	if _, err := conn.ExecContext(ctx, `BEGIN ISOLATION LEVEL REPEATABLE READ READ ONLY`); err != nil {
+		return fail("starting snapshot transaction", err)
+	}
+	if importID != "" {
+		if _, err := conn.ExecContext(ctx, `SET TRANSACTION SNAPSHOT `+pq.QuoteLiteral(importID)); err != nil {
+			return fail("importing snapshot "+importID, err)
+		}
+	}
+
+	// Snapshot di-export ulang walaupun hasil import, supaya koneksi pool tidak
+	// bergantung pada transaksi proses lain yang bisa selesai lebih dulu
+	snapshot := &SourceSnapshot{Imported: importID != ""}
+	err = conn.QueryRowContext(ctx, `
+		SELECT pg_export_snapshot(),
+		       (CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END)::text,
+		       now()
+	`).Scan(&snapshot.ID, &snapshot.LSN, &snapshot.TakenAt)
+	if err != nil {
+		return fail("exporting snapshot", err)
+	}
+
+	sourceSnapshot = snapshot
+	snapshotHolder = conn
+	snapshotHolderDB = holderDB
+
+	return snapshot, nil
+}
+
+// sourceDSN menambahkan parameter sesi ke connection string (format key=value atau URL)
+func sourceDSN(connStr string, opts SourceOptions) (string, error) {
+	if strings.HasPrefix(connStr, "postgres://") || strings.HasPrefix(connStr, "postgresql://") {
+		converted, err := pq.ParseURL(connStr)
+		if err != nil {
+			return "", fmt.Errorf("error parsing source DSN: %v", err)
+		}
+		connStr = converted
+	}
+
+	params := []string{
+		connStr,
+		"default_transaction_read_only=on",
+		"application_name=go-migration-app",
+	}
+	if opts.StatementTimeout > 0 {
+		params = append(params, fmt.Sprintf("statement_timeout=%d", opts.StatementTimeout.Milliseconds()))
+	}
+
+	return strings.Join(params, " "), nil
+}
+
+// rowLimiter membagi baris secara merata per interval, tanpa menabung burst
+type rowLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func (l *rowLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+type sourceConnector struct {
+	connector  *pq.Connector
+	limiter    *rowLimiter
+	querySlots chan struct{}
+	snapshotID string
+}
+
+// Connect membuka koneksi baru dan langsung masuk ke transaksi snapshot run ini.
+// Transaksi tersebut tidak pernah di-commit selama koneksi hidup.
+func (c *sourceConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execer := conn.(driver.ExecerContext)
+	queries := []string{
+		`BEGIN ISOLATION LEVEL REPEATABLE READ READ ONLY`,
+		`SET TRANSACTION SNAPSHOT ` + pq.QuoteLiteral(c.snapshotID),
+	}
+	for _, query := range queries {
+		if _, err := execer.ExecContext(ctx, query, nil); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error joining source snapshot %s: %v", c.snapshotID, err)
+		}
+	}
+
+	return &sourceConn{conn: conn, source: c}, nil
+}
+
+func (c *sourceConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// acquire menahan slot hanya selama query dieksekusi sampai hasil pertama diterima.
+// Slot tidak ditahan selama rows diiterasi, karena service sering menjalankan
+// query lookup di dalam loop rows dan akan deadlock jika slot ditahan.
+func (c *sourceConnector) acquire(ctx context.Context) (func(), error) {
+	if c.querySlots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.querySlots <- struct{}{}:
+		return func() { <-c.querySlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type sourceConn struct {
+	conn   driver.Conn
+	source *sourceConnector
+
+	// aborted true jika ada error di dalam transaksi snapshot. PostgreSQL menolak
+	// query berikutnya di transaksi yang gagal, jadi koneksi harus dibuang dari pool.
+	aborted bool
+}
+
+func (c *sourceConn) track(err error) error {
+	if err != nil && err != io.EOF {
+		c.aborted = true
+	}
+	return err
+}
+
+func (c *sourceConn) wrapRows(rows driver.Rows) driver.Rows {
+	return &sourceRows{Rows: rows, conn: c}
+}
+
+func (c *sourceConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sourceConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sourceStmt{stmt: stmt, conn: c}, nil
+}
+
+func (c *sourceConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *sourceConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx tidak membuka transaksi baru karena koneksi sudah berada di transaksi
+// snapshot, COMMIT/ROLLBACK asli akan mengakhiri snapshot tersebut
+func (c *sourceConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return snapshotTx{}, nil
+}
+
+func (c *sourceConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	release, err := c.source.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := c.conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	observeQuery(start)
+	release()
+	if err != nil {
+		return nil, c.track(err)
+	}
+	return c.wrapRows(rows), nil
+}
+
+func (c *sourceConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	release, err := c.source.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	result, err := c.conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	return result, c.track(err)
+}
+
+func (c *sourceConn) Ping(ctx context.Context) error {
+	return c.conn.(driver.Pinger).Ping(ctx)
+}
+
+func (c *sourceConn) ResetSession(ctx context.Context) error {
+	if c.aborted {
+		return driver.ErrBadConn
+	}
+	return c.conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+func (c *sourceConn) IsValid() bool {
+	return !c.aborted && c.conn.(driver.Validator).IsValid()
+}
+
+type snapshotTx struct{}
+
+func (snapshotTx) Commit() error   { return nil }
+func (snapshotTx) Rollback() error { return nil }
+
+type sourceStmt struct {
+	stmt driver.Stmt
+	conn *sourceConn
+}
+
+func (s *sourceStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *sourceStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *sourceStmt) Exec(args []driver.Value) (driver.Result, error) {
+	result, err := s.stmt.Exec(args)
+	return result, s.conn.track(err)
+}
+
+func (s *sourceStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, err := s.stmt.Query(args)
+	if err != nil {
+		return nil, s.conn.track(err)
+	}
+	return s.conn.wrapRows(rows), nil
+}
+
+func (s *sourceStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	release, err := s.conn.source.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	rows, err := s.stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	observeQuery(start)
+	release()
+	if err != nil {
+		return nil, s.conn.track(err)
+	}
+	return s.conn.wrapRows(rows), nil
+}
+
+func (s *sourceStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	release, err := s.conn.source.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	result, err := s.stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	return result, s.conn.track(err)
+}
+
+// sourceRows menunggu giliran dari rowLimiter sebelum membaca setiap baris
+// dan menandai koneksi jika pembacaan baris gagal
+type sourceRows struct {
+	driver.Rows
+	conn *sourceConn
+}
+
+func (r *sourceRows) Next(dest []driver.Value) error {
+	if r.conn.source.limiter != nil {
+		r.conn.source.limiter.wait()
+	}
+	err := r.Rows.Next(dest)
+	if err == nil {
+		metrics.Add("migration_source_rows_read_total", "Rows read from the legacy source database.", 1)
+	}
+	return r.conn.track(err)
+}
+
+// observeQuery mencatat latency query dan lookup ke sumber, termasuk QueryRow per record
+func observeQuery(start time.Time) {
+	metrics.Observe("migration_source_query_duration_seconds", "Latency of queries and lookups against the legacy source database.", time.Since(start))
+}
@@ -0,0 +1,264 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/ApesJs/go-migration-app/tracing"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// maxStatementLength membatasi db.statement di span supaya query panjang tidak membesarkan trace
+const maxStatementLength = 2000
+
+// openDB membuka database postgres. Saat tracing aktif koneksinya dibungkus
+// sehingga setiap query, exec dan commit menjadi span.
+func openDB(connStr string) (*sql.DB, error) {
+	if !tracing.Enabled() {
+		return sql.Open("postgres", connStr)
+	}
+	connector, err := pq.NewConnector(connStr)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(traced(connector, connStr)), nil
+}
+
+// traced membungkus connector dengan span SQL, atau mengembalikannya apa adanya jika tracing mati
+func traced(connector driver.Connector, connStr string) driver.Connector {
+	if !tracing.Enabled() {
+		return connector
+	}
+	return &tracedConnector{connector: connector, dbName: dsnName(connStr)}
+}
+
+// dsnName mengambil dbname dari connection string format key=value
+func dsnName(connStr string) string {
+	for _, field := range strings.Fields(connStr) {
+		if name, ok := strings.CutPrefix(field, "dbname="); ok {
+			return strings.Trim(name, "'")
+		}
+	}
+	return ""
+}
+
+type tracedConnector struct {
+	connector driver.Connector
+	dbName    string
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{conn: conn, dbName: c.dbName}, nil
+}
+
+func (c *tracedConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+type tracedConn struct {
+	conn   driver.Conn
+	dbName string
+}
+
+// span membuka span client untuk satu statement, diberi nama "<operasi> <database>"
+// seperti konvensi OpenTelemetry, misalnya "SELECT umrah". Parent-nya span di ctx,
+// atau batch progress yang berjalan jika service memanggil tanpa context.
+func (c *tracedConn) span(ctx context.Context, query string) *tracing.Span {
+	statement := strings.Join(strings.Fields(query), " ")
+	operation, _, _ := strings.Cut(statement, " ")
+	operation = strings.ToUpper(operation)
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength] + "..."
+	}
+	_, span := tracing.StartClient(ctx, strings.TrimSpace(operation+" "+c.dbName),
+		"db.system", "postgresql",
+		"db.name", c.dbName,
+		"db.operation", operation,
+		"db.statement", statement,
+	)
+	return span
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// COPY (generate-legacy) mengirim satu Exec per baris, terlalu banyak untuk dijadikan span
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "COPY") {
+		return stmt, nil
+	}
+	return &tracedStmt{stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *tracedConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *tracedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var (
+		tx  driver.Tx
+		err error
+	)
+	if beginner, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.conn.Begin()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedTx{tx: tx, conn: c, ctx: ctx}, nil
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	span := c.span(ctx, query)
+	defer span.End()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	span.SetError(err)
+	return rows, err
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	span := c.span(ctx, query)
+	defer span.End()
+	result, err := execer.ExecContext(ctx, query, args)
+	span.SetError(err)
+	return result, err
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+type tracedTx struct {
+	tx   driver.Tx
+	conn *tracedConn
+	ctx  context.Context // context BeginTx, parent span commit dan rollback
+}
+
+func (t *tracedTx) Commit() error {
+	span := t.conn.span(t.ctx, "COMMIT")
+	defer span.End()
+	err := t.tx.Commit()
+	span.SetError(err)
+	return err
+}
+
+func (t *tracedTx) Rollback() error {
+	span := t.conn.span(t.ctx, "ROLLBACK")
+	defer span.End()
+	err := t.tx.Rollback()
+	span.SetError(err)
+	return err
+}
+
+// tracedStmt menjadikan setiap eksekusi prepared statement satu span,
+// termasuk lookup QueryRow per record di dalam loop service
+type tracedStmt struct {
+	stmt  driver.Stmt
+	conn  *tracedConn
+	query string
+}
+
+func (s *tracedStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *tracedStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *tracedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	span := s.conn.span(context.Background(), s.query)
+	defer span.End()
+	result, err := s.stmt.Exec(args)
+	span.SetError(err)
+	return result, err
+}
+
+func (s *tracedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	span := s.conn.span(context.Background(), s.query)
+	defer span.End()
+	rows, err := s.stmt.Query(args)
+	span.SetError(err)
+	return rows, err
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.stmt.(driver.StmtExecContext)
+	if !ok {
+		return s.Exec(values(args))
+	}
+	span := s.conn.span(ctx, s.query)
+	defer span.End()
+	result, err := execer.ExecContext(ctx, args)
+	span.SetError(err)
+	return result, err
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.stmt.(driver.StmtQueryContext)
+	if !ok {
+		return s.Query(values(args))
+	}
+	span := s.conn.span(ctx, s.query)
+	defer span.End()
+	rows, err := queryer.QueryContext(ctx, args)
+	span.SetError(err)
+	return rows, err
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	converted := make([]driver.Value, len(args))
+	for i, arg := range args {
+		converted[i] = arg.Value
+	}
+	return converted
+}
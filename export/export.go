@@ -0,0 +1,118 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// List adalah satu daftar yang diekspor ke file dengan header tetap
+type List struct {
+	Name    string // nama file tanpa ekstensi
+	Columns []string
+}
+
+// Satu proses menjalankan satu migrasi, jadi baris disimpan di level package
+// seperti ledger dan report
+var (
+	mu        sync.Mutex
+	migration string
+	lists     []List
+	rows      = make(map[string][][]string)
+)
+
+// Dir mengembalikan folder export dari MIGRATION_EXPORT_DIR, kosong berarti export mati
+func Dir() string {
+	return os.Getenv("MIGRATION_EXPORT_DIR")
+}
+
+// XLSX menandakan daftar juga ditulis sebagai .xlsx selain .csv
+func XLSX() bool {
+	return os.Getenv("MIGRATION_EXPORT_XLSX") == "true"
+}
+
+// Start mulai mengumpulkan daftar migrasi name
+func Start(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	migration = name
+	lists = nil
+	rows = make(map[string][][]string)
+}
+
+// Add menambah satu baris ke daftar. Tidak melakukan apa-apa jika export mati.
+func (l List) Add(values ...string) {
+	if Dir() == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := rows[l.Name]; !ok {
+		lists = append(lists, l)
+	}
+	row := make([]string, len(l.Columns))
+	copy(row, values)
+	rows[l.Name] = append(rows[l.Name], row)
+}
+
+// Close menulis setiap daftar yang berisi ke <Dir>/<migration>/<list>.csv dan
+// mengembalikan file yang ditulis. File dari run sebelumnya dihapus dulu supaya
+// daftar yang sekarang kosong tidak tertinggal.
+func Close() ([]string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if Dir() == "" || migration == "" {
+		return nil, nil
+	}
+
+	dir := filepath.Join(Dir(), migration)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("error clearing export directory: %v", err)
+	}
+	if len(lists) == 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating export directory: %v", err)
+	}
+
+	var files []string
+	for _, l := range lists {
+		path := filepath.Join(dir, l.Name+".csv")
+		if err := writeCSV(path, l.Columns, rows[l.Name]); err != nil {
+			return files, err
+		}
+		files = append(files, path)
+
+		if XLSX() {
+			path = filepath.Join(dir, l.Name+".xlsx")
+			if err := writeXLSX(path, l.Name, l.Columns, rows[l.Name]); err != nil {
+				return files, err
+			}
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+func writeCSV(path string, columns []string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write(columns)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
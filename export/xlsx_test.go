@@ -0,0 +1,149 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestColumn(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := column(tt.index); got != tt.want {
+			t.Errorf("column(%d) = %s, want %s", tt.index, got, tt.want)
+		}
+	}
+}
+
+// sheetDoc dan workbookDoc adalah bagian file xlsx yang dibaca kembali oleh test
+type sheetDoc struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R    string `xml:"r,attr"`
+			Type string `xml:"t,attr"`
+			Text string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type workbookDoc struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+func TestWriteXLSX(t *testing.T) {
+	tests := []struct {
+		name      string
+		sheet     string
+		columns   []string
+		rows      [][]string
+		wantSheet string
+	}{
+		{
+			name:      "header and rows",
+			sheet:     "duplicate_phones",
+			columns:   []string{"user_id", "phone"},
+			rows:      [][]string{{"u1", "+62811"}, {"u2", ""}},
+			wantSheet: "duplicate_phones",
+		},
+		{
+			name:      "values are XML escaped",
+			sheet:     "generated & slugs",
+			columns:   []string{"name"},
+			rows:      [][]string{{`<Travel> "A" & B`}, {"  spaces kept  "}},
+			wantSheet: "generated & slugs",
+		},
+		{
+			name:      "sheet name is cut to 31 characters",
+			sheet:     "organization_instance_missing_slugs",
+			columns:   []string{"id"},
+			rows:      nil,
+			wantSheet: "organization_instance_missing_s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "list.xlsx")
+			if err := writeXLSX(path, tt.sheet, tt.columns, tt.rows); err != nil {
+				t.Fatalf("writeXLSX() error = %v", err)
+			}
+
+			z, err := zip.OpenReader(path)
+			if err != nil {
+				t.Fatalf("not a zip file: %v", err)
+			}
+			defer z.Close()
+
+			parts := make(map[string]string)
+			for _, f := range z.File {
+				r, err := f.Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				data, _ := io.ReadAll(r)
+				r.Close()
+				parts[f.Name] = string(data)
+			}
+			for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+				if _, ok := parts[name]; !ok {
+					t.Errorf("missing part %s", name)
+				}
+			}
+
+			var workbook workbookDoc
+			if err := xml.Unmarshal([]byte(parts["xl/workbook.xml"]), &workbook); err != nil {
+				t.Fatalf("workbook.xml: %v", err)
+			}
+			if len(workbook.Sheets) != 1 || workbook.Sheets[0].Name != tt.wantSheet {
+				t.Errorf("sheets = %+v, want one named %q", workbook.Sheets, tt.wantSheet)
+			}
+
+			var sheet sheetDoc
+			if err := xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet); err != nil {
+				t.Fatalf("sheet1.xml: %v", err)
+			}
+			want := append([][]string{tt.columns}, tt.rows...)
+			var got [][]string
+			for i, row := range sheet.Rows {
+				if row.R != i+1 {
+					t.Errorf("row %d has r=%d", i, row.R)
+				}
+				var values []string
+				for j, cell := range row.Cells {
+					if ref := fmt.Sprintf("%s%d", column(j), i+1); cell.R != ref {
+						t.Errorf("cell r = %s, want %s", cell.R, ref)
+					}
+					if cell.Type != "inlineStr" {
+						t.Errorf("cell %s has t=%s, want inlineStr", cell.R, cell.Type)
+					}
+					values = append(values, cell.Text)
+				}
+				got = append(got, values)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("sheet rows = %q, want %q", got, want)
+			}
+		})
+	}
+}
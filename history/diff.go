@@ -0,0 +1,96 @@
+package history
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Change adalah satu nilai yang berbeda antara dua run. Delta hanya diisi
+// untuk nilai angka, status migrasi yang berbeda punya Delta 0.
+type Change struct {
+	Migration string
+	Name      string
+	A         string
+	B         string
+	Delta     int
+}
+
+// Diff membandingkan hitungan setiap migrasi di run a dan b. Migrasi yang
+// hanya ada di salah satu run dibandingkan dengan nilai kosong.
+func Diff(a, b *Run) []Change {
+	var changes []Change
+	for _, name := range migrationNames(a, b) {
+		ma, okA := a.Migrations[name]
+		mb, okB := b.Migrations[name]
+
+		statusA, statusB := ma.Status, mb.Status
+		if !okA {
+			statusA = "-"
+		}
+		if !okB {
+			statusB = "-"
+		}
+		if statusA != statusB {
+			changes = append(changes, Change{Migration: name, Name: "status", A: statusA, B: statusB})
+		}
+
+		valuesA, valuesB := values(ma), values(mb)
+		for _, key := range keys(valuesA, valuesB) {
+			if valuesA[key] != valuesB[key] {
+				changes = append(changes, numberChange(name, key, valuesA[key], valuesB[key]))
+			}
+		}
+	}
+	return changes
+}
+
+func numberChange(migration, name string, a, b int) Change {
+	return Change{Migration: migration, Name: name, A: strconv.Itoa(a), B: strconv.Itoa(b), Delta: b - a}
+}
+
+// values meratakan hitungan satu migrasi, counter diberi awalan supaya tidak bentrok dengan outcome
+func values(m Migration) map[string]int {
+	v := map[string]int{
+		"duplicates":    m.Duplicates,
+		"placeholders":  m.Placeholders,
+		"errors":        m.Errors,
+		"lossy_changes": m.LossyChanges,
+	}
+	for name, n := range m.Outcomes {
+		v[name] = n
+	}
+	for name, n := range m.Counters {
+		v["counter:"+name] = n
+	}
+	return v
+}
+
+func migrationNames(a, b *Run) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, run := range []*Run{a, b} {
+		for name := range run.Migrations {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func keys(a, b map[string]int) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range []map[string]int{a, b} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,70 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b map[string]Migration
+		want []Change
+	}{
+		{
+			name: "identical runs",
+			a:    map[string]Migration{"user": {Status: "done", Outcomes: map[string]int{"transferred": 10}}},
+			b:    map[string]Migration{"user": {Status: "done", Outcomes: map[string]int{"transferred": 10}}},
+			want: nil,
+		},
+		{
+			name: "outcome and error counts",
+			a:    map[string]Migration{"user": {Status: "done", Outcomes: map[string]int{"transferred": 10, "skipped": 2}, Errors: 1}},
+			b:    map[string]Migration{"user": {Status: "done", Outcomes: map[string]int{"transferred": 12}, Errors: 1}},
+			want: []Change{
+				{Migration: "user", Name: "skipped", A: "2", B: "0", Delta: -2},
+				{Migration: "user", Name: "transferred", A: "10", B: "12", Delta: 2},
+			},
+		},
+		{
+			name: "status change has no delta",
+			a:    map[string]Migration{"package": {Status: "done"}},
+			b:    map[string]Migration{"package": {Status: "failed"}},
+			want: []Change{{Migration: "package", Name: "status", A: "done", B: "failed"}},
+		},
+		{
+			name: "counters are prefixed so they cannot clash with outcomes",
+			a:    map[string]Migration{"user": {Status: "done", Outcomes: map[string]int{"converted": 1}}},
+			b:    map[string]Migration{"user": {Status: "done", Outcomes: map[string]int{"converted": 1}, Counters: map[string]int{"converted": 3}}},
+			want: []Change{{Migration: "user", Name: "counter:converted", A: "0", B: "3", Delta: 3}},
+		},
+		{
+			name: "migration missing from one run",
+			a:    map[string]Migration{},
+			b:    map[string]Migration{"hotel": {Status: "done", Duplicates: 4, LossyChanges: 1}},
+			want: []Change{
+				{Migration: "hotel", Name: "status", A: "-", B: "done"},
+				{Migration: "hotel", Name: "duplicates", A: "0", B: "4", Delta: 4},
+				{Migration: "hotel", Name: "lossy_changes", A: "0", B: "1", Delta: 1},
+			},
+		},
+		{
+			name: "migrations are compared in name order",
+			a:    map[string]Migration{"user": {Status: "done", Placeholders: 1}, "airline": {Status: "done", Placeholders: 1}},
+			b:    map[string]Migration{"user": {Status: "done"}, "airline": {Status: "done"}},
+			want: []Change{
+				{Migration: "airline", Name: "placeholders", A: "1", B: "0", Delta: -1},
+				{Migration: "user", Name: "placeholders", A: "1", B: "0", Delta: -1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(&Run{Migrations: tt.a}, &Run{Migrations: tt.b})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
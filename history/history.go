@@ -0,0 +1,280 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	configApp "github.com/ApesJs/go-migration-app/config"
+	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/report"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+)
+
+// StatusRunning adalah status run yang belum selesai. Run yang berhenti paksa
+// (Ctrl+C, proses mati) tetap berstatus running.
+const StatusRunning = "running"
+
+const createTable = `
+	CREATE TABLE IF NOT EXISTS migration_history (
+		run_id       TEXT PRIMARY KEY,
+		run_by       TEXT NOT NULL,
+		host         TEXT NOT NULL,
+		environment  TEXT NOT NULL,
+		git_revision TEXT NOT NULL,
+		options      JSONB NOT NULL,
+		started_at   TIMESTAMPTZ NOT NULL,
+		finished_at  TIMESTAMPTZ,
+		status       TEXT NOT NULL,
+		migrations   JSONB NOT NULL DEFAULT '{}'
+	)
+`
+
+const selectColumns = `run_id, run_by, host, environment, git_revision, options, started_at, finished_at, status, migrations`
+
+// Migration adalah hasil satu migrasi dalam satu run
+type Migration struct {
+	Status       string         `json:"status"`
+	DurationMs   int64          `json:"duration_ms"`
+	Outcomes     map[string]int `json:"outcomes,omitempty"`
+	Counters     map[string]int `json:"counters,omitempty"`
+	Duplicates   int            `json:"duplicates"`
+	Placeholders int            `json:"placeholders"`
+	Errors       int            `json:"errors"`
+	LossyChanges int            `json:"lossy_changes"`
+}
+
+// Run adalah satu baris migration_history
+type Run struct {
+	RunID       string
+	RunBy       string
+	Host        string
+	Environment string
+	GitRevision string
+	Options     map[string]string
+	StartedAt   time.Time
+	FinishedAt  sql.NullTime
+	Status      string
+	Migrations  map[string]Migration
+}
+
+// Processed adalah jumlah semua outcome di semua migrasi
+func (r *Run) Processed() int {
+	processed := 0
+	for _, m := range r.Migrations {
+		for _, n := range m.Outcomes {
+			processed += n
+		}
+	}
+	return processed
+}
+
+// Failed adalah jumlah outcome failed di semua migrasi
+func (r *Run) Failed() int {
+	failed := 0
+	for _, m := range r.Migrations {
+		failed += m.Outcomes[report.OutcomeFailed]
+	}
+	return failed
+}
+
+// Duration mengembalikan durasi run, nol jika belum selesai
+func (r *Run) Duration() time.Duration {
+	if !r.FinishedAt.Valid {
+		return 0
+	}
+	return r.FinishedAt.Time.Sub(r.StartedAt)
+}
+
+// Open membuka database HISTORY_DB dan membuat tabel migration_history jika belum ada
+func Open() (*sql.DB, configApp.Config, error) {
+	config, err := configApp.LoadConfig()
+	if err != nil {
+		return nil, config, err
+	}
+	db, err := database.OpenTargetDB(config, config.HistoryDB)
+	if err != nil {
+		return nil, config, err
+	}
+	if _, err := db.Exec(createTable); err != nil {
+		db.Close()
+		return nil, config, fmt.Errorf("error creating migration_history: %v", err)
+	}
+	return db, config, nil
+}
+
+// Recorder mencatat satu command run. Semua method aman dipanggil dengan
+// Recorder nil, sehingga run tetap jalan jika database riwayat tidak tersedia.
+type Recorder struct {
+	db  *sql.DB
+	run *Run
+}
+
+// Begin menyimpan run dengan status running
+func Begin(runID string, startedAt time.Time, options map[string]string) (*Recorder, error) {
+	db, config, err := Open()
+	if err != nil {
+		return nil, err
+	}
+
+	run := &Run{
+		RunID:       runID,
+		RunBy:       currentUser(),
+		Host:        hostname(),
+		Environment: config.Environment,
+		GitRevision: report.GitVersion(),
+		Options:     options,
+		StartedAt:   startedAt,
+		Status:      StatusRunning,
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO migration_history (run_id, run_by, host, environment, git_revision, options, started_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, run.RunID, run.RunBy, run.Host, run.Environment, run.GitRevision, string(optionsJSON), run.StartedAt, run.Status)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error inserting into migration_history: %v", err)
+	}
+	return &Recorder{db: db, run: run}, nil
+}
+
+// Finish menyimpan waktu selesai, status akhir dan hitungan setiap migrasi dari run report
+func (r *Recorder) Finish(run *report.Run) error {
+	if r == nil {
+		return nil
+	}
+	defer r.db.Close()
+
+	migrations := make(map[string]Migration)
+	for _, result := range run.Migrations {
+		m := Migration{Status: result.Status, DurationMs: result.DurationMs}
+		if result.Report != "" {
+			if rep, err := report.Load(report.Path(run.RunID, result.Name)); err == nil {
+				m = fromReport(m, rep)
+			}
+		}
+		migrations[result.Name] = m
+	}
+	migrationsJSON, err := json.Marshal(migrations)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE migration_history SET finished_at = $2, status = $3, migrations = $4
+		WHERE run_id = $1
+	`, r.run.RunID, run.FinishedAt, run.Status, string(migrationsJSON))
+	if err != nil {
+		return fmt.Errorf("error updating migration_history: %v", err)
+	}
+	return nil
+}
+
+func fromReport(m Migration, r *report.Report) Migration {
+	m.Outcomes = make(map[string]int)
+	for _, c := range r.Outcomes {
+		m.Outcomes[c.Name] = c.Count
+	}
+	m.Counters = make(map[string]int)
+	for _, c := range r.Counters {
+		m.Counters[c.Name] = c.Count
+	}
+	m.Duplicates = len(r.Duplicates)
+	m.Placeholders = len(r.Placeholders)
+	m.Errors = r.ErrorCount
+	m.LossyChanges = r.LossyChanges
+	return m
+}
+
+// List mengembalikan run terbaru lebih dulu, environment kosong berarti semua
+func List(db *sql.DB, environment string, limit int) ([]*Run, error) {
+	query := `SELECT ` + selectColumns + ` FROM migration_history`
+	var args []interface{}
+	if environment != "" {
+		query += ` WHERE environment = $1`
+		args = append(args, environment)
+	}
+	query += ` ORDER BY started_at DESC`
+	if limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*Run
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// Find mencari run berdasarkan id, "latest" atau "latest:<environment>"
+func Find(db *sql.DB, ref string) (*Run, error) {
+	var row *sql.Row
+	switch {
+	case ref == "latest":
+		row = db.QueryRow(`SELECT ` + selectColumns + ` FROM migration_history ORDER BY started_at DESC LIMIT 1`)
+	case strings.HasPrefix(ref, "latest:"):
+		row = db.QueryRow(`SELECT `+selectColumns+` FROM migration_history WHERE environment = $1 ORDER BY started_at DESC LIMIT 1`, strings.TrimPrefix(ref, "latest:"))
+	default:
+		row = db.QueryRow(`SELECT `+selectColumns+` FROM migration_history WHERE run_id = $1`, ref)
+	}
+
+	run, err := scanRun(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("run %s not found in migration_history", ref)
+	}
+	return run, err
+}
+
+func scanRun(row interface{ Scan(...interface{}) error }) (*Run, error) {
+	var (
+		run                         Run
+		optionsJSON, migrationsJSON []byte
+	)
+	err := row.Scan(&run.RunID, &run.RunBy, &run.Host, &run.Environment, &run.GitRevision,
+		&optionsJSON, &run.StartedAt, &run.FinishedAt, &run.Status, &migrationsJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(optionsJSON, &run.Options); err != nil {
+		return nil, fmt.Errorf("error parsing options of run %s: %v", run.RunID, err)
+	}
+	if err := json.Unmarshal(migrationsJSON, &run.Migrations); err != nil {
+		return nil, fmt.Errorf("error parsing migrations of run %s: %v", run.RunID, err)
+	}
+	return &run, nil
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+func hostname() string {
+	if host, err := os.Hostname(); err == nil {
+		return host
+	}
+	return "unknown"
+}
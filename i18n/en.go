@@ -0,0 +1,254 @@
+package i18n
+
+// en adalah katalog bahasa Inggris, sekaligus fallback untuk key yang belum diterjemahkan
+var en = map[string]string{
+	// Migrations
+	"transfer.found":             "Found %d total records to transfer",
+	"transfer.completed":         "Transfer completed!",
+	"processing.completed":       "Processing completed!",
+	"user.found_wukala":          "Found %d wukala in source database",
+	"user.created_wukala_role":   "Created 'wukala' role",
+	"make_uc.found":              "Found %d users without credentials",
+	"make_uc.none":               "No users need credentials. Exiting...",
+	"bdm_persona.found":          "Found %d BDM users to process",
+	"wukala_persona.start":       "Starting wukala persona transfer...",
+	"wukala_persona.total":       "Wukala to process: %d",
+	"wukala_persona.completed":   "Wukala persona transfer completed!",
+	"user_persona.start":         "Starting user persona transfer...",
+	"user_persona.total":         "Users to process: %d",
+	"user_persona.completed":     "Persona transfer completed!",
+	"package.found":              "Found %d total packages to transfer",
+	"standardize.completed":      "City name standardization completed!",
+	"standardize.mecca":          "Standardized %d Mecca hotel records",
+	"standardize.madinah":        "Standardized %d Madinah hotel records",
+	"package.thumbnails":         "Updated %d package thumbnail URLs",
+	"package.variant_thumbnails": "Updated %d variant thumbnail URLs",
+	"hotel.phase_package":        "Phase 1: Migrating Hotels from Package Table",
+	"hotel.found_package":        "Found %d total hotels to transfer from package",
+	"hotel.phase_td_hotel":       "Phase 2: Migrating Hotels from td_hotel Table",
+	"hotel.found_td_hotel":       "Found %d hotels to transfer from td_hotel",
+	"airport.phase_indo":         "Phase 1: Migrating Airports from JSON",
+	"airport.phase_provinces":    "Phase 1.1: Migrating Provinces from JSON",
+	"airport.phase_cities":       "Phase 1.2: Migrating Cities from JSON",
+	"airport.phase_arab":         "Phase 1.3: Migrating Airports from JSON",
+	"airport.found":              "Found %d airports to transfer",
+	"airport.found_provinces":    "Found %d provinces to transfer",
+	"airport.found_cities":       "Found %d cities to transfer",
+
+	// Migration summary
+	"summary.title":        "Migration Summary",
+	"summary.total":        "Total records: %d",
+	"summary.writes":       "Writes:",
+	"summary.write":        "inserted %d, updated %d",
+	"summary.phases":       "Phases:",
+	"summary.phase":        "%d records  %.2f records/second",
+	"summary.duration":     "Duration: %s",
+	"summary.speed":        "Average speed: %.2f records/second",
+	"summary.lossy":        "Lossy changes recorded: %d",
+	"summary.duplicates":   "Duplicates",
+	"summary.placeholders": "Placeholders",
+	"summary.errors":       "Errors (%d):",
+	"summary.more_errors":  "... and %d more in the log",
+	"report.written":       "Report written to %s",
+	"report.partial":       "Run stopped before its summary, partial report written to %s",
+
+	// Outcome and counter labels
+	"label.transferred":                      "Transferred",
+	"label.inserted":                         "Inserted",
+	"label.updated":                          "Updated",
+	"label.skipped":                          "Skipped",
+	"label.failed":                           "Failed",
+	"label.converted_to_wukala":              "Converted to wukala",
+	"label.airlines_in_mapping":              "Airlines in mapping",
+	"label.departure_references_updated":     "Departure references updated",
+	"label.arrival_references_updated":       "Arrival references updated",
+	"label.unmatched_departure_packages":     "Unmatched departure packages",
+	"label.unmatched_arrival_packages":       "Unmatched arrival packages",
+	"label.update_errors":                    "Update errors",
+	"label.duplicate_phones_cleared":         "Duplicate phones cleared",
+	"label.hotels_in_mapping":                "Hotels in mapping",
+	"label.unmatched_madinah_hotel_packages": "Unmatched madinah hotel packages",
+	"label.unmatched_makkah_hotel_packages":  "Unmatched makkah hotel packages",
+	"label.travel_agents_in_source":          "Travel agents in source",
+	"label.standardized_mecca_hotels":        "Standardized mecca hotels",
+	"label.standardized_madinah_hotels":      "Standardized madinah hotels",
+
+	// Report list titles
+	"list.Skipped travel agents (already exist)":                "Skipped travel agents (already exist)",
+	"list.Generated slugs":                                      "Generated slugs",
+	"list.Unmatched Madinah hotels (no master hotel row)":       "Unmatched Madinah hotels (no master hotel row)",
+	"list.Unmatched Makkah hotels (no master hotel row)":        "Unmatched Makkah hotels (no master hotel row)",
+	"list.Unmatched Departure airlines (no master airline row)": "Unmatched Departure airlines (no master airline row)",
+	"list.Unmatched Arrival airlines (no master airline row)":   "Unmatched Arrival airlines (no master airline row)",
+
+	// Progress
+	"progress.done":    "done in %s",
+	"progress.overall": "overall",
+
+	// Runner
+	"runner.started": "started",
+	"runner.skipped": "skipped, dependency %s %s",
+	"runner.failed":  "failed after %s: %v",
+	"runner.done":    "done in %s",
+
+	// Database connections
+	"db.connected":          "Successfully connected to %s databases",
+	"db.connected_readonly": "Successfully connected to %s databases (read-only)",
+	"db.snapshot":           "Source snapshot %s (LSN %s, taken at %s)",
+	"db.created_legacy":     "Created local legacy database '%s'",
+
+	// Legacy dataset generator
+	"legacy.generating":       "Generating %d legacy rows (scale %d, seed %d, anomaly rate %.2f)",
+	"legacy.summary":          "Legacy Dataset Summary",
+	"legacy.anomalies":        "Injected Anomalies:",
+	"legacy.duplicate_emails": "Duplicate emails: %d",
+	"legacy.duplicate_phones": "Duplicate phones: %d",
+	"legacy.duplicate_codes":  "Duplicate referral codes: %d",
+	"legacy.nulls":            "NULL values: %d",
+	"legacy.overlong":         "Over-long values: %d",
+	"legacy.orphans":          "Orphan references: %d",
+	"legacy.speed":            "Average speed: %.2f rows/second",
+
+	// Shared by the check commands
+	"check.error":      "Error running check: %v",
+	"check.more":       "... and %d more (use --limit 0 or --export to see all)",
+	"check.more_limit": "... and %d more (use --limit 0 to show all)",
+	"check.failed":     "- Checks failed: %d",
+	"check.summary":    "Summary:",
+
+	// Reconcile
+	"reconcile.finished":      "Reconciliation finished in %s",
+	"reconcile.saved":         "Reconciliation saved to %s",
+	"reconcile.running":       "Reconciling %s (%s)...",
+	"reconcile.result":        "Reconciliation Result: %s",
+	"reconcile.key":           "Key: %s",
+	"reconcile.source":        "Source (%s): %d",
+	"reconcile.target":        "Target (%s): %d",
+	"reconcile.matched":       "Matched: %d",
+	"reconcile.missing":       "Missing in target: %d",
+	"reconcile.extra":         "Extra in target: %d",
+	"reconcile.duplicates":    "Duplicate keys: %d in source, %d in target",
+	"reconcile.missing_title": "Missing (in source but not in target)",
+	"reconcile.extra_title":   "Extra (in target but not in source)",
+	"reconcile.completed":     "Completed in: %s",
+
+	// Orphans
+	"orphan.title":      "Source Orphan Report:",
+	"orphan.checked":    "Checked: %d, orphans: %d (%d distinct missing ids)",
+	"orphan.item":       "%d. %s %s -> missing %s (%s)",
+	"orphan.references": "- References checked: %d",
+	"orphan.rows":       "- Orphan rows: %d",
+	"orphan.exported":   "Orphans exported to %s",
+	"orphan.finished":   "Report finished in %s",
+
+	// Audit
+	"audit.title":        "Audit Result:",
+	"audit.found":        "Found: %d",
+	"audit.summary":      "Audit Summary:",
+	"audit.placeholders": "- Placeholder rows: %d",
+	"audit.dangling":     "- Dangling references: %d",
+	"audit.exported":     "Findings exported to %s",
+	"audit.finished":     "Audit finished in %s",
+
+	// Inspect
+	"inspect.none":     "No %s record found",
+	"inspect.legacy":   "LEGACY (prod existing umrah)",
+	"inspect.migrated": "MIGRATED",
+
+	// Monitor
+	"monitor.listening":   "Drift status available at http://%s/status",
+	"monitor.check":       "Drift check at %s",
+	"monitor.next":        "Next check in %s",
+	"monitor.error":       "error: %s",
+	"monitor.line":        "source %d, target %d, missing %d, extra %d",
+	"monitor.new_missing": "(+%d not migrated since last check)",
+
+	// Verify and checksum
+	"checksum.running":      "Checking %s (%s)...",
+	"checksum.finished":     "Checksum finished in %s",
+	"checksum.flagged":      "FLAGGED: money values changed in %v, review before go-live",
+	"checksum.ok":           "OK: all money fields match",
+	"checksum.result":       "Checksum Result: %s",
+	"checksum.labels":       "Source: %s, target: %s, organizations: %d",
+	"checksum.distribution": "Distribution (source/target rows per amount range):",
+	"checksum.all_match":    "All organization sums match",
+	"checksum.different":    "Organizations with different sums: %d",
+	"verify.running":        "Verifying %s (%s, %d fields)...",
+	"verify.finished":       "Verification finished in %s",
+	"verify.result":         "Verification Result: %s",
+	"verify.compared":       "Rows compared: %d",
+	"verify.identical":      "Identical: %d",
+	"verify.different":      "Different: %d",
+	"verify.only_source":    "Only in source: %d",
+	"verify.only_target":    "Only in target: %d",
+	"verify.duplicates":     "Duplicate keys skipped: %d in source, %d in target",
+	"verify.fields":         "Differing fields:",
+	"verify.field_rows":     "- %s: %d rows",
+	"verify.more_rows":      "... and %d more rows (use --limit 0 to show all)",
+	"verify.diff":           "%d. %s (source %s, target %s)",
+
+	// Invariants
+	"invariant.title":         "Invariant Check (%s):",
+	"invariant.violations_of": "Violations: %d of %d",
+	"invariant.violations":    "Violations: %d",
+	"invariant.more":          "... and %d more (use --limit 0 to see all)",
+	"invariant.checked":       "- Invariants checked: %d",
+	"invariant.passed":        "- Passed: %d",
+	"invariant.violated":      "- Violated: %d",
+	"invariant.finished":      "Check finished in %s",
+
+	// Profile
+	"profile.title":            "Legacy Data Profile:",
+	"profile.error":            "Error running profile: %v",
+	"profile.rows":             "Rows: %d, null: %d (%.1f%%), empty: %d (%.1f%%), distinct: %d",
+	"profile.truncated":        "-> %d values will be truncated",
+	"profile.max_length_limit": "Max length: %d (target limit %d)",
+	"profile.max_length":       "Max length: %d",
+	"profile.invalid":          "Invalid format: %d (%.1f%%), expected %s",
+	"profile.sample":           "%s %s: %q (%d chars)",
+	"profile.values":           "Values:",
+	"profile.distinct_total":   "... %d distinct values in total",
+	"profile.columns":          "- Columns profiled: %d",
+	"profile.flagged":          "- Columns with truncated or invalid values: %d",
+	"profile.failed":           "- Profiles failed: %d",
+	"profile.finished":         "Profile finished in %s",
+
+	// Commands
+	"cmd.unknown_command":      "Unknown command: %s",
+	"cmd.level":                "Level %d: %s",
+	"cmd.conns_too_low":        "--%s must be at least %d: a migration keeps a result set open while it queries the same database",
+	"cmd.metrics_listening":    "Metrics available at http://%s/metrics",
+	"cmd.history_not_recorded": "Warning: run history not recorded: %v",
+	"cmd.run_summary":          "Migration Summary:",
+	"cmd.total_duration":       "Total duration: %s",
+	"cmd.history_error":        "Error recording run history: %v",
+	"cmd.history_recorded":     "Run recorded in migration_history as %s",
+	"cmd.no_invariants":        "No %s found, skipping invariant check",
+	"cmd.invariants_violated":  "invariants violated",
+	"cmd.unknown_migration":    "Unknown migration: %s",
+	"cmd.export_error":         "Error writing exports: %v",
+	"cmd.exported":             "Exported %s",
+	"cmd.lossy_recorded":       "Recorded %d lossy changes in %s",
+	"cmd.export_format":        "--export must end with .csv or .json",
+	"cmd.run_report_error":     "Error writing run report: %v",
+	"cmd.run_report_written":   "Run report written to %s",
+	"cmd.run_metrics_error":    "Error writing run metrics: %v",
+	"cmd.lossy_title":          "Lossy changes (%s):",
+	"cmd.lossy_hint":           "Run 'ledger' for details or 'ledger --export changes.csv' for the full list",
+	"cmd.ledger_format":        "Unsupported export format %q, use .csv or .json",
+	"cmd.ledger_title":         "Lossy Transformation Ledger (%s):",
+	"cmd.ledger_events":        "%s events:",
+	"cmd.ledger_total":         "Total lossy changes: %d",
+	"cmd.ledger_exported":      "Ledger exported to %s",
+	"cmd.interval_positive":    "--interval must be positive",
+	"cmd.html_written":         "HTML report written to %s",
+	"cmd.history_title":        "Run History (%s):",
+	"cmd.history_empty":        "No runs recorded",
+	"cmd.history_compare":      "Run Comparison:",
+	"cmd.history_no_diff":      "No differences in counts",
+	"cmd.notify_none":          "No webhook configured, set NOTIFY_WEBHOOK_URL / NOTIFY_SLACK_WEBHOOK_URL or pass --webhook / --slack",
+	"cmd.notify_sent":          "Sent %s event (failures are logged as warnings)",
+	"cmd.trace_sent":           "Trace %s sent to %s",
+	"cmd.trace_written":        "Trace %s written to %s",
+	"cmd.migration_aborted":    "Migration %s stopped before finishing, marking it as failed",
+}
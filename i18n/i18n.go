@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Bahasa yang tersedia
+const (
+	EN = "en"
+	ID = "id"
+)
+
+// DefaultLang dipakai jika MIGRATION_LANG kosong atau tidak dikenal
+const DefaultLang = EN
+
+var catalogs = map[string]map[string]string{
+	EN: en,
+	ID: id,
+}
+
+// Lang mengembalikan bahasa console dari MIGRATION_LANG. Flag --lang mengisi
+// variabel ini di main supaya proses migrasi yang dijalankan run ikut memakainya.
+func Lang() string {
+	lang := strings.ToLower(os.Getenv("MIGRATION_LANG"))
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return DefaultLang
+}
+
+// Supported mengecek apakah bahasa ada katalognya
+func Supported(lang string) bool {
+	_, ok := catalogs[strings.ToLower(lang)]
+	return ok
+}
+
+// T mengembalikan pesan key dalam bahasa aktif, diformat dengan args seperti fmt.Sprintf.
+// Key yang tidak ada di bahasa aktif memakai teks bahasa Inggris, lalu key itu sendiri.
+func T(key string, args ...interface{}) string {
+	text, ok := catalogs[Lang()][key]
+	if !ok {
+		if text, ok = en[key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Has mengecek apakah key ada di katalog bahasa Inggris, untuk teks yang punya
+// fallback sendiri seperti label outcome
+func Has(key string) bool {
+	_, ok := en[key]
+	return ok
+}
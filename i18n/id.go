@@ -0,0 +1,254 @@
+package i18n
+
+// id adalah katalog bahasa Indonesia
+var id = map[string]string{
+	// Migrations
+	"transfer.found":             "Ditemukan %d record untuk ditransfer",
+	"transfer.completed":         "Transfer selesai!",
+	"processing.completed":       "Proses selesai!",
+	"user.found_wukala":          "Ditemukan %d wukala di database sumber",
+	"user.created_wukala_role":   "Role 'wukala' dibuat",
+	"make_uc.found":              "Ditemukan %d user tanpa kredensial",
+	"make_uc.none":               "Tidak ada user yang perlu kredensial. Selesai...",
+	"bdm_persona.found":          "Ditemukan %d user BDM untuk diproses",
+	"wukala_persona.start":       "Memulai proses transfer data persona wukala...",
+	"wukala_persona.total":       "Total wukala yang akan diproses: %d",
+	"wukala_persona.completed":   "Transfer data persona wukala selesai!",
+	"user_persona.start":         "Memulai proses transfer data persona user...",
+	"user_persona.total":         "Total user yang akan diproses: %d",
+	"user_persona.completed":     "Transfer data persona selesai!",
+	"package.found":              "Ditemukan %d paket untuk ditransfer",
+	"standardize.completed":      "Standardisasi nama kota selesai!",
+	"standardize.mecca":          "%d record hotel Makkah distandardisasi",
+	"standardize.madinah":        "%d record hotel Madinah distandardisasi",
+	"package.thumbnails":         "%d URL thumbnail paket diperbarui",
+	"package.variant_thumbnails": "%d URL thumbnail varian diperbarui",
+	"hotel.phase_package":        "Fase 1: Migrasi Hotel dari Tabel Package",
+	"hotel.found_package":        "Ditemukan %d hotel untuk ditransfer dari package",
+	"hotel.phase_td_hotel":       "Fase 2: Migrasi Hotel dari Tabel td_hotel",
+	"hotel.found_td_hotel":       "Ditemukan %d hotel untuk ditransfer dari td_hotel",
+	"airport.phase_indo":         "Fase 1: Migrasi Bandara dari JSON",
+	"airport.phase_provinces":    "Fase 1.1: Migrasi Provinsi dari JSON",
+	"airport.phase_cities":       "Fase 1.2: Migrasi Kota dari JSON",
+	"airport.phase_arab":         "Fase 1.3: Migrasi Bandara dari JSON",
+	"airport.found":              "Ditemukan %d bandara untuk ditransfer",
+	"airport.found_provinces":    "Ditemukan %d provinsi untuk ditransfer",
+	"airport.found_cities":       "Ditemukan %d kota untuk ditransfer",
+
+	// Migration summary
+	"summary.title":        "Ringkasan Migrasi",
+	"summary.total":        "Total record: %d",
+	"summary.writes":       "Penulisan:",
+	"summary.write":        "insert %d, update %d",
+	"summary.phases":       "Fase:",
+	"summary.phase":        "%d record  %.2f record/detik",
+	"summary.duration":     "Durasi: %s",
+	"summary.speed":        "Kecepatan rata-rata: %.2f record/detik",
+	"summary.lossy":        "Perubahan lossy tercatat: %d",
+	"summary.duplicates":   "Duplikat",
+	"summary.placeholders": "Placeholder",
+	"summary.errors":       "Error (%d):",
+	"summary.more_errors":  "... dan %d lainnya di log",
+	"report.written":       "Report ditulis ke %s",
+	"report.partial":       "Run berhenti sebelum ringkasan, report sebagian ditulis ke %s",
+
+	// Outcome and counter labels
+	"label.transferred":                      "Ditransfer",
+	"label.inserted":                         "Di-insert",
+	"label.updated":                          "Di-update",
+	"label.skipped":                          "Dilewati",
+	"label.failed":                           "Gagal",
+	"label.converted_to_wukala":              "Dikonversi ke wukala",
+	"label.airlines_in_mapping":              "Maskapai di mapping",
+	"label.departure_references_updated":     "Referensi keberangkatan diperbarui",
+	"label.arrival_references_updated":       "Referensi kedatangan diperbarui",
+	"label.unmatched_departure_packages":     "Paket keberangkatan tanpa pasangan",
+	"label.unmatched_arrival_packages":       "Paket kedatangan tanpa pasangan",
+	"label.update_errors":                    "Error update",
+	"label.duplicate_phones_cleared":         "Nomor telepon duplikat dikosongkan",
+	"label.hotels_in_mapping":                "Hotel di mapping",
+	"label.unmatched_madinah_hotel_packages": "Paket hotel Madinah tanpa pasangan",
+	"label.unmatched_makkah_hotel_packages":  "Paket hotel Makkah tanpa pasangan",
+	"label.travel_agents_in_source":          "Travel agent di sumber",
+	"label.standardized_mecca_hotels":        "Hotel Makkah distandardisasi",
+	"label.standardized_madinah_hotels":      "Hotel Madinah distandardisasi",
+
+	// Report list titles
+	"list.Skipped travel agents (already exist)":                "Travel agent dilewati (sudah ada)",
+	"list.Generated slugs":                                      "Slug yang di-generate",
+	"list.Unmatched Madinah hotels (no master hotel row)":       "Hotel Madinah tanpa pasangan (tidak ada di master hotel)",
+	"list.Unmatched Makkah hotels (no master hotel row)":        "Hotel Makkah tanpa pasangan (tidak ada di master hotel)",
+	"list.Unmatched Departure airlines (no master airline row)": "Maskapai keberangkatan tanpa pasangan (tidak ada di master maskapai)",
+	"list.Unmatched Arrival airlines (no master airline row)":   "Maskapai kedatangan tanpa pasangan (tidak ada di master maskapai)",
+
+	// Progress
+	"progress.done":    "selesai dalam %s",
+	"progress.overall": "keseluruhan",
+
+	// Runner
+	"runner.started": "dimulai",
+	"runner.skipped": "dilewati, dependensi %s %s",
+	"runner.failed":  "gagal setelah %s: %v",
+	"runner.done":    "selesai dalam %s",
+
+	// Database connections
+	"db.connected":          "Berhasil terhubung ke database %s",
+	"db.connected_readonly": "Berhasil terhubung ke database %s (read-only)",
+	"db.snapshot":           "Snapshot sumber %s (LSN %s, diambil %s)",
+	"db.created_legacy":     "Database legacy lokal '%s' dibuat",
+
+	// Legacy dataset generator
+	"legacy.generating":       "Membuat %d baris legacy (scale %d, seed %d, anomaly rate %.2f)",
+	"legacy.summary":          "Ringkasan Dataset Legacy",
+	"legacy.anomalies":        "Anomali yang Disisipkan:",
+	"legacy.duplicate_emails": "Email duplikat: %d",
+	"legacy.duplicate_phones": "Nomor telepon duplikat: %d",
+	"legacy.duplicate_codes":  "Kode referral duplikat: %d",
+	"legacy.nulls":            "Nilai NULL: %d",
+	"legacy.overlong":         "Nilai terlalu panjang: %d",
+	"legacy.orphans":          "Referensi yatim: %d",
+	"legacy.speed":            "Kecepatan rata-rata: %.2f baris/detik",
+
+	// Shared by the check commands
+	"check.error":      "Error saat menjalankan pengecekan: %v",
+	"check.more":       "... dan %d lainnya (pakai --limit 0 atau --export untuk melihat semua)",
+	"check.more_limit": "... dan %d lainnya (pakai --limit 0 untuk menampilkan semua)",
+	"check.failed":     "- Pengecekan gagal: %d",
+	"check.summary":    "Ringkasan:",
+
+	// Reconcile
+	"reconcile.finished":      "Rekonsiliasi selesai dalam %s",
+	"reconcile.saved":         "Hasil rekonsiliasi disimpan ke %s",
+	"reconcile.running":       "Merekonsiliasi %s (%s)...",
+	"reconcile.result":        "Hasil Rekonsiliasi: %s",
+	"reconcile.key":           "Key: %s",
+	"reconcile.source":        "Sumber (%s): %d",
+	"reconcile.target":        "Target (%s): %d",
+	"reconcile.matched":       "Cocok: %d",
+	"reconcile.missing":       "Tidak ada di target: %d",
+	"reconcile.extra":         "Lebih di target: %d",
+	"reconcile.duplicates":    "Key duplikat: %d di sumber, %d di target",
+	"reconcile.missing_title": "Hilang (ada di sumber, tidak ada di target)",
+	"reconcile.extra_title":   "Lebih (ada di target, tidak ada di sumber)",
+	"reconcile.completed":     "Selesai dalam: %s",
+
+	// Orphans
+	"orphan.title":      "Laporan Orphan Sumber:",
+	"orphan.checked":    "Dicek: %d, orphan: %d (%d id hilang yang berbeda)",
+	"orphan.item":       "%d. %s %s -> tidak ada %s (%s)",
+	"orphan.references": "- Referensi dicek: %d",
+	"orphan.rows":       "- Baris orphan: %d",
+	"orphan.exported":   "Orphan diekspor ke %s",
+	"orphan.finished":   "Laporan selesai dalam %s",
+
+	// Audit
+	"audit.title":        "Hasil Audit:",
+	"audit.found":        "Ditemukan: %d",
+	"audit.summary":      "Ringkasan Audit:",
+	"audit.placeholders": "- Baris placeholder: %d",
+	"audit.dangling":     "- Referensi menggantung: %d",
+	"audit.exported":     "Temuan diekspor ke %s",
+	"audit.finished":     "Audit selesai dalam %s",
+
+	// Inspect
+	"inspect.none":     "Record %s tidak ditemukan",
+	"inspect.legacy":   "LEGACY (prod existing umrah)",
+	"inspect.migrated": "HASIL MIGRASI",
+
+	// Monitor
+	"monitor.listening":   "Status drift tersedia di http://%s/status",
+	"monitor.check":       "Pengecekan drift pada %s",
+	"monitor.next":        "Pengecekan berikutnya dalam %s",
+	"monitor.error":       "error: %s",
+	"monitor.line":        "sumber %d, target %d, hilang %d, lebih %d",
+	"monitor.new_missing": "(+%d belum dimigrasi sejak pengecekan terakhir)",
+
+	// Verify and checksum
+	"checksum.running":      "Mengecek %s (%s)...",
+	"checksum.finished":     "Checksum selesai dalam %s",
+	"checksum.flagged":      "DITANDAI: nilai uang berubah di %v, periksa sebelum go-live",
+	"checksum.ok":           "OK: semua field uang cocok",
+	"checksum.result":       "Hasil Checksum: %s",
+	"checksum.labels":       "Sumber: %s, target: %s, organisasi: %d",
+	"checksum.distribution": "Distribusi (baris sumber/target per rentang nominal):",
+	"checksum.all_match":    "Semua jumlah per organisasi cocok",
+	"checksum.different":    "Organisasi dengan jumlah berbeda: %d",
+	"verify.running":        "Memverifikasi %s (%s, %d field)...",
+	"verify.finished":       "Verifikasi selesai dalam %s",
+	"verify.result":         "Hasil Verifikasi: %s",
+	"verify.compared":       "Baris dibandingkan: %d",
+	"verify.identical":      "Identik: %d",
+	"verify.different":      "Berbeda: %d",
+	"verify.only_source":    "Hanya di sumber: %d",
+	"verify.only_target":    "Hanya di target: %d",
+	"verify.duplicates":     "Key duplikat dilewati: %d di sumber, %d di target",
+	"verify.fields":         "Field yang berbeda:",
+	"verify.field_rows":     "- %s: %d baris",
+	"verify.more_rows":      "... dan %d baris lainnya (pakai --limit 0 untuk menampilkan semua)",
+	"verify.diff":           "%d. %s (sumber %s, target %s)",
+
+	// Invariants
+	"invariant.title":         "Pengecekan Invariant (%s):",
+	"invariant.violations_of": "Pelanggaran: %d dari %d",
+	"invariant.violations":    "Pelanggaran: %d",
+	"invariant.more":          "... dan %d lainnya (pakai --limit 0 untuk melihat semua)",
+	"invariant.checked":       "- Invariant dicek: %d",
+	"invariant.passed":        "- Lolos: %d",
+	"invariant.violated":      "- Dilanggar: %d",
+	"invariant.finished":      "Pengecekan selesai dalam %s",
+
+	// Profile
+	"profile.title":            "Profil Data Legacy:",
+	"profile.error":            "Error saat menjalankan profil: %v",
+	"profile.rows":             "Baris: %d, null: %d (%.1f%%), kosong: %d (%.1f%%), unik: %d",
+	"profile.truncated":        "-> %d nilai akan terpotong",
+	"profile.max_length_limit": "Panjang maksimum: %d (batas target %d)",
+	"profile.max_length":       "Panjang maksimum: %d",
+	"profile.invalid":          "Format tidak valid: %d (%.1f%%), seharusnya %s",
+	"profile.sample":           "%s %s: %q (%d karakter)",
+	"profile.values":           "Nilai:",
+	"profile.distinct_total":   "... total %d nilai unik",
+	"profile.columns":          "- Kolom diprofilkan: %d",
+	"profile.flagged":          "- Kolom dengan nilai terpotong atau tidak valid: %d",
+	"profile.failed":           "- Profil gagal: %d",
+	"profile.finished":         "Profil selesai dalam %s",
+
+	// Commands
+	"cmd.unknown_command":      "Command tidak dikenal: %s",
+	"cmd.level":                "Level %d: %s",
+	"cmd.conns_too_low":        "--%s minimal %d: migrasi membiarkan result set terbuka sambil menjalankan query lain ke database yang sama",
+	"cmd.metrics_listening":    "Metrik tersedia di http://%s/metrics",
+	"cmd.history_not_recorded": "Warning: riwayat run tidak dicatat: %v",
+	"cmd.run_summary":          "Ringkasan Migrasi:",
+	"cmd.total_duration":       "Total durasi: %s",
+	"cmd.history_error":        "Error saat mencatat riwayat run: %v",
+	"cmd.history_recorded":     "Run dicatat di migration_history sebagai %s",
+	"cmd.no_invariants":        "%s tidak ditemukan, pengecekan invariant dilewati",
+	"cmd.invariants_violated":  "invariant dilanggar",
+	"cmd.unknown_migration":    "Migrasi tidak dikenal: %s",
+	"cmd.export_error":         "Error saat menulis export: %v",
+	"cmd.exported":             "Diekspor %s",
+	"cmd.lossy_recorded":       "%d perubahan lossy dicatat di %s",
+	"cmd.export_format":        "--export harus berakhiran .csv atau .json",
+	"cmd.run_report_error":     "Error saat menulis run report: %v",
+	"cmd.run_report_written":   "Run report ditulis ke %s",
+	"cmd.run_metrics_error":    "Error saat menulis metrik run: %v",
+	"cmd.lossy_title":          "Perubahan lossy (%s):",
+	"cmd.lossy_hint":           "Jalankan 'ledger' untuk detail atau 'ledger --export changes.csv' untuk daftar lengkap",
+	"cmd.ledger_format":        "Format export %q tidak didukung, pakai .csv atau .json",
+	"cmd.ledger_title":         "Ledger Transformasi Lossy (%s):",
+	"cmd.ledger_events":        "Event %s:",
+	"cmd.ledger_total":         "Total perubahan lossy: %d",
+	"cmd.ledger_exported":      "Ledger diekspor ke %s",
+	"cmd.interval_positive":    "--interval harus positif",
+	"cmd.html_written":         "Report HTML ditulis ke %s",
+	"cmd.history_title":        "Riwayat Run (%s):",
+	"cmd.history_empty":        "Belum ada run yang dicatat",
+	"cmd.history_compare":      "Perbandingan Run:",
+	"cmd.history_no_diff":      "Tidak ada perbedaan hitungan",
+	"cmd.notify_none":          "Belum ada webhook, isi NOTIFY_WEBHOOK_URL / NOTIFY_SLACK_WEBHOOK_URL atau pakai --webhook / --slack",
+	"cmd.notify_sent":          "Event %s dikirim (kegagalan dicatat sebagai warning)",
+	"cmd.trace_sent":           "Trace %s dikirim ke %s",
+	"cmd.trace_written":        "Trace %s ditulis ke %s",
+	"cmd.migration_aborted":    "Migrasi %s berhenti sebelum selesai, ditandai gagal",
+}
@@ -0,0 +1,168 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Alasan perubahan data yang dicatat
+const (
+	ReasonTruncated = "truncated" // dipotong ke panjang kolom target, atau pecahannya dibuang saat dijadikan bilangan bulat
+	ReasonDuplicate = "duplicate" // dikosongkan karena nilainya sudah dipakai record lain
+	ReasonRounded   = "rounded"   // dibulatkan ke bilangan bulat terdekat
+)
+
+// DefaultDir adalah folder ledger jika LEDGER_DIR tidak diisi
+const DefaultDir = "ledger"
+
+// Event adalah satu perubahan data yang tidak bisa dikembalikan (lossy)
+type Event struct {
+	Migration string `json:"migration"`
+	Entity    string `json:"entity"`
+	SourceID  string `json:"source_id"`
+	Field     string `json:"field"`
+	Original  string `json:"original"`
+	New       string `json:"new"`
+	Reason    string `json:"reason"`
+}
+
+var (
+	mu        sync.Mutex
+	file      *os.File
+	encoder   *json.Encoder
+	migration string
+	recorded  int
+)
+
+// Dir mengembalikan folder ledger dari LEDGER_DIR
+func Dir() string {
+	if dir := os.Getenv("LEDGER_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultDir
+}
+
+// Open mulai mencatat event migrasi ke <Dir>/<name>.jsonl. Isi file sebelumnya
+// dihapus, jadi ledger selalu berisi hasil run terakhir migrasi tersebut.
+// Setiap event langsung ditulis ke file supaya tetap tersimpan walau
+// service berhenti dengan log.Fatal.
+func Open(name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return fmt.Errorf("error creating ledger directory: %v", err)
+	}
+
+	f, err := os.Create(filepath.Join(Dir(), name+".jsonl"))
+	if err != nil {
+		return fmt.Errorf("error creating ledger file: %v", err)
+	}
+
+	file = f
+	encoder = json.NewEncoder(f)
+	migration = name
+	recorded = 0
+	return nil
+}
+
+// Close menutup file ledger dan mengembalikan jumlah event yang dicatat
+func Close() int {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file != nil {
+		file.Close()
+		file = nil
+		encoder = nil
+	}
+	return recorded
+}
+
+// Count mengembalikan jumlah event yang dicatat sejak Open
+func Count() int {
+	mu.Lock()
+	defer mu.Unlock()
+	return recorded
+}
+
+// Record mencatat satu perubahan. Tidak melakukan apa-apa jika ledger belum dibuka,
+// misalnya saat service dipanggil langsung dari main.
+func Record(entity, sourceID, field, original, new, reason string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if encoder == nil {
+		return
+	}
+
+	event := Event{
+		Migration: migration,
+		Entity:    entity,
+		SourceID:  sourceID,
+		Field:     field,
+		Original:  original,
+		New:       new,
+		Reason:    reason,
+	}
+	if err := encoder.Encode(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing ledger event: %v\n", err)
+		return
+	}
+	recorded++
+}
+
+// Truncate memotong value ke max byte dan mencatatnya jika terpotong
+func Truncate(entity, sourceID, field, value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	Record(entity, sourceID, field, value, value[:max], ReasonTruncated)
+	return value[:max]
+}
+
+// Buffer menampung event untuk perubahan yang baru berlaku setelah transaksi
+// di-commit. Event ditulis ke ledger dengan Flush, atau dibuang jika transaksi
+// gagal sehingga ledger tidak mencatat record yang tidak pernah tersimpan.
+type Buffer struct {
+	events []Event
+}
+
+// Record menampung satu perubahan, argumennya sama dengan Record
+func (b *Buffer) Record(entity, sourceID, field, original, new, reason string) {
+	b.events = append(b.events, Event{
+		Entity:   entity,
+		SourceID: sourceID,
+		Field:    field,
+		Original: original,
+		New:      new,
+		Reason:   reason,
+	})
+}
+
+// Truncate memotong value ke max byte dan menampung event jika terpotong
+func (b *Buffer) Truncate(entity, sourceID, field, value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	b.Record(entity, sourceID, field, value, value[:max], ReasonTruncated)
+	return value[:max]
+}
+
+// Append memindahkan semua event other ke b, misalnya event satu record yang
+// berhasil ke buffer transaksinya
+func (b *Buffer) Append(other *Buffer) {
+	b.events = append(b.events, other.events...)
+	other.events = nil
+}
+
+// Flush menulis semua event yang ditampung ke ledger lalu mengosongkan buffer
+func (b *Buffer) Flush() {
+	for _, e := range b.events {
+		Record(e.Entity, e.SourceID, e.Field, e.Original, e.New, e.Reason)
+	}
+	b.events = nil
+}
@@ -0,0 +1,127 @@
+package ledger
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Load membaca semua file ledger di dir. names membatasi migrasi yang dibaca.
+func Load(dir string, names []string) ([]Event, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) > 0 {
+		paths = nil
+		for _, name := range names {
+			path := filepath.Join(dir, name+".jsonl")
+			if _, err := os.Stat(path); err != nil {
+				return nil, fmt.Errorf("no ledger for migration %s in %s", name, dir)
+			}
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var events []Event
+	for _, path := range paths {
+		loaded, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, loaded...)
+	}
+	return events, nil
+}
+
+func loadFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("error parsing %s line %d: %v", path, line, err)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Group adalah jumlah event untuk satu kombinasi entity, field dan reason
+type Group struct {
+	Migration string
+	Entity    string
+	Field     string
+	Reason    string
+	Count     int
+}
+
+// Summarize mengelompokkan event per migrasi, entity, field dan reason
+func Summarize(events []Event) []Group {
+	index := make(map[Group]int)
+	var groups []Group
+	for _, e := range events {
+		key := Group{Migration: e.Migration, Entity: e.Entity, Field: e.Field, Reason: e.Reason}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, key)
+		}
+		groups[i].Count++
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Migration != groups[j].Migration {
+			return groups[i].Migration < groups[j].Migration
+		}
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
+
+// Export menulis event ke file .csv atau .json
+func Export(path string, events []Event) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if events == nil {
+			events = []Event{}
+		}
+		return encoder.Encode(events)
+	case ".csv":
+		writer := csv.NewWriter(file)
+		writer.Write([]string{"migration", "entity", "source_id", "field", "original", "new", "reason"})
+		for _, e := range events {
+			writer.Write([]string{e.Migration, e.Entity, e.SourceID, e.Field, e.Original, e.New, e.Reason})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported export format %q, use .csv or .json", filepath.Ext(path))
+	}
+}
@@ -0,0 +1,243 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"github.com/joho/godotenv"
+	"golang.org/x/term"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Nilai default jika LOG_* tidak diisi
+const (
+	DefaultDir        = "logs"
+	DefaultMaxSizeMB  = 50
+	DefaultMaxBackups = 5
+)
+
+// Satu proses menjalankan satu migrasi secara berurutan, jadi key record yang
+// sedang diproses cukup disimpan di level package
+var (
+	mu        sync.Mutex
+	record    string
+	exitHooks []func()
+)
+
+// Dir mengembalikan folder log dari LOG_DIR
+func Dir() string {
+	if dir := os.Getenv("LOG_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultDir
+}
+
+// Setup mengarahkan slog dan package log ke file <Dir>/<name>.log yang dirotasi
+// berdasarkan ukuran. Console (stderr) hanya menampilkan warning dan error supaya
+// tidak merusak progress bar. Setiap baris membawa run_id, migration dan record.
+// Fungsi yang dikembalikan menutup file log.
+func Setup(runID, name string) func() {
+	// LOG_* boleh diisi di .env, yang biasanya baru dibaca saat koneksi database dibuka
+	godotenv.Load()
+
+	var handlers []slog.Handler
+	closeFile := func() {}
+
+	file, err := openRotating(filepath.Join(Dir(), name+".log"), envInt("LOG_MAX_SIZE_MB", DefaultMaxSizeMB)*1024*1024, envInt("LOG_MAX_BACKUPS", DefaultMaxBackups))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening log file, logging to console only: %v\n", err)
+	} else {
+		handlers = append(handlers, fileHandler(file))
+		closeFile = func() { file.Close() }
+	}
+	handlers = append(handlers, consoleHandler())
+
+	var handler slog.Handler = &recordHandler{multiHandler(handlers)}
+	handler = handler.WithAttrs([]slog.Attr{slog.String("run_id", runID), slog.String("migration", name)})
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
+	// Output package log dari library pihak ketiga ikut menjadi baris slog
+	log.SetFlags(0)
+	log.SetOutput(stdWriter{logger})
+
+	return closeFile
+}
+
+// SetRecord mencatat key record sumber yang sedang diproses. Kosongkan setelah
+// loop selesai supaya baris log berikutnya tidak membawa key record terakhir.
+func SetRecord(key string) {
+	mu.Lock()
+	defer mu.Unlock()
+	record = key
+}
+
+func currentRecord() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return record
+}
+
+func fileHandler(w io.Writer) slog.Handler {
+	options := &slog.HandlerOptions{Level: level(os.Getenv("LOG_LEVEL"))}
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// consoleHandler mencetak warning dan error tanpa waktu dan run id, yang sudah ada di file log
+func consoleHandler() slog.Handler {
+	var w io.Writer = os.Stderr
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		w = clearLineWriter{os.Stderr}
+	}
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == "run_id") {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+// clearLineWriter menghapus baris progress bar sebelum menulis, supaya pesan tidak tercampur
+type clearLineWriter struct {
+	w io.Writer
+}
+
+func (c clearLineWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(c.w, "\r\x1b[K"); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
+
+func level(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// recordHandler menambahkan key record yang sedang diproses ke setiap baris
+type recordHandler struct {
+	slog.Handler
+}
+
+func (h *recordHandler) Handle(ctx context.Context, r slog.Record) error {
+	if key := currentRecord(); key != "" {
+		r.AddAttrs(slog.String("record", key))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *recordHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *recordHandler) WithGroup(name string) slog.Handler {
+	return &recordHandler{h.Handler.WithGroup(name)}
+}
+
+// multiHandler meneruskan setiap record ke semua handler yang menerima levelnya
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var first error
+	for _, h := range m {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// Fatal mencatat pesan di level error lalu keluar dengan exit code 1, pengganti
+// log.Fatal. args diberikan seperti slog: nama, nilai, ...
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	Exit(1)
+}
+
+// OnExit mendaftarkan fungsi yang dijalankan Exit sebelum proses keluar, karena
+// os.Exit melewati semua defer. Fungsi dijalankan dari yang terakhir didaftarkan.
+func OnExit(hook func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	exitHooks = append(exitHooks, hook)
+}
+
+// Exit menjalankan fungsi OnExit lalu keluar dengan exit code yang diberikan
+func Exit(code int) {
+	mu.Lock()
+	hooks := exitHooks
+	exitHooks = nil
+	mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+	os.Exit(code)
+}
+
+// stdWriter menerima output package log dari library pihak ketiga, misalnya
+// net/http. Kode aplikasi memakai slog langsung dengan level yang jelas, jadi
+// output ini dicatat sebagai warn dengan source=log supaya tetap terlihat di console.
+type stdWriter struct {
+	logger *slog.Logger
+}
+
+func (s stdWriter) Write(p []byte) (int, error) {
+	s.logger.Warn(strings.TrimRight(string(p), "\n"), "source", "log")
+	return len(p), nil
+}
@@ -1,11 +1,46 @@
 package main
 
 import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/runner"
 	"github.com/ApesJs/go-migration-app/service/user"
+	"github.com/ApesJs/go-migration-app/tracing"
 	_ "github.com/lib/pq"
+	"os"
+	"strings"
 )
 
 func main() {
+	// Proses migrasi yang dijalankan command run mewarisi run id dari environment
+	if os.Getenv("MIGRATION_RUN_ID") == "" {
+		os.Setenv("MIGRATION_RUN_ID", report.NewRunID())
+	}
+
+	// Opsi global di depan command, contoh: go run . --lang id --trace file run
+	args := globalArgs(os.Args[1:])
+
+	// Jalankan command jika ada argumen, contoh: go run . generate-legacy --scale 10
+	if len(args) > 0 {
+		closeLog := logging.Setup(report.RunID(), logName(args))
+		defer closeLog()
+		logging.OnExit(closeLog)
+		tracing.Setup(report.RunID(), logName(args))
+		defer tracing.Close()
+		logging.OnExit(tracing.Close)
+		runCommand(args[0], args[1:])
+		return
+	}
+
+	closeLog := logging.Setup(report.RunID(), "main")
+	defer closeLog()
+	logging.OnExit(closeLog)
+	tracing.Setup(report.RunID(), "main")
+	defer tracing.Close()
+	logging.OnExit(tracing.Close)
+
 	//user.BDMService()
 	//user.BdmPersonaService()
 	//user.UserService()
@@ -23,3 +58,47 @@ func main() {
 	//airport.AirportService()
 	//airline.AirlineService()
 }
+
+// logName menentukan nama file log: nama migrasi untuk proses migrasi, nama command untuk yang lain
+func logName(args []string) string {
+	if args[0] == runner.ChildCommand && len(args) > 1 {
+		return args[1]
+	}
+	return args[0]
+}
+
+// globalArgs membaca opsi global di depan command dan mengisinya ke environment
+// supaya proses migrasi yang dijalankan run ikut memakainya:
+// --lang X (MIGRATION_LANG) dan --trace X (TRACE_EXPORTER), juga dalam bentuk --opsi=X
+func globalArgs(args []string) []string {
+	for len(args) > 0 {
+		name, value, hasValue := strings.Cut(args[0], "=")
+		if name != "--lang" && name != "--trace" {
+			return args
+		}
+		if hasValue {
+			args = args[1:]
+		} else if len(args) > 1 {
+			value, args = args[1], args[2:]
+		} else {
+			fmt.Printf("%s needs a value\n", name)
+			os.Exit(2)
+		}
+
+		switch name {
+		case "--lang":
+			if !i18n.Supported(value) {
+				fmt.Printf("Unsupported language %q, use %s or %s\n", value, i18n.EN, i18n.ID)
+				os.Exit(2)
+			}
+			os.Setenv("MIGRATION_LANG", strings.ToLower(value))
+		case "--trace":
+			if !tracing.Supported(value) {
+				fmt.Printf("Unsupported trace exporter %q, use %s, %s or %s,%s\n", value, tracing.ExporterOTLP, tracing.ExporterFile, tracing.ExporterOTLP, tracing.ExporterFile)
+				os.Exit(2)
+			}
+			os.Setenv("TRACE_EXPORTER", value)
+		}
+	}
+	return args
+}
@@ -0,0 +1,236 @@
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// writeInterval adalah jarak penulisan file textfile selama migrasi berjalan
+const writeInterval = 5 * time.Second
+
+// durationBuckets adalah batas bucket histogram durasi dalam detik
+var durationBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30}
+
+// family adalah satu nama metrik beserta semua seri label-nya
+type family struct {
+	name   string
+	help   string
+	kind   string // counter, gauge atau histogram
+	series map[string]*series
+}
+
+type series struct {
+	labels  string
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// Satu proses menjalankan satu migrasi, jadi metrik disimpan di level package
+// seperti report dan ledger
+var (
+	mu        sync.Mutex
+	families  = make(map[string]*family)
+	order     []string
+	migration string
+	stop      chan struct{}
+	stopped   sync.WaitGroup
+)
+
+// TextfileDir mengembalikan folder output textfile collector dari METRICS_TEXTFILE_DIR
+func TextfileDir() string {
+	return os.Getenv("METRICS_TEXTFILE_DIR")
+}
+
+// Start memberi label migration ke semua metrik proses ini dan, jika
+// METRICS_TEXTFILE_DIR diisi, menulis file .prom secara berkala sampai Close
+func Start(name string) {
+	mu.Lock()
+	migration = name
+	mu.Unlock()
+
+	if TextfileDir() == "" {
+		return
+	}
+	stop = make(chan struct{})
+	stopped.Add(1)
+	go func() {
+		defer stopped.Done()
+		ticker := time.NewTicker(writeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				writeTextfile()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close menulis nilai terakhir ke file textfile
+func Close() {
+	if stop == nil {
+		return
+	}
+	close(stop)
+	stopped.Wait()
+	stop = nil
+	writeTextfile()
+}
+
+// TextfilePath mengembalikan file .prom untuk satu nama proses
+func TextfilePath(name string) string {
+	return filepath.Join(TextfileDir(), "migration_"+name+".prom")
+}
+
+func writeTextfile() {
+	mu.Lock()
+	name := migration
+	mu.Unlock()
+	if name == "" {
+		name = "unnamed"
+	}
+	if err := WriteFile(TextfilePath(name)); err != nil {
+		slog.Warn("Warning: could not write metrics textfile", "error", err)
+	}
+}
+
+// WriteFile menulis semua metrik ke path lewat file sementara, supaya collector
+// tidak pernah membaca file yang setengah ditulis
+func WriteFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	Write(f)
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Add menambah counter
+func Add(name, help string, value float64, labels ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	lookup(name, help, "counter", labels).value += value
+}
+
+// Set mengganti nilai gauge
+func Set(name, help string, value float64, labels ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	lookup(name, help, "gauge", labels).value = value
+}
+
+// Observe mencatat satu durasi ke histogram
+func Observe(name, help string, d time.Duration, labels ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	s := lookup(name, help, "histogram", labels)
+	seconds := d.Seconds()
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.sum += seconds
+	s.count++
+}
+
+// Commit meng-commit transaksi dan mencatat durasinya
+func Commit(tx *sql.Tx) error {
+	start := time.Now()
+	err := tx.Commit()
+	Observe("migration_commit_duration_seconds", "Duration of transaction commits.", time.Since(start))
+	return err
+}
+
+// lookup mencari seri metrik, label diberikan berpasangan: nama, nilai, ...
+func lookup(name, help, kind string, labels []string) *series {
+	f, ok := families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: kind, series: make(map[string]*series)}
+		families[name] = f
+		order = append(order, name)
+	}
+
+	// Proses run (induk) tidak punya migrasi sendiri dan memberi label migration langsung
+	var pairs []string
+	if migration != "" {
+		pairs = append(pairs, `migration="`+escapeLabel(migration)+`"`)
+	}
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+`="`+escapeLabel(labels[i+1])+`"`)
+	}
+	key := strings.Join(pairs, ",")
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: key}
+		if kind == "histogram" {
+			s.buckets = make([]uint64, len(durationBuckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// labelEscaper meng-escape nilai label sesuai format teks Prometheus, hanya
+// backslash, kutip ganda dan newline; karakter lain ditulis apa adanya
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// Write menulis semua metrik dalam format teks Prometheus
+func Write(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, name := range order {
+		f := families[name]
+		fmt.Fprintf(w, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+
+		var keys []string
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != "histogram" {
+				fmt.Fprintf(w, "%s{%s} %g\n", f.name, s.labels, s.value)
+				continue
+			}
+			prefix := s.labels
+			if prefix != "" {
+				prefix += ","
+			}
+			for i, bound := range durationBuckets {
+				fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", f.name, prefix, bound, s.buckets[i])
+			}
+			fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", f.name, prefix, s.count)
+			fmt.Fprintf(w, "%s_sum{%s} %g\n", f.name, s.labels, s.sum)
+			fmt.Fprintf(w, "%s_count{%s} %d\n", f.name, s.labels, s.count)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEscapeLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "organization_user", "organization_user"},
+		{"backslash", `C:\migrations`, `C:\\migrations`},
+		{"double quote", `say "hi"`, `say \"hi\"`},
+		{"newline", "line1\nline2", `line1\nline2`},
+		{"escapes are not doubled", `a\"b`, `a\\\"b`},
+		{"tab stays as is", "a\tb", "a\tb"},
+		{"unicode stays as is", "umrah – mekkah", "umrah – mekkah"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeLabel(tt.value); got != tt.want {
+				t.Errorf("escapeLabel(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteEscapesLabels(t *testing.T) {
+	mu.Lock()
+	families = make(map[string]*family)
+	order = nil
+	migration = `pack"age`
+	mu.Unlock()
+	defer func() { migration = "" }()
+
+	Add("migration_records_total", "Records by outcome.", 2, "outcome", "umrah – mekkah\n")
+
+	var buf bytes.Buffer
+	Write(&buf)
+
+	want := `migration_records_total{migration="pack\"age",outcome="umrah – mekkah\n"} 2`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Write() output:\n%s\nwant line %s", buf.String(), want)
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Handler melayani /metrics dengan menggabungkan file .prom semua proses
+// migrasi di TextfileDir dan metrik proses ini
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMerged(w)
+	})
+}
+
+// writeMerged menggabungkan metrik dengan nama sama dari beberapa file, karena
+// format Prometheus tidak mengizinkan HELP/TYPE yang sama muncul dua kali
+func writeMerged(w io.Writer) {
+	var local bytes.Buffer
+	Write(&local)
+	sources := [][]byte{local.Bytes()}
+
+	if dir := TextfileDir(); dir != "" {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.prom"))
+		for _, path := range paths {
+			if data, err := os.ReadFile(path); err == nil {
+				sources = append(sources, data)
+			}
+		}
+	}
+
+	var names []string
+	headers := make(map[string][]string)
+	samples := make(map[string][]string)
+	for _, data := range sources {
+		current := ""
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "# ") {
+				fields := strings.Fields(line)
+				if len(fields) < 3 {
+					continue
+				}
+				current = fields[2]
+				if _, ok := headers[current]; !ok {
+					names = append(names, current)
+				}
+				if len(headers[current]) < 2 {
+					headers[current] = append(headers[current], line)
+				}
+				continue
+			}
+			if line != "" && current != "" {
+				samples[current] = append(samples[current], line)
+			}
+		}
+	}
+
+	for _, name := range names {
+		for _, line := range headers[name] {
+			io.WriteString(w, line+"\n")
+		}
+		for _, line := range samples[name] {
+			io.WriteString(w, line+"\n")
+		}
+	}
+}
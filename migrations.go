@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/ApesJs/go-migration-app/runner"
+	"github.com/ApesJs/go-migration-app/service/airline"
+	"github.com/ApesJs/go-migration-app/service/airport"
+	"github.com/ApesJs/go-migration-app/service/hotel"
+	_package "github.com/ApesJs/go-migration-app/service/package"
+	"github.com/ApesJs/go-migration-app/service/travel"
+	"github.com/ApesJs/go-migration-app/service/user"
+)
+
+// Nama database yang dipakai migrasi, untuk membatasi koneksi per database
+const (
+	dbProdExistingUmrah = "prod-existing-umrah"
+	dbProdIdentity      = "prod-identity"
+	dbLocalIdentity     = "local-identity"
+	dbDevIdentity       = "dev-identity"
+	dbDevUmrah          = "dev-umrah"
+	dbDevGeneral        = "dev-general"
+)
+
+// migrations berisi semua service migrasi beserta urutan dependensinya.
+// Migrasi tanpa hubungan dependensi dijalankan bersamaan oleh command run.
+var migrations = []runner.Migration{
+	{
+		Name:      "user",
+		Databases: []string{dbProdExistingUmrah, dbLocalIdentity},
+		Run:       user.UserService,
+	},
+	{
+		Name:      "bdm",
+		Databases: []string{dbProdExistingUmrah, dbProdIdentity},
+		Run:       user.BDMService,
+	},
+	{
+		Name:      "make-uc",
+		DependsOn: []string{"user", "bdm"},
+		Databases: []string{dbProdIdentity},
+		Run:       user.MakeUCService,
+	},
+	{
+		Name:      "user-persona",
+		DependsOn: []string{"user"},
+		Databases: []string{dbProdExistingUmrah, dbProdIdentity},
+		Run:       user.UserPersonaService,
+	},
+	{
+		Name:      "bdm-persona",
+		DependsOn: []string{"bdm"},
+		Databases: []string{dbProdExistingUmrah, dbLocalIdentity},
+		Run:       user.BdmPersonaService,
+	},
+	{
+		Name:      "organization",
+		DependsOn: []string{"bdm"},
+		Databases: []string{dbProdExistingUmrah, dbLocalIdentity},
+		Run:       travel.OrganizationService,
+	},
+	{
+		Name:      "organization-instance",
+		DependsOn: []string{"organization"},
+		Databases: []string{dbProdExistingUmrah, dbLocalIdentity},
+		Run:       travel.OrganizationInstanceService,
+	},
+	{
+		Name:      "organization-user",
+		DependsOn: []string{"organization", "user"},
+		Databases: []string{dbProdExistingUmrah, dbLocalIdentity},
+		Run:       travel.OrganizationUserService,
+	},
+	{
+		Name:      "wukala-persona",
+		DependsOn: []string{"user", "organization"},
+		Databases: []string{dbProdExistingUmrah, dbDevIdentity, dbDevUmrah},
+		Run:       user.WukalaPersonaService,
+	},
+	{
+		Name:      "airport",
+		Databases: []string{dbProdExistingUmrah, dbDevIdentity, dbDevGeneral},
+		Run:       airport.AirportService,
+	},
+	{
+		Name:      "package",
+		DependsOn: []string{"organization-instance"},
+		Databases: []string{dbProdExistingUmrah, dbDevIdentity, dbDevUmrah},
+		Run:       _package.PackageService,
+	},
+	{
+		// Mengisi ulang ID airline di package, jadi harus setelah package
+		Name:      "airline",
+		DependsOn: []string{"package"},
+		Databases: []string{dbDevGeneral, dbDevUmrah},
+		Run:       airline.AirlineService,
+	},
+	{
+		// Butuh kota dari airport dan mengisi ulang ID hotel di package
+		Name:      "hotel",
+		DependsOn: []string{"airport", "package"},
+		Databases: []string{dbProdExistingUmrah, dbDevIdentity, dbDevGeneral, dbDevUmrah},
+		Run:       hotel.HotelService,
+	},
+}
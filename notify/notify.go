@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jenis event notifikasi
+const (
+	EventStarted        = "started"
+	EventCompleted      = "completed"
+	EventFailed         = "failed"
+	EventErrorThreshold = "error_threshold"
+)
+
+// sendTimeout membatasi satu request webhook supaya migrasi tidak tertahan
+const sendTimeout = 10 * time.Second
+
+// Migration adalah ringkasan satu migrasi di payload
+type Migration struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Processed  int    `json:"processed"`
+	Failed     int    `json:"failed"`
+	Errors     int    `json:"errors"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Event adalah payload webhook generik. Format Slack dibuat dari struct yang sama.
+type Event struct {
+	Event       string      `json:"event"`
+	Message     string      `json:"message"`
+	RunID       string      `json:"run_id"`
+	Environment string      `json:"environment,omitempty"`
+	Host        string      `json:"host"`
+	Status      string      `json:"status,omitempty"`
+	StartedAt   time.Time   `json:"started_at"`
+	FinishedAt  *time.Time  `json:"finished_at,omitempty"`
+	DurationMs  int64       `json:"duration_ms,omitempty"`
+	Migration   string      `json:"migration,omitempty"`   // migrasi yang melewati batas error
+	ErrorCount  int         `json:"error_count,omitempty"` // jumlah error saat batas dilewati
+	Threshold   int         `json:"threshold,omitempty"`
+	Migrations  []Migration `json:"migrations,omitempty"`
+	Report      string      `json:"report,omitempty"`     // path run.json di mesin yang menjalankan
+	ReportURL   string      `json:"report_url,omitempty"` // dari NOTIFY_REPORT_URL
+}
+
+// WebhookURL adalah tujuan payload JSON generik (NOTIFY_WEBHOOK_URL)
+func WebhookURL() string {
+	return os.Getenv("NOTIFY_WEBHOOK_URL")
+}
+
+// SlackURL adalah incoming webhook Slack atau yang kompatibel (NOTIFY_SLACK_WEBHOOK_URL)
+func SlackURL() string {
+	return os.Getenv("NOTIFY_SLACK_WEBHOOK_URL")
+}
+
+// Enabled bernilai true jika minimal satu webhook diisi
+func Enabled() bool {
+	return WebhookURL() != "" || SlackURL() != ""
+}
+
+// ErrorThreshold adalah jumlah error satu migrasi yang memicu notifikasi (NOTIFY_ERROR_THRESHOLD, 0 = mati)
+func ErrorThreshold() int {
+	n, _ := strconv.Atoi(os.Getenv("NOTIFY_ERROR_THRESHOLD"))
+	return n
+}
+
+// ReportURL mengisi {run_id} di NOTIFY_REPORT_URL, misalnya https://reports.internal/{run_id}/report.html
+func ReportURL(runID string) string {
+	return strings.ReplaceAll(os.Getenv("NOTIFY_REPORT_URL"), "{run_id}", runID)
+}
+
+// Send mengirim event ke semua webhook yang diisi. Kegagalan hanya dicatat
+// sebagai warning, notifikasi tidak pernah menghentikan migrasi.
+func Send(e Event) {
+	if !Enabled() {
+		return
+	}
+	if e.Host == "" {
+		e.Host, _ = os.Hostname()
+	}
+	if e.Environment == "" {
+		e.Environment = os.Getenv("MIGRATION_ENV")
+	}
+	if e.ReportURL == "" {
+		e.ReportURL = ReportURL(e.RunID)
+	}
+	if e.Message == "" {
+		e.Message = message(e)
+	}
+
+	if url := WebhookURL(); url != "" {
+		if err := post(url, e); err != nil {
+			slog.Warn("Warning: could not send webhook notification", "event", e.Event, "error", err)
+		}
+	}
+	if url := SlackURL(); url != "" {
+		if err := post(url, slackPayload(e)); err != nil {
+			slog.Warn("Warning: could not send Slack notification", "event", e.Event, "error", err)
+		}
+	}
+}
+
+func post(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: sendTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// message adalah ringkasan satu baris untuk payload generik dan judul pesan Slack
+func message(e Event) string {
+	env := ""
+	if e.Environment != "" {
+		env = " on " + e.Environment
+	}
+	switch e.Event {
+	case EventStarted:
+		return fmt.Sprintf("Migration run %s started%s (%s)", e.RunID, env, e.Host)
+	case EventErrorThreshold:
+		return fmt.Sprintf("Migration %s in run %s%s reached %d errors", e.Migration, e.RunID, env, e.ErrorCount)
+	case EventFailed:
+		return fmt.Sprintf("Migration run %s failed%s after %s", e.RunID, env, duration(e.DurationMs))
+	default:
+		return fmt.Sprintf("Migration run %s %s%s in %s", e.RunID, strings.ReplaceAll(e.Status, "_", " "), env, duration(e.DurationMs))
+	}
+}
+
+// slackPayload menyusun pesan incoming webhook Slack: satu judul dan satu baris per migrasi
+func slackPayload(e Event) map[string]string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "*%s*", e.Message)
+	for _, m := range e.Migrations {
+		fmt.Fprintf(&s, "\n• `%s` %s, %s, %d processed, %d failed, %d errors", m.Name, m.Status, duration(m.DurationMs), m.Processed, m.Failed, m.Errors)
+		if m.Error != "" {
+			fmt.Fprintf(&s, " (%s)", m.Error)
+		}
+	}
+	if e.ReportURL != "" {
+		fmt.Fprintf(&s, "\nReport: <%s>", e.ReportURL)
+	} else if e.Report != "" {
+		fmt.Fprintf(&s, "\nReport: `%s` on %s", e.Report, e.Host)
+	}
+	return map[string]string{"text": s.String()}
+}
+
+func duration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+}
@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// request adalah satu POST yang diterima server test
+type request struct {
+	path        string
+	contentType string
+	body        []byte
+}
+
+func newServer(t *testing.T, status int) (*httptest.Server, func() []request) {
+	var (
+		mu       sync.Mutex
+		received []request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, request{path: r.URL.Path, contentType: r.Header.Get("Content-Type"), body: body})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]request(nil), received...)
+	}
+}
+
+func TestSend(t *testing.T) {
+	finishedAt := time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+	event := Event{
+		Event:      EventCompleted,
+		RunID:      "20261019T120000.000-ab12",
+		Status:     "completed_with_errors",
+		StartedAt:  finishedAt.Add(-90 * time.Minute),
+		FinishedAt: &finishedAt,
+		DurationMs: (90 * time.Minute).Milliseconds(),
+		Migrations: []Migration{
+			{Name: "user", Status: "done", DurationMs: 60000, Processed: 120, Failed: 2, Errors: 2},
+			{Name: "package", Status: "failed", Error: "exit status 1"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		webhook     bool
+		slack       bool
+		status      int
+		wantWebhook int
+		wantSlack   int
+	}{
+		{name: "disabled sends nothing", status: http.StatusOK},
+		{name: "generic webhook only", webhook: true, status: http.StatusOK, wantWebhook: 1},
+		{name: "slack only", slack: true, status: http.StatusOK, wantSlack: 1},
+		{name: "both", webhook: true, slack: true, status: http.StatusOK, wantWebhook: 1, wantSlack: 1},
+		{name: "failing endpoint is only a warning", webhook: true, slack: true, status: http.StatusInternalServerError, wantWebhook: 1, wantSlack: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := newServer(t, tt.status)
+			t.Setenv("NOTIFY_WEBHOOK_URL", "")
+			t.Setenv("NOTIFY_SLACK_WEBHOOK_URL", "")
+			if tt.webhook {
+				t.Setenv("NOTIFY_WEBHOOK_URL", server.URL+"/webhook")
+			}
+			if tt.slack {
+				t.Setenv("NOTIFY_SLACK_WEBHOOK_URL", server.URL+"/slack")
+			}
+			t.Setenv("MIGRATION_ENV", "staging")
+			t.Setenv("NOTIFY_REPORT_URL", "https://reports.example/{run_id}/report.html")
+
+			Send(event)
+
+			var webhook, slack []request
+			for _, r := range received() {
+				if r.contentType != "application/json" {
+					t.Errorf("%s Content-Type = %q", r.path, r.contentType)
+				}
+				switch r.path {
+				case "/webhook":
+					webhook = append(webhook, r)
+				case "/slack":
+					slack = append(slack, r)
+				default:
+					t.Errorf("unexpected request to %s", r.path)
+				}
+			}
+			if len(webhook) != tt.wantWebhook || len(slack) != tt.wantSlack {
+				t.Fatalf("got %d webhook and %d slack requests, want %d and %d", len(webhook), len(slack), tt.wantWebhook, tt.wantSlack)
+			}
+
+			for _, r := range webhook {
+				var got Event
+				if err := json.Unmarshal(r.body, &got); err != nil {
+					t.Fatalf("webhook payload: %v", err)
+				}
+				if got.Event != EventCompleted || got.RunID != event.RunID || got.Status != event.Status {
+					t.Errorf("webhook event = %s %s %s", got.Event, got.RunID, got.Status)
+				}
+				if got.Environment != "staging" || got.Host == "" {
+					t.Errorf("environment = %q, host = %q, want staging and the hostname", got.Environment, got.Host)
+				}
+				if want := "https://reports.example/" + event.RunID + "/report.html"; got.ReportURL != want {
+					t.Errorf("report_url = %q, want %q", got.ReportURL, want)
+				}
+				if want := "Migration run " + event.RunID + " completed with errors on staging in 1h30m0s"; got.Message != want {
+					t.Errorf("message = %q, want %q", got.Message, want)
+				}
+				if len(got.Migrations) != 2 || got.Migrations[0].Processed != 120 {
+					t.Errorf("migrations = %+v", got.Migrations)
+				}
+			}
+
+			for _, r := range slack {
+				var got map[string]string
+				if err := json.Unmarshal(r.body, &got); err != nil {
+					t.Fatalf("slack payload: %v", err)
+				}
+				for _, want := range []string{
+					"*Migration run " + event.RunID + " completed with errors on staging in 1h30m0s*",
+					"• `user` done, 1m0s, 120 processed, 2 failed, 2 errors",
+					"• `package` failed, 0s, 0 processed, 0 failed, 0 errors (exit status 1)",
+					"Report: <https://reports.example/" + event.RunID + "/report.html>",
+				} {
+					if !strings.Contains(got["text"], want) {
+						t.Errorf("slack text missing %q:\n%s", want, got["text"])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{
+			name:  "started",
+			event: Event{Event: EventStarted, RunID: "r1", Host: "db-migrate-01"},
+			want:  "Migration run r1 started (db-migrate-01)",
+		},
+		{
+			name:  "failed",
+			event: Event{Event: EventFailed, RunID: "r1", Environment: "prod", DurationMs: 61000},
+			want:  "Migration run r1 failed on prod after 1m1s",
+		},
+		{
+			name:  "error threshold",
+			event: Event{Event: EventErrorThreshold, RunID: "r1", Migration: "package", ErrorCount: 50},
+			want:  "Migration package in run r1 reached 50 errors",
+		},
+		{
+			name:  "completed uses the run status",
+			event: Event{Event: EventCompleted, RunID: "r1", Status: "completed_with_errors", DurationMs: 2000},
+			want:  "Migration run r1 completed with errors in 2s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := message(tt.event); got != tt.want {
+				t.Errorf("message() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/tracing"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Mode tampilan progress
+const (
+	ModeBar   = "bar"   // satu baris yang ditimpa dengan "\r", untuk terminal dan runner
+	ModePlain = "plain" // baris biasa secara berkala, untuk CI, nohup dan cron
+)
+
+const (
+	barWidth       = 20
+	redrawInterval = 200 * time.Millisecond
+)
+
+// DefaultPlainInterval adalah jarak baris progress di mode plain jika PROGRESS_INTERVAL tidak diisi
+const DefaultPlainInterval = 10 * time.Second
+
+// Mode mengembalikan mode dari PROGRESS_MODE, atau bar jika stdout terminal dan plain jika bukan.
+// Runner mengisi PROGRESS_MODE=bar karena console-nya sendiri yang menangani output non-terminal.
+func Mode() string {
+	switch mode := os.Getenv("PROGRESS_MODE"); mode {
+	case ModeBar, ModePlain:
+		return mode
+	}
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		return ModeBar
+	}
+	return ModePlain
+}
+
+func plainInterval() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PROGRESS_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return DefaultPlainInterval
+}
+
+// Plan adalah daftar fase satu migrasi. Dengan tahu semua fase sejak awal,
+// progress dan ETA keseluruhan bisa dihitung selain progress fase yang berjalan.
+type Plan struct {
+	mu        sync.Mutex
+	out       io.Writer
+	mode      string
+	interval  time.Duration
+	phases    []string
+	index     int // fase yang sedang berjalan
+	startedAt time.Time
+	last      *Bar
+}
+
+// NewPlan membuat rencana dengan fase-fase yang akan dijalankan berurutan
+func NewPlan(phases ...string) *Plan {
+	p := &Plan{
+		out:       os.Stdout,
+		mode:      Mode(),
+		phases:    phases,
+		index:     -1,
+		startedAt: time.Now(),
+	}
+	p.interval = redrawInterval
+	if p.mode == ModePlain {
+		p.interval = plainInterval()
+	}
+	return p
+}
+
+// Single adalah rencana satu fase, untuk migrasi yang hanya punya satu loop
+func Single(phase string, total int) *Bar {
+	return NewPlan(phase).Start(phase, total)
+}
+
+// Start memulai fase berikutnya. total 0 berarti jumlahnya tidak diketahui,
+// misalnya satu UPDATE besar, sehingga hanya nama fase dan durasinya yang tampil.
+// Saat tracing aktif setiap fase menjadi span, dan record-nya dibagi ke span batch.
+func (p *Plan) Start(phase string, total int) *Bar {
+	// Fase sebelumnya yang tidak di-Finish tetap ditutup span-nya supaya fase ini tidak menjadi anaknya
+	if p.last != nil {
+		p.last.endSpans()
+	}
+
+	p.mu.Lock()
+	p.index++
+	for i := p.index; i < len(p.phases); i++ {
+		if p.phases[i] == phase {
+			p.index = i
+			break
+		}
+	}
+	if p.index >= len(p.phases) {
+		p.phases = append(p.phases, phase)
+	}
+	p.mu.Unlock()
+
+	b := &Bar{
+		plan:      p,
+		phase:     phase,
+		number:    p.index + 1,
+		total:     int64(total),
+		startedAt: time.Now(),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	// Fase sebelumnya sudah ditutup, jadi parent fase ini span root
+	b.ctx, b.span = tracing.Start(tracing.Context(), phase, "phase.number", b.number, "phase.total_records", total)
+	if b.span != nil {
+		// Query service yang tidak membawa context menjadi anak fase atau batch-nya
+		tracing.Activate(b.ctx)
+		if total > 0 {
+			b.batchSize = int64(tracing.BatchSize())
+			b.nextBatch(0)
+		}
+	}
+	p.last = b
+	go b.loop()
+	return b
+}
+
+// Bar adalah progress satu fase. Add aman dipanggil dari beberapa worker sekaligus.
+type Bar struct {
+	plan      *Plan
+	phase     string
+	number    int
+	total     int64
+	current   atomic.Int64
+	startedAt time.Time
+	once      sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+
+	// Span fase dan batch yang berjalan, nil jika tracing mati
+	spanMu    sync.Mutex
+	ctx       context.Context // context span fase, parent span batch
+	span      *tracing.Span
+	batch     *tracing.Span
+	batchSize int64
+	batchEnd  int64
+	spanEnded bool
+}
+
+// Add menambah jumlah record yang selesai diproses
+func (b *Bar) Add(n int) {
+	current := b.current.Add(int64(n))
+	if b.span != nil {
+		b.spanMu.Lock()
+		if b.batch != nil && current >= b.batchEnd {
+			b.nextBatch(current)
+		}
+		b.spanMu.Unlock()
+	}
+}
+
+// Finish menutup fase dan mencetak baris terakhirnya
+func (b *Bar) Finish() {
+	b.once.Do(func() {
+		close(b.stop)
+		<-b.done
+		b.endSpans()
+		b.plan.write(b.line(true), true)
+	})
+}
+
+// nextBatch menutup span batch yang berjalan dan membuka batch berikutnya mulai dari record from
+func (b *Bar) nextBatch(from int64) {
+	if b.batch != nil {
+		b.batch.SetAttr("batch.records", from-(b.batchEnd-b.batchSize))
+		b.batch.End()
+	}
+	start := from - from%b.batchSize
+	b.batchEnd = start + b.batchSize
+	var ctx context.Context
+	ctx, b.batch = tracing.Start(b.ctx, fmt.Sprintf("%s batch %d", b.phase, start/b.batchSize+1),
+		"batch.number", int(start/b.batchSize)+1, "batch.first_record", start+1)
+	tracing.Activate(ctx)
+}
+
+// endSpans menutup span batch dan fase, aman dipanggil lebih dari sekali
+func (b *Bar) endSpans() {
+	if b.span == nil {
+		return
+	}
+	b.spanMu.Lock()
+	defer b.spanMu.Unlock()
+	if b.spanEnded {
+		return
+	}
+	b.spanEnded = true
+	current := b.current.Load()
+	if b.batch != nil {
+		b.batch.SetAttr("batch.records", current-(b.batchEnd-b.batchSize))
+		b.batch.End()
+		b.batch = nil
+	}
+	tracing.Activate(nil)
+	b.span.SetAttr("phase.records", current)
+	b.span.End()
+}
+
+func (b *Bar) loop() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.plan.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.plan.write(b.line(false), b.plan.mode == ModePlain)
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// line menyusun teks progress, misalnya
+// "[2/4] provinces [====>     ] 120/324 37% 45/s ETA 5s | overall 40% ETA 1m"
+func (b *Bar) line(finished bool) string {
+	current := b.current.Load()
+	elapsed := time.Since(b.startedAt)
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "[%d/%d] %s", b.number, b.plan.count(), b.phase)
+
+	fraction := 0.0
+	if b.total > 0 {
+		fraction = float64(current) / float64(b.total)
+		if fraction > 1 {
+			fraction = 1
+		}
+		filled := int(fraction * barWidth)
+		fmt.Fprintf(&s, " [%s%s] %d/%d %3.0f%%", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled), current, b.total, fraction*100)
+	} else if current > 0 {
+		fmt.Fprintf(&s, " %d", current)
+	}
+	if current > 0 && elapsed > 0 {
+		fmt.Fprintf(&s, " %.0f/s", float64(current)/elapsed.Seconds())
+	}
+
+	if finished {
+		fmt.Fprintf(&s, " %s", i18n.T("progress.done", elapsed.Round(time.Second)))
+		return s.String()
+	}
+	if b.total > 0 && current > 0 {
+		fmt.Fprintf(&s, " ETA %s", eta(elapsed, fraction))
+	}
+
+	// Progress keseluruhan: fase yang sudah selesai ditambah pecahan fase berjalan
+	if count := b.plan.count(); count > 1 {
+		overall := (float64(b.number-1) + fraction) / float64(count)
+		fmt.Fprintf(&s, " | %s %.0f%%", i18n.T("progress.overall"), overall*100)
+		if overall > 0 {
+			fmt.Fprintf(&s, " ETA %s", eta(time.Since(b.plan.startedAt), overall))
+		}
+	}
+	return s.String()
+}
+
+func (p *Plan) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.phases)
+}
+
+// write mencetak baris progress. Di mode bar baris ditimpa dengan "\r" sampai newline.
+func (p *Plan) write(line string, newline bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.mode == ModePlain {
+		if newline {
+			fmt.Fprintln(p.out, line)
+		}
+		return
+	}
+	fmt.Fprintf(p.out, "\r%s\x1b[K", line)
+	if newline {
+		fmt.Fprintln(p.out)
+	}
+}
+
+func eta(elapsed time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return 0
+	}
+	return (time.Duration(float64(elapsed)/fraction) - elapsed).Round(time.Second)
+}
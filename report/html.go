@@ -0,0 +1,473 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// outcomeColors adalah warna outcome standar di grafik, outcome lain memakai otherColor
+var outcomeColors = map[string]string{
+	OutcomeTransferred: "#2e7d32",
+	OutcomeInserted:    "#43a047",
+	OutcomeUpdated:     "#1e88e5",
+	OutcomeSkipped:     "#f9a825",
+	OutcomeFailed:      "#e53935",
+}
+
+const otherColor = "#8e8e8e"
+
+// throughputBuckets adalah jumlah titik di grafik throughput
+const throughputBuckets = 60
+
+// htmlItem adalah satu baris tabel duplikat, placeholder, list atau error
+type htmlItem struct {
+	Migration string
+	Kind      string
+	Item      string
+}
+
+type htmlPage struct {
+	Run             *Run
+	Reports         []*Report
+	Reconcile       *ReconcileRun
+	Generated       time.Time
+	Duration        string
+	OutcomeChart    template.HTML
+	ThroughputChart template.HTML
+	TimelineChart   template.HTML
+	Items           []htmlItem
+	Errors          []htmlItem
+	ConfigKeys      []string
+	ConfigValues    map[string]string
+}
+
+// LoadRunReports membaca report setiap migrasi yang tercatat di run.json
+func LoadRunReports(run *Run) ([]*Report, error) {
+	var reports []*Report
+	for _, m := range run.Migrations {
+		if m.Report == "" {
+			continue
+		}
+		r, err := Load(Path(run.RunID, m.Name))
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// WriteHTML menulis laporan satu run sebagai satu file HTML tanpa asset luar.
+// recon boleh nil jika reconcile belum dijalankan untuk run ini.
+func WriteHTML(w io.Writer, run *Run, reports []*Report, recon *ReconcileRun) error {
+	page := htmlPage{
+		Run:             run,
+		Reports:         reports,
+		Reconcile:       recon,
+		Generated:       time.Now(),
+		Duration:        (time.Duration(run.DurationMs) * time.Millisecond).Round(time.Second).String(),
+		OutcomeChart:    outcomeChart(reports),
+		ThroughputChart: throughputChart(run, reports),
+		TimelineChart:   timelineChart(run, reports),
+		ConfigValues:    make(map[string]string),
+	}
+
+	for _, r := range reports {
+		for _, item := range r.Duplicates {
+			page.Items = append(page.Items, htmlItem{r.Migration, "duplicate", item})
+		}
+		for _, item := range r.Placeholders {
+			page.Items = append(page.Items, htmlItem{r.Migration, "placeholder", item})
+		}
+		for _, list := range r.Lists {
+			for _, item := range list.Items {
+				page.Items = append(page.Items, htmlItem{r.Migration, list.Title, item})
+			}
+		}
+		for _, item := range r.Errors {
+			page.Errors = append(page.Errors, htmlItem{r.Migration, "error", item})
+		}
+		for key, value := range r.Config {
+			page.ConfigValues[key] = value
+		}
+	}
+
+	for key := range page.ConfigValues {
+		page.ConfigKeys = append(page.ConfigKeys, key)
+	}
+	sort.Strings(page.ConfigKeys)
+
+	return htmlTemplate.Execute(w, page)
+}
+
+// outcomeChart menggambar bar bertumpuk outcome per migrasi
+func outcomeChart(reports []*Report) template.HTML {
+	const labelWidth, barWidth, rowHeight = 180, 560, 26
+
+	max := 0
+	var legend []string
+	seen := make(map[string]bool)
+	for _, r := range reports {
+		if r.Processed() > max {
+			max = r.Processed()
+		}
+		for _, c := range r.Outcomes {
+			if !seen[c.Name] {
+				seen[c.Name] = true
+				legend = append(legend, c.Name)
+			}
+		}
+	}
+	if max == 0 {
+		return template.HTML(`<p class="muted">No records processed.</p>`)
+	}
+
+	height := len(reports)*rowHeight + 40
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %d %d" class="chart" role="img">`, labelWidth+barWidth+80, height)
+	for i, r := range reports {
+		y := i*rowHeight + 4
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, labelWidth-8, y+15, html.EscapeString(r.Migration))
+		x := float64(labelWidth)
+		for _, c := range r.Outcomes {
+			width := float64(c.Count) / float64(max) * barWidth
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"><title>%s: %d</title></rect>`,
+				x, y, width, rowHeight-8, outcomeColor(c.Name), html.EscapeString(label(c.Name)), c.Count)
+			x += width
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d">%d</text>`, x+6, y+15, r.Processed())
+	}
+
+	x := labelWidth
+	y := len(reports)*rowHeight + 20
+	for _, name := range legend {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/><text x="%d" y="%d">%s</text>`,
+			x, y, outcomeColor(name), x+16, y+11, html.EscapeString(label(name)))
+		x += 30 + 7*len(name)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+func outcomeColor(name string) string {
+	if color, ok := outcomeColors[name]; ok {
+		return color
+	}
+	return otherColor
+}
+
+// segment adalah rentang waktu dengan throughput rata-rata tetap
+type segment struct {
+	start, end time.Time
+	throughput float64
+}
+
+// segments memecah migrasi per fase. Fase berjalan berurutan sejak migrasi mulai,
+// migrasi tanpa fase dianggap satu rentang dengan throughput rata-ratanya.
+func segments(r *Report) []segment {
+	if len(r.Phases) == 0 {
+		return []segment{{r.StartedAt, r.FinishedAt, r.Throughput}}
+	}
+	var out []segment
+	start := r.StartedAt
+	for _, phase := range r.Phases {
+		end := start.Add(time.Duration(phase.DurationMs) * time.Millisecond)
+		out = append(out, segment{start, end, phase.Throughput})
+		start = end
+	}
+	return out
+}
+
+// throughputChart menggambar total records per detik semua migrasi sepanjang run
+func throughputChart(run *Run, reports []*Report) template.HTML {
+	const width, height, left, bottom = 760, 220, 70, 30
+
+	total := run.FinishedAt.Sub(run.StartedAt)
+	if total <= 0 || len(reports) == 0 {
+		return template.HTML(`<p class="muted">No timing data.</p>`)
+	}
+
+	bucket := total / throughputBuckets
+	if bucket <= 0 {
+		bucket = time.Millisecond
+	}
+	values := make([]float64, throughputBuckets)
+	for _, r := range reports {
+		for _, s := range segments(r) {
+			for i := range values {
+				from := run.StartedAt.Add(time.Duration(i) * bucket)
+				to := from.Add(bucket)
+				overlap := minTime(to, s.end).Sub(maxTime(from, s.start))
+				if overlap > 0 {
+					values[i] += s.throughput * float64(overlap) / float64(bucket)
+				}
+			}
+		}
+	}
+
+	max := 0.0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	plotWidth := float64(width - left - 10)
+	plotHeight := float64(height - bottom - 10)
+	var points []string
+	points = append(points, fmt.Sprintf("%d,%.1f", left, 10+plotHeight))
+	for i, v := range values {
+		x := float64(left) + (float64(i)+0.5)/throughputBuckets*plotWidth
+		y := 10 + plotHeight - v/max*plotHeight
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	points = append(points, fmt.Sprintf("%.1f,%.1f", float64(left)+plotWidth, 10+plotHeight))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %d %d" class="chart" role="img">`, width, height)
+	fmt.Fprintf(&b, `<line x1="%d" y1="10" x2="%d" y2="%.1f" class="axis"/>`, left, left, 10+plotHeight)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" class="axis"/>`, left, 10+plotHeight, float64(left)+plotWidth, 10+plotHeight)
+	fmt.Fprintf(&b, `<polygon points="%s" fill="#1e88e5" fill-opacity="0.25" stroke="#1e88e5"/>`, strings.Join(points, " "))
+	fmt.Fprintf(&b, `<text x="%d" y="20" text-anchor="end">%.0f/s</text>`, left-6, max)
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">0</text>`, left-6, 10+plotHeight)
+	for i := 0; i <= 4; i++ {
+		x := float64(left) + float64(i)/4*plotWidth
+		offset := (total * time.Duration(i) / 4).Round(time.Second)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">+%s</text>`, x, height-8, offset)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// timelineChart menggambar kapan setiap migrasi berjalan di dalam run
+func timelineChart(run *Run, reports []*Report) template.HTML {
+	const labelWidth, barWidth, rowHeight = 180, 560, 22
+
+	total := run.FinishedAt.Sub(run.StartedAt)
+	if total <= 0 || len(reports) == 0 {
+		return template.HTML(`<p class="muted">No timing data.</p>`)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %d %d" class="chart" role="img">`, labelWidth+barWidth+80, len(reports)*rowHeight+8)
+	for i, r := range reports {
+		y := i*rowHeight + 4
+		x := float64(labelWidth) + float64(r.StartedAt.Sub(run.StartedAt))/float64(total)*barWidth
+		width := float64(r.FinishedAt.Sub(r.StartedAt)) / float64(total) * barWidth
+		if width < 1 {
+			width = 1
+		}
+		color := outcomeColors[OutcomeInserted]
+		if r.Status != StatusCompleted {
+			color = outcomeColors[OutcomeFailed]
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, labelWidth-8, y+13, html.EscapeString(r.Migration))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"><title>%s</title></rect>`,
+			x, y, width, rowHeight-6, color, r.Duration().Round(time.Second))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d">%s</text>`, x+width+6, y+13, r.Duration().Round(time.Second))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"label": label,
+	"duration": func(ms int64) string {
+		return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+	},
+	"time": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04:05 MST")
+	},
+}).Parse(htmlSource))
+
+const htmlSource = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Migration report {{.Run.RunID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 1100px; color: #222; padding: 0 1rem; }
+h1 { margin-bottom: 0.2rem; }
+h2 { margin-top: 2.5rem; border-bottom: 1px solid #ddd; padding-bottom: 0.3rem; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9rem; }
+th, td { text-align: left; padding: 0.35rem 0.6rem; border-bottom: 1px solid #eee; vertical-align: top; }
+th { background: #f6f6f6; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+.muted { color: #777; }
+.status { font-weight: 600; }
+.status.completed, .status.done { color: #2e7d32; }
+.status.completed_with_errors, .status.failed, .status.aborted, .status.invariants_violated { color: #e53935; }
+.chart { width: 100%; height: auto; font-size: 12px; }
+.chart .axis { stroke: #999; }
+.cards { display: flex; flex-wrap: wrap; gap: 1rem; margin: 1rem 0; }
+.card { border: 1px solid #ddd; border-radius: 6px; padding: 0.6rem 1rem; min-width: 140px; }
+.card b { display: block; font-size: 1.3rem; }
+input.search { padding: 0.4rem; width: 300px; margin-bottom: 0.6rem; }
+details { margin: 0.6rem 0; }
+summary { cursor: pointer; font-weight: 600; }
+</style>
+</head>
+<body>
+<h1>Migration report</h1>
+<p class="muted">Run {{.Run.RunID}}, generated {{time .Generated}}</p>
+
+<div class="cards">
+<div class="card">Status<b class="status {{.Run.Status}}">{{label .Run.Status}}</b></div>
+<div class="card">Migrations<b>{{len .Run.Migrations}}</b></div>
+<div class="card">Duration<b>{{.Duration}}</b></div>
+<div class="card">Git version<b>{{.Run.GitVersion}}</b></div>
+</div>
+
+<h2>Summary</h2>
+<table>
+<thead><tr><th>Migration</th><th>Status</th><th class="num">Total</th><th class="num">Processed</th><th>Outcomes</th><th class="num">Duration</th><th class="num">Records/s</th><th class="num">Errors</th><th class="num">Lossy</th></tr></thead>
+<tbody>
+{{range .Reports}}<tr>
+<td>{{.Migration}}</td>
+<td class="status {{.Status}}">{{label .Status}}</td>
+<td class="num">{{.Total}}</td>
+<td class="num">{{.Processed}}</td>
+<td>{{range .Outcomes}}{{label .Name}}: {{.Count}}<br>{{end}}</td>
+<td class="num">{{duration .DurationMs}}</td>
+<td class="num">{{printf "%.2f" .Throughput}}</td>
+<td class="num">{{.ErrorCount}}</td>
+<td class="num">{{.LossyChanges}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{range .Run.Migrations}}{{if not .Report}}<p class="muted">{{.Name}}: {{.Status}}{{if .Error}} ({{.Error}}){{end}}, no report written.</p>{{end}}{{end}}
+
+<h2>Outcomes</h2>
+{{.OutcomeChart}}
+
+<h2>Throughput over time</h2>
+{{.ThroughputChart}}
+
+<h2>Timeline</h2>
+{{.TimelineChart}}
+
+<h2>Reconciliation</h2>
+{{if .Reconcile}}
+<p class="muted">Checked {{time .Reconcile.CheckedAt}}</p>
+<table>
+<thead><tr><th>Entity</th><th>Key</th><th class="num">Source</th><th class="num">Target</th><th class="num">Matched</th><th class="num">Missing</th><th class="num">Extra</th><th class="num">Duplicate keys</th></tr></thead>
+<tbody>
+{{range .Reconcile.Entities}}<tr>
+<td>{{.Entity}}<br><span class="muted">{{.Description}}</span></td>
+<td>{{.Key}}</td>
+<td class="num">{{.SourceCount}}</td>
+<td class="num">{{.TargetCount}}</td>
+<td class="num">{{.Matched}}</td>
+<td class="num">{{.Missing}}</td>
+<td class="num">{{.Extra}}</td>
+<td class="num">{{.SourceDuplicates}} / {{.TargetDuplicates}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{range .Reconcile.Entities}}{{if or .MissingRecords .ExtraRecords}}
+<details><summary>{{.Entity}}: {{.Missing}} missing, {{.Extra}} extra</summary>
+<table>
+<thead><tr><th>Side</th><th>Key</th><th>Detail</th></tr></thead>
+<tbody>
+{{range .MissingRecords}}<tr><td>missing in target</td><td>{{.Key}}</td><td>{{.Detail}}</td></tr>
+{{end}}{{range .ExtraRecords}}<tr><td>extra in target</td><td>{{.Key}}</td><td>{{.Detail}}</td></tr>
+{{end}}</tbody>
+</table>
+</details>
+{{end}}{{end}}
+{{else}}
+<p class="muted">No reconciliation saved for this run. Run <code>reconcile --run-id {{.Run.RunID}} all</code> and generate the report again.</p>
+{{end}}
+
+<h2>Duplicates, placeholders and lists</h2>
+{{if .Items}}
+<input class="search" type="search" placeholder="Search..." data-table="items">
+<table id="items">
+<thead><tr><th>Migration</th><th>Kind</th><th>Item</th></tr></thead>
+<tbody>
+{{range .Items}}<tr><td>{{.Migration}}</td><td>{{.Kind}}</td><td>{{.Item}}</td></tr>
+{{end}}</tbody>
+</table>
+{{else}}
+<p class="muted">None.</p>
+{{end}}
+
+<h2>Errors</h2>
+{{if .Errors}}
+<input class="search" type="search" placeholder="Search..." data-table="errors">
+<table id="errors">
+<thead><tr><th>Migration</th><th>Error</th></tr></thead>
+<tbody>
+{{range .Errors}}<tr><td>{{.Migration}}</td><td>{{.Item}}</td></tr>
+{{end}}</tbody>
+</table>
+{{else}}
+<p class="muted">None.</p>
+{{end}}
+
+<h2>Details per migration</h2>
+{{range .Reports}}
+<details><summary>{{.Migration}}</summary>
+<p class="muted">{{time .StartedAt}} to {{time .FinishedAt}}</p>
+{{if .Writes}}<table>
+<thead><tr><th>Table</th><th class="num">Inserted</th><th class="num">Updated</th></tr></thead>
+<tbody>{{range .Writes}}<tr><td>{{.Table}}</td><td class="num">{{.Inserted}}</td><td class="num">{{.Updated}}</td></tr>{{end}}</tbody>
+</table>{{end}}
+{{if .Phases}}<table>
+<thead><tr><th>Phase</th><th class="num">Duration</th><th class="num">Records</th><th class="num">Records/s</th></tr></thead>
+<tbody>{{range .Phases}}<tr><td>{{.Name}}</td><td class="num">{{duration .DurationMs}}</td><td class="num">{{.Records}}</td><td class="num">{{printf "%.2f" .Throughput}}</td></tr>{{end}}</tbody>
+</table>{{end}}
+{{if .Counters}}<table>
+<thead><tr><th>Counter</th><th class="num">Value</th></tr></thead>
+<tbody>{{range .Counters}}<tr><td>{{label .Name}}</td><td class="num">{{.Count}}</td></tr>{{end}}</tbody>
+</table>{{end}}
+</details>
+{{end}}
+
+<h2>Run metadata</h2>
+<table>
+<tbody>
+<tr><th>Run id</th><td>{{.Run.RunID}}</td></tr>
+<tr><th>Started</th><td>{{time .Run.StartedAt}}</td></tr>
+<tr><th>Finished</th><td>{{time .Run.FinishedAt}}</td></tr>
+<tr><th>Git version</th><td>{{.Run.GitVersion}}</td></tr>
+{{with .Run.Snapshot}}<tr><th>Source snapshot</th><td>{{.ID}} (LSN {{.LSN}}, taken {{time .TakenAt}})</td></tr>{{end}}
+{{$values := .ConfigValues}}{{range .ConfigKeys}}<tr><th>{{.}}</th><td>{{index $values .}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<script>
+document.querySelectorAll("input.search").forEach(function (input) {
+  input.addEventListener("input", function () {
+    var query = input.value.toLowerCase();
+    document.querySelectorAll("#" + input.dataset.table + " tbody tr").forEach(function (row) {
+      row.style.display = row.textContent.toLowerCase().indexOf(query) < 0 ? "none" : "";
+    });
+  });
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxReconcileRecords membatasi missing/extra yang disimpan per entitas, jumlahnya tetap lengkap
+const maxReconcileRecords = 1000
+
+// ReconcileRecord adalah satu key yang tidak cocok
+type ReconcileRecord struct {
+	Key    string `json:"key"`
+	Detail string `json:"detail"`
+}
+
+// Reconciliation adalah hasil rekonsiliasi satu entitas
+type Reconciliation struct {
+	Entity           string            `json:"entity"`
+	Description      string            `json:"description"`
+	Key              string            `json:"key"`
+	Source           string            `json:"source"`
+	Target           string            `json:"target"`
+	SourceCount      int               `json:"source_count"`
+	TargetCount      int               `json:"target_count"`
+	Matched          int               `json:"matched"`
+	Missing          int               `json:"missing"`
+	Extra            int               `json:"extra"`
+	SourceDuplicates int               `json:"source_duplicates"`
+	TargetDuplicates int               `json:"target_duplicates"`
+	DurationMs       int64             `json:"duration_ms"`
+	MissingRecords   []ReconcileRecord `json:"missing_records,omitempty"`
+	ExtraRecords     []ReconcileRecord `json:"extra_records,omitempty"`
+}
+
+// ReconcileRun adalah hasil command reconcile yang disimpan di folder run
+type ReconcileRun struct {
+	RunID     string           `json:"run_id"`
+	CheckedAt time.Time        `json:"checked_at"`
+	Entities  []Reconciliation `json:"entities"`
+}
+
+// ReconcilePath mengembalikan lokasi reconcile.json sebuah run
+func ReconcilePath(runID string) string {
+	return filepath.Join(Dir(), runID, "reconcile.json")
+}
+
+// ReconcileRecords memotong daftar record ke maxReconcileRecords
+func ReconcileRecords(records []ReconcileRecord) []ReconcileRecord {
+	if len(records) > maxReconcileRecords {
+		return records[:maxReconcileRecords]
+	}
+	return records
+}
+
+// WriteReconciliation menulis reconcile.json
+func WriteReconciliation(run *ReconcileRun) (string, error) {
+	path := ReconcilePath(run.RunID)
+	return path, writeJSON(path, run)
+}
+
+// LoadReconciliation membaca reconcile.json sebuah run. Hasilnya nil tanpa error
+// jika reconcile belum pernah dijalankan dengan --run-id untuk run tersebut.
+func LoadReconciliation(runID string) (*ReconcileRun, error) {
+	data, err := os.ReadFile(ReconcilePath(runID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var run ReconcileRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", ReconcilePath(runID), err)
+	}
+	return &run, nil
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Render mencetak ringkasan migrasi dari report
+func Render(w io.Writer, r *Report) {
+	title := i18n.T("summary.title")
+	if r.Migration != "" {
+		title += ": " + r.Migration
+	}
+	fmt.Fprint(w, Heading(title, "-"))
+
+	fmt.Fprintln(w, i18n.T("summary.total", r.Total))
+	for _, c := range r.Outcomes {
+		fmt.Fprintf(w, "%s: %d\n", label(c.Name), c.Count)
+	}
+	for _, c := range r.Counters {
+		fmt.Fprintf(w, "%s: %d\n", label(c.Name), c.Count)
+	}
+
+	if len(r.Writes) > 0 {
+		fmt.Fprintf(w, "\n%s\n", i18n.T("summary.writes"))
+		for _, write := range r.Writes {
+			fmt.Fprintf(w, "  %-22s %s\n", write.Table, i18n.T("summary.write", write.Inserted, write.Updated))
+		}
+	}
+
+	if len(r.Phases) > 1 {
+		fmt.Fprintf(w, "\n%s\n", i18n.T("summary.phases"))
+		for _, phase := range r.Phases {
+			fmt.Fprintf(w, "  %-22s %8s  %s\n", phase.Name, (time.Duration(phase.DurationMs) * time.Millisecond).Round(time.Second),
+				i18n.T("summary.phase", phase.Records, phase.Throughput))
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s\n", i18n.T("summary.duration", r.Duration().Round(time.Second)))
+	fmt.Fprintln(w, i18n.T("summary.speed", r.Throughput))
+	if r.LossyChanges > 0 {
+		fmt.Fprintln(w, i18n.T("summary.lossy", r.LossyChanges))
+	}
+
+	printItems(w, i18n.T("summary.duplicates"), r.Duplicates)
+	printItems(w, i18n.T("summary.placeholders"), r.Placeholders)
+	for _, list := range r.Lists {
+		title := list.Title
+		if i18n.Has("list." + title) {
+			title = i18n.T("list." + title)
+		}
+		printItems(w, title, list.Items)
+	}
+
+	if r.ErrorCount > 0 {
+		fmt.Fprintf(w, "\n%s\n", i18n.T("summary.errors", r.ErrorCount))
+		for i, message := range r.Errors {
+			fmt.Fprintf(w, "%d. %s\n", i+1, message)
+		}
+		if r.ErrorCount > len(r.Errors) {
+			fmt.Fprintln(w, i18n.T("summary.more_errors", r.ErrorCount-len(r.Errors)))
+		}
+	}
+}
+
+func printItems(w io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s (%d):\n", title, len(items))
+	for i, item := range items {
+		fmt.Fprintf(w, "%d. %s\n", i+1, item)
+	}
+}
+
+// Heading menyusun judul bergaris bawah, misalnya untuk ringkasan dan fase migrasi
+func Heading(title, underline string) string {
+	return fmt.Sprintf("\n%s\n%s\n", title, strings.Repeat(underline, utf8.RuneCountInString(title)))
+}
+
+// label mengubah nama outcome/counter menjadi teks console dari katalog, atau dari
+// namanya jika belum diterjemahkan, misalnya "duplicate_phones" -> "Duplicate phones"
+func label(name string) string {
+	if i18n.Has("label." + name) {
+		return i18n.T("label." + name)
+	}
+	name = strings.ReplaceAll(name, "_", " ")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,362 @@
+package report
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/ledger"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/notify"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDir adalah folder report jika REPORT_DIR tidak diisi
+const DefaultDir = "reports"
+
+// maxErrors membatasi pesan error yang disimpan, jumlah totalnya tetap di ErrorCount
+const maxErrors = 100
+
+// configKeys adalah konfigurasi yang dicatat di report. Password dan DSN tidak ikut.
+var configKeys = []string{
+	"PROD_EXISTING_DB_HOST", "PROD_EXISTING_DB_NAME",
+	"LOCAL_DB_HOST", "LOCAL_IDENTITY_DB_NAME", "LOCAL_UMRAH_DB_NAME", "LOCAL_GENERAL_DB_NAME",
+	"DEV_DB_HOST", "DEV_IDENTITY_DB_NAME", "DEV_UMRAH_DB_NAME", "DEV_GENERAL_DB_NAME",
+	"PROD_DB_HOST", "PROD_IDENTITY_DB_NAME", "PROD_UMRAH_DB_NAME", "PROD_GENERAL_DB_NAME",
+	"PROD_EXISTING_DB_MAX_ROWS_PER_SECOND", "PROD_EXISTING_DB_MAX_CONCURRENT_QUERIES",
+	"PROD_EXISTING_DB_STATEMENT_TIMEOUT", "DB_MAX_OPEN_CONNS", "LEDGER_DIR", "REPORT_DIR",
+	"TRACE_EXPORTER",
+}
+
+// Satu proses menjalankan satu migrasi, jadi report disimpan di level package
+// supaya service dan helper-nya bisa mencatat tanpa meneruskan parameter.
+var (
+	mu       sync.Mutex
+	current  = newReport("")
+	finished bool
+)
+
+func newReport(migration string) *Report {
+	return &Report{
+		RunID:     RunID(),
+		Migration: migration,
+		StartedAt: time.Now(),
+	}
+}
+
+// RunID mengembalikan id run dari MIGRATION_RUN_ID, diisi command run supaya
+// semua migrasi satu run memakai id yang sama
+func RunID() string {
+	if id := os.Getenv("MIGRATION_RUN_ID"); id != "" {
+		return id
+	}
+	return NewRunID()
+}
+
+// NewRunID membuat id run baru dari waktu sekarang sampai milidetik ditambah
+// akhiran acak, karena id dipakai sebagai primary key migration_history dan dua
+// run bisa dimulai pada detik yang sama. Urutan string tetap urutan waktu.
+func NewRunID() string {
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	return time.Now().Format("20060102T150405.000") + "-" + hex.EncodeToString(suffix)
+}
+
+// Dir mengembalikan folder report dari REPORT_DIR
+func Dir() string {
+	if dir := os.Getenv("REPORT_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultDir
+}
+
+// Path mengembalikan lokasi file report satu migrasi
+func Path(runID, migration string) string {
+	return filepath.Join(Dir(), runID, migration+".json")
+}
+
+// Start memulai report baru untuk migrasi
+func Start(migration string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = newReport(migration)
+	finished = false
+}
+
+// SetTotal mencatat jumlah record sumber yang akan diproses
+func SetTotal(total int) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Total = total
+	metrics.Set("migration_records_expected", "Source records the migration is going to process.", float64(total))
+}
+
+// Add menambah jumlah outcome, misalnya report.Add(report.OutcomeSkipped, 1)
+func Add(outcome string, n int) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Outcomes = addCount(current.Outcomes, outcome, n)
+	metrics.Add("migration_records_total", "Source records processed, by outcome.", float64(n), "outcome", outcome)
+	metrics.Set("migration_position_records", "Source records processed so far (current position of the run).", float64(current.Processed()))
+}
+
+// Counter menambah angka tambahan yang tidak termasuk outcome
+func Counter(name string, n int) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Counters = addCount(current.Counters, name, n)
+}
+
+// Inserted mencatat baris yang di-insert ke tabel target
+func Inserted(table string, n int) {
+	mu.Lock()
+	defer mu.Unlock()
+	tableWrite(table).Inserted += n
+	metrics.Add("migration_rows_written_total", "Rows written to target tables.", float64(n), "table", table, "operation", "insert")
+}
+
+// Updated mencatat baris yang di-update di tabel target
+func Updated(table string, n int) {
+	mu.Lock()
+	defer mu.Unlock()
+	tableWrite(table).Updated += n
+	metrics.Add("migration_rows_written_total", "Rows written to target tables.", float64(n), "table", table, "operation", "update")
+}
+
+// AddPhase mencatat durasi satu tahap migrasi
+func AddPhase(name string, duration time.Duration, records int) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Phases = append(current.Phases, Phase{
+		Name:       name,
+		DurationMs: duration.Milliseconds(),
+		Records:    records,
+		Throughput: throughput(records, duration),
+	})
+}
+
+// Duplicate mencatat record yang dilewati atau diubah karena duplikat
+func Duplicate(item string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Duplicates = append(current.Duplicates, item)
+}
+
+// Placeholder mencatat nilai pengganti yang diisi karena data sumber tidak ada
+func Placeholder(item string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Placeholders = append(current.Placeholders, item)
+}
+
+// Item menambah satu baris ke daftar detail dengan judul title
+func Item(title, item string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i := range current.Lists {
+		if current.Lists[i].Title == title {
+			current.Lists[i].Items = append(current.Lists[i].Items, item)
+			return
+		}
+	}
+	current.Lists = append(current.Lists, List{Title: title, Items: []string{item}})
+}
+
+// Errorf mencetak error ke log dan mencatatnya di report
+func Errorf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	slog.Error(message)
+
+	mu.Lock()
+	current.ErrorCount++
+	if len(current.Errors) < maxErrors {
+		current.Errors = append(current.Errors, message)
+	}
+	count, migration, startedAt := current.ErrorCount, current.Migration, current.StartedAt
+	mu.Unlock()
+
+	// Notifikasi dikirim sekali, saat jumlah error tepat mencapai batas
+	if threshold := notify.ErrorThreshold(); threshold > 0 && count == threshold {
+		notify.Send(notify.Event{
+			Event:      notify.EventErrorThreshold,
+			RunID:      RunID(),
+			StartedAt:  startedAt,
+			Migration:  migration,
+			ErrorCount: count,
+			Threshold:  threshold,
+			Report:     absPath(Path(RunID(), migration)),
+		})
+	}
+}
+
+// Finish menutup report, menulis file JSON dan mencetak ringkasannya
+func Finish() {
+	r, path, err := finish(StatusCompleted)
+	if r == nil {
+		return
+	}
+
+	Render(os.Stdout, r)
+	if err != nil {
+		slog.Error("Error writing run report", "error", err)
+		return
+	}
+	fmt.Println(i18n.T("report.written", path))
+}
+
+// Close menulis report dengan status aborted jika service berhenti tanpa memanggil Finish.
+// Hasilnya true jika report aborted, misalnya service return setelah commit gagal.
+func Close() bool {
+	r, path, err := finish(StatusAborted)
+	if err != nil {
+		slog.Error("Error writing run report", "error", err)
+	} else if path != "" {
+		fmt.Println(i18n.T("report.partial", path))
+	}
+	return r != nil
+}
+
+func finish(status string) (*Report, string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if finished || current.Migration == "" && status == StatusAborted {
+		return nil, "", nil
+	}
+	finished = true
+
+	r := current
+	r.FinishedAt = time.Now()
+	r.DurationMs = r.FinishedAt.Sub(r.StartedAt).Milliseconds()
+	r.Throughput = throughput(r.Processed(), r.FinishedAt.Sub(r.StartedAt))
+	r.Status = status
+	if status == StatusCompleted && (r.ErrorCount > 0 || r.Outcome(OutcomeFailed) > 0) {
+		r.Status = StatusCompletedWithErrors
+	}
+	r.GitVersion = GitVersion()
+	r.LossyChanges = ledger.Count()
+	r.Config = make(map[string]string)
+	for _, key := range configKeys {
+		if value := os.Getenv(key); value != "" {
+			r.Config[key] = value
+		}
+	}
+	if os.Getenv("PROD_EXISTING_DB_REPLICA_DSN") != "" {
+		r.Config["PROD_EXISTING_DB_REPLICA"] = "true"
+	}
+	r.Snapshot = SourceSnapshot()
+
+	migration := r.Migration
+	if migration == "" {
+		migration = "unnamed"
+	}
+	path := Path(r.RunID, migration)
+	return r, path, writeJSON(path, r)
+}
+
+// SourceSnapshot mengembalikan snapshot sumber proses ini, nil jika sumber tidak dibuka
+func SourceSnapshot() *Snapshot {
+	snapshot := database.CurrentSourceSnapshot()
+	if snapshot == nil {
+		return nil
+	}
+	return &Snapshot{ID: snapshot.ID, LSN: snapshot.LSN, TakenAt: snapshot.TakenAt, Imported: snapshot.Imported}
+}
+
+// Load membaca file report
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return &r, nil
+}
+
+// GitVersion mengembalikan commit binary ini, dari build info atau git jika dijalankan dengan go run
+func GitVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision, modified string
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value
+			}
+		}
+		if revision != "" {
+			if len(revision) > 12 {
+				revision = revision[:12]
+			}
+			if modified == "true" {
+				revision += "-dirty"
+			}
+			return revision
+		}
+	}
+
+	out, err := exec.Command("git", "describe", "--always", "--dirty").Output()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// absPath mengembalikan path absolut untuk notifikasi, atau path asli jika gagal
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func writeJSON(path string, value interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func addCount(counts []Count, name string, n int) []Count {
+	for i := range counts {
+		if counts[i].Name == name {
+			counts[i].Count += n
+			return counts
+		}
+	}
+	return append(counts, Count{Name: name, Count: n})
+}
+
+func tableWrite(table string) *TableWrite {
+	for i := range current.Writes {
+		if current.Writes[i].Table == table {
+			return &current.Writes[i]
+		}
+	}
+	current.Writes = append(current.Writes, TableWrite{Table: table})
+	return &current.Writes[len(current.Writes)-1]
+}
+
+func throughput(records int, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	return float64(records) / duration.Seconds()
+}
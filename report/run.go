@@ -0,0 +1,72 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MigrationResult adalah status satu migrasi di run.json
+type MigrationResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	Report     string `json:"report,omitempty"` // nama file report migrasi, relatif ke folder run
+}
+
+// Run adalah ringkasan satu command run yang menjalankan beberapa migrasi
+type Run struct {
+	RunID      string            `json:"run_id"`
+	Status     string            `json:"status"`
+	GitVersion string            `json:"git_version"`
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+	DurationMs int64             `json:"duration_ms"`
+	Snapshot   *Snapshot         `json:"source_snapshot,omitempty"`
+	Migrations []MigrationResult `json:"migrations"`
+}
+
+// RunPath mengembalikan lokasi run.json
+func RunPath(runID string) string {
+	return filepath.Join(Dir(), runID, "run.json")
+}
+
+// WriteRun menulis run.json
+func WriteRun(run *Run) (string, error) {
+	path := RunPath(run.RunID)
+	return path, writeJSON(path, run)
+}
+
+// LoadRun membaca run.json
+func LoadRun(runID string) (*Run, error) {
+	data, err := os.ReadFile(RunPath(runID))
+	if err != nil {
+		return nil, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", RunPath(runID), err)
+	}
+	return &run, nil
+}
+
+// LatestRunID mengembalikan run terbaru yang punya run.json. Id run berupa
+// timestamp, jadi urutan nama folder sama dengan urutan waktu.
+func LatestRunID() (string, error) {
+	entries, err := os.ReadDir(Dir())
+	if err != nil {
+		return "", err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].IsDir() {
+			continue
+		}
+		if _, err := os.Stat(RunPath(entries[i].Name())); err == nil {
+			return entries[i].Name(), nil
+		}
+	}
+	return "", fmt.Errorf("no run found in %s", Dir())
+}
@@ -0,0 +1,105 @@
+package report
+
+import "time"
+
+// Outcome standar untuk hasil setiap record sumber
+const (
+	OutcomeTransferred = "transferred"
+	OutcomeInserted    = "inserted"
+	OutcomeUpdated     = "updated"
+	OutcomeSkipped     = "skipped"
+	OutcomeFailed      = "failed"
+)
+
+// Status akhir report
+const (
+	StatusCompleted           = "completed"
+	StatusCompletedWithErrors = "completed_with_errors"
+	StatusAborted             = "aborted"             // service berhenti sebelum memanggil Finish
+	StatusInvariantsViolated  = "invariants_violated" // run selesai tapi invariant dilanggar
+)
+
+// Count adalah satu angka bernama. Urutan mengikuti urutan pertama kali dicatat.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TableWrite adalah jumlah baris yang ditulis ke satu tabel target
+type TableWrite struct {
+	Table    string `json:"table"`
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+}
+
+// Phase adalah durasi satu tahap migrasi
+type Phase struct {
+	Name       string  `json:"name"`
+	DurationMs int64   `json:"duration_ms"`
+	Records    int     `json:"records"`
+	Throughput float64 `json:"throughput"` // records per detik
+}
+
+// List adalah daftar detail, misalnya slug yang di-generate atau hotel tanpa master
+type List struct {
+	Title string   `json:"title"`
+	Items []string `json:"items"`
+}
+
+// Snapshot adalah snapshot sumber yang dibaca migrasi
+type Snapshot struct {
+	ID       string    `json:"id"`
+	LSN      string    `json:"lsn"`
+	TakenAt  time.Time `json:"taken_at"`
+	Imported bool      `json:"imported"`
+}
+
+// Report adalah hasil satu migrasi. File JSON dan ringkasan di console
+// dibuat dari struct yang sama.
+type Report struct {
+	RunID        string            `json:"run_id"`
+	Migration    string            `json:"migration"`
+	Status       string            `json:"status"`
+	GitVersion   string            `json:"git_version"`
+	StartedAt    time.Time         `json:"started_at"`
+	FinishedAt   time.Time         `json:"finished_at"`
+	DurationMs   int64             `json:"duration_ms"`
+	Throughput   float64           `json:"throughput"` // records per detik dari semua outcome
+	Total        int               `json:"total"`      // record sumber yang akan diproses
+	Outcomes     []Count           `json:"outcomes"`   // tidak saling tumpang tindih, jumlahnya = record yang diproses
+	Counters     []Count           `json:"counters"`   // angka tambahan, boleh tumpang tindih dengan outcome
+	Writes       []TableWrite      `json:"writes"`
+	Phases       []Phase           `json:"phases"`
+	Duplicates   []string          `json:"duplicates"`
+	Placeholders []string          `json:"placeholders"`
+	Lists        []List            `json:"lists"`
+	ErrorCount   int               `json:"error_count"`
+	Errors       []string          `json:"errors"` // dibatasi maxErrors pesan pertama
+	LossyChanges int               `json:"lossy_changes"`
+	Snapshot     *Snapshot         `json:"source_snapshot,omitempty"`
+	Config       map[string]string `json:"config"`
+}
+
+// Outcome mengembalikan jumlah outcome tertentu
+func (r *Report) Outcome(name string) int {
+	for _, c := range r.Outcomes {
+		if c.Name == name {
+			return c.Count
+		}
+	}
+	return 0
+}
+
+// Processed adalah jumlah semua outcome
+func (r *Report) Processed() int {
+	processed := 0
+	for _, c := range r.Outcomes {
+		processed += c.Count
+	}
+	return processed
+}
+
+// Duration mengembalikan durasi report
+func (r *Report) Duration() time.Duration {
+	return time.Duration(r.DurationMs) * time.Millisecond
+}
@@ -0,0 +1,192 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	redrawInterval      = 100 * time.Millisecond
+	plainStatusInterval = 10 * time.Second
+)
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// console menggabungkan output beberapa migrasi yang berjalan bersamaan.
+// Baris log dicetak utuh dengan prefix nama migrasi, sedangkan progress bar
+// tiap migrasi ditampilkan sebagai satu baris status di bagian bawah layar.
+// Jika stdout bukan terminal, status hanya dicetak berkala sebagai baris biasa.
+type console struct {
+	mu        sync.Mutex
+	out       io.Writer
+	tty       bool
+	width     int
+	nameWidth int
+
+	order      []string
+	status     map[string]string
+	rendered   int
+	lastDraw   time.Time
+	lastStatus map[string]time.Time
+}
+
+func newConsole(names []string) *console {
+	c := &console{
+		out:        os.Stdout,
+		tty:        term.IsTerminal(int(os.Stdout.Fd())),
+		width:      80,
+		status:     make(map[string]string),
+		lastStatus: make(map[string]time.Time),
+	}
+	if c.tty {
+		if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+			c.width = width
+		}
+	}
+	for _, name := range names {
+		if len(name) > c.nameWidth {
+			c.nameWidth = len(name)
+		}
+	}
+	return c
+}
+
+// Log mencetak satu baris milik migrasi tertentu
+func (c *console) Log(name, line string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.tty {
+		line = ansiPattern.ReplaceAllString(line, "")
+	}
+
+	c.clear()
+	fmt.Fprintf(c.out, "[%-*s] %s\n", c.nameWidth, name, line)
+	c.draw()
+}
+
+// Status mengganti baris status (progress) migrasi tertentu
+func (c *console) Status(name, text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	text = strings.TrimSpace(ansiPattern.ReplaceAllString(text, ""))
+	if text == "" {
+		return
+	}
+
+	if _, exists := c.status[name]; !exists {
+		c.order = append(c.order, name)
+	}
+	c.status[name] = text
+
+	if !c.tty {
+		if time.Since(c.lastStatus[name]) >= plainStatusInterval {
+			c.lastStatus[name] = time.Now()
+			fmt.Fprintf(c.out, "[%-*s] %s\n", c.nameWidth, name, text)
+		}
+		return
+	}
+
+	if time.Since(c.lastDraw) >= redrawInterval {
+		c.clear()
+		c.draw()
+	}
+}
+
+// Remove menghapus baris status migrasi yang sudah selesai
+func (c *console) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.status[name]; !exists {
+		return
+	}
+
+	c.clear()
+	delete(c.status, name)
+	for i, n := range c.order {
+		if n == name {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.draw()
+}
+
+func (c *console) clear() {
+	if !c.tty || c.rendered == 0 {
+		return
+	}
+	fmt.Fprintf(c.out, "\033[%dA\033[J", c.rendered)
+	c.rendered = 0
+}
+
+func (c *console) draw() {
+	if !c.tty {
+		return
+	}
+	for _, name := range c.order {
+		line := fmt.Sprintf("[%-*s] %s", c.nameWidth, name, c.status[name])
+		if runes := []rune(line); len(runes) >= c.width {
+			line = string(runes[:c.width-1])
+		}
+		fmt.Fprintln(c.out, line)
+	}
+	c.rendered = len(c.order)
+	c.lastDraw = time.Now()
+}
+
+// Writer mengembalikan io.Writer untuk output satu migrasi. Teks yang diakhiri
+// "\n" menjadi baris log, teks yang ditimpa dengan "\r" (progress bar) menjadi status.
+func (c *console) Writer(name string) *migrationWriter {
+	return &migrationWriter{console: c, name: name}
+}
+
+type migrationWriter struct {
+	console *console
+	name    string
+	buf     bytes.Buffer
+	inBar   bool // teks di buffer sedang menimpa baris yang sama (setelah "\r")
+}
+
+func (w *migrationWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		switch b {
+		case '\n':
+			if line := strings.TrimRight(w.buf.String(), " "); strings.TrimSpace(line) != "" {
+				w.console.Log(w.name, line)
+			}
+			w.buf.Reset()
+			w.inBar = false
+		case '\r':
+			w.console.Status(w.name, w.buf.String())
+			w.buf.Reset()
+			w.inBar = true
+		default:
+			w.buf.WriteByte(b)
+		}
+	}
+
+	// Progress bar tidak diakhiri newline, jadi render terakhir langsung ditampilkan
+	if w.inBar && w.buf.Len() > 0 {
+		w.console.Status(w.name, w.buf.String())
+	}
+	return len(p), nil
+}
+
+// Flush mencetak sisa output yang belum diakhiri newline
+func (w *migrationWriter) Flush() {
+	if strings.TrimSpace(w.buf.String()) != "" {
+		w.console.Log(w.name, w.buf.String())
+	}
+	w.buf.Reset()
+	w.console.Remove(w.name)
+}
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Migration adalah satu service migrasi beserta dependensi dan database yang disentuhnya
+type Migration struct {
+	Name      string
+	DependsOn []string // migrasi yang harus selesai dulu
+	Databases []string // database yang dipakai, untuk membatasi koneksi per database
+	Run       func()
+}
+
+// Find mencari migrasi berdasarkan nama
+func Find(migrations []Migration, name string) (Migration, bool) {
+	for _, m := range migrations {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+// Select mengambil migrasi yang dipilih. Dependensi yang tidak ikut dipilih
+// dianggap sudah dijalankan sebelumnya dan diabaikan.
+func Select(migrations []Migration, names []string) ([]Migration, error) {
+	if len(names) == 0 {
+		return migrations, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		if _, ok := Find(migrations, name); !ok {
+			return nil, fmt.Errorf("unknown migration: %s", name)
+		}
+		wanted[name] = true
+	}
+
+	var selected []Migration
+	for _, m := range migrations {
+		if !wanted[m.Name] {
+			continue
+		}
+		var deps []string
+		for _, dep := range m.DependsOn {
+			if wanted[dep] {
+				deps = append(deps, dep)
+			}
+		}
+		m.DependsOn = deps
+		selected = append(selected, m)
+	}
+
+	return selected, nil
+}
+
+// Levels mengelompokkan migrasi per tingkat dependensi. Migrasi dalam satu
+// tingkat tidak saling bergantung. Error jika ada dependensi tak dikenal atau siklus.
+func Levels(migrations []Migration) ([][]string, error) {
+	remaining := make(map[string][]string)
+	for _, m := range migrations {
+		if _, exists := remaining[m.Name]; exists {
+			return nil, fmt.Errorf("duplicate migration: %s", m.Name)
+		}
+		remaining[m.Name] = m.DependsOn
+	}
+	for _, m := range migrations {
+		for _, dep := range m.DependsOn {
+			if _, ok := remaining[dep]; !ok {
+				return nil, fmt.Errorf("migration %s depends on unknown migration %s", m.Name, dep)
+			}
+		}
+	}
+
+	done := make(map[string]bool)
+	var levels [][]string
+	for len(done) < len(migrations) {
+		var level []string
+		for _, m := range migrations {
+			if done[m.Name] || !depsDone(remaining[m.Name], done) {
+				continue
+			}
+			level = append(level, m.Name)
+		}
+
+		if len(level) == 0 {
+			var stuck []string
+			for _, m := range migrations {
+				if !done[m.Name] {
+					stuck = append(stuck, m.Name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between: %s", strings.Join(stuck, ", "))
+		}
+
+		for _, name := range level {
+			done[name] = true
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+func depsDone(deps []string, done map[string]bool) bool {
+	for _, dep := range deps {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func migration(name string, deps ...string) Migration {
+	return Migration{Name: name, DependsOn: deps}
+}
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		want       [][]string
+		wantErr    string
+	}{
+		{
+			name:       "independent migrations share one level",
+			migrations: []Migration{migration("a"), migration("b"), migration("c")},
+			want:       [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:       "chain",
+			migrations: []Migration{migration("c", "b"), migration("b", "a"), migration("a")},
+			want:       [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name: "diamond keeps declaration order inside a level",
+			migrations: []Migration{
+				migration("user"),
+				migration("organization", "user"),
+				migration("hotel", "user"),
+				migration("package", "organization", "hotel"),
+			},
+			want: [][]string{{"user"}, {"organization", "hotel"}, {"package"}},
+		},
+		{
+			name:       "unknown dependency",
+			migrations: []Migration{migration("a", "missing")},
+			wantErr:    "depends on unknown migration missing",
+		},
+		{
+			name:       "cycle",
+			migrations: []Migration{migration("a"), migration("b", "c"), migration("c", "b")},
+			wantErr:    "dependency cycle between: b, c",
+		},
+		{
+			name:       "duplicate",
+			migrations: []Migration{migration("a"), migration("a")},
+			wantErr:    "duplicate migration: a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Levels(tt.migrations)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Levels() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Levels() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Levels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	all := []Migration{migration("a"), migration("b", "a"), migration("c", "a", "b")}
+
+	tests := []struct {
+		name    string
+		names   []string
+		want    []Migration
+		wantErr string
+	}{
+		{
+			name:  "no names selects everything",
+			names: nil,
+			want:  all,
+		},
+		{
+			name:  "unselected dependencies are dropped",
+			names: []string{"c", "b"},
+			want:  []Migration{migration("b"), migration("c", "b")},
+		},
+		{
+			name:    "unknown name",
+			names:   []string{"a", "nope"},
+			wantErr: "unknown migration: nope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Select(all, tt.names)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Select() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
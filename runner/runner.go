@@ -0,0 +1,201 @@
+package runner
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/tracing"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ChildCommand adalah command yang dipakai runner untuk menjalankan satu migrasi
+// di proses terpisah. Setiap migrasi berjalan di prosesnya sendiri supaya
+// log.Fatal di satu service tidak menghentikan migrasi lain.
+const ChildCommand = "run-migration"
+
+// MinConnsPerMigration adalah batas bawah koneksi satu migrasi ke satu database.
+// Service membaca rows sambil menjalankan query lain ke database yang sama,
+// sehingga pool satu koneksi membuat migrasi tertahan.
+const MinConnsPerMigration = 2
+
+// Options mengatur paralelisme runner
+type Options struct {
+	MaxConnsPerDB     int // total koneksi ke satu database dari semua migrasi yang berjalan
+	ConnsPerMigration int // batas koneksi satu migrasi ke setiap database yang dipakainya
+}
+
+// Result adalah hasil satu migrasi
+type Result struct {
+	Name     string
+	Status   string // done, failed, skipped
+	Duration time.Duration
+	Err      error
+}
+
+type finished struct {
+	name     string
+	err      error
+	duration time.Duration
+}
+
+// Run menjalankan migrasi sesuai graph dependensi. Migrasi yang dependensinya
+// sudah selesai langsung dijalankan selama kuota koneksi tiap database masih cukup.
+// Migrasi yang dependensinya gagal tidak dijalankan.
+func Run(migrations []Migration, opts Options) ([]Result, error) {
+	if _, err := Levels(migrations); err != nil {
+		return nil, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("error locating executable: %v", err)
+	}
+
+	conns := opts.ConnsPerMigration
+	if opts.MaxConnsPerDB > 0 && conns > opts.MaxConnsPerDB {
+		conns = opts.MaxConnsPerDB
+	}
+
+	names := make([]string, len(migrations))
+	for i, m := range migrations {
+		names[i] = m.Name
+	}
+	out := newConsole(names)
+
+	results := make(map[string]*Result)
+	for _, m := range migrations {
+		results[m.Name] = &Result{Name: m.Name}
+	}
+
+	dbUsage := make(map[string]int)
+	running := 0
+	done := make(chan finished)
+
+	fits := func(m Migration) bool {
+		if opts.MaxConnsPerDB <= 0 {
+			return true
+		}
+		for _, db := range m.Databases {
+			if dbUsage[db]+conns > opts.MaxConnsPerDB {
+				return false
+			}
+		}
+		return true
+	}
+
+	start := func(m Migration) {
+		for _, db := range m.Databases {
+			dbUsage[db] += conns
+		}
+		running++
+		results[m.Name].Status = "running"
+		out.Log(m.Name, i18n.T("runner.started"))
+
+		go func() {
+			startTime := time.Now()
+			w := out.Writer(m.Name)
+
+			cmd := exec.Command(executable, ChildCommand, m.Name)
+			cmd.Stdout = w
+			cmd.Stderr = w
+			// Console runner sendiri yang menurunkan status ke baris biasa jika bukan terminal
+			cmd.Env = append(os.Environ(), "PROGRESS_MODE="+progress.ModeBar)
+			if conns > 0 {
+				cmd.Env = append(cmd.Env, fmt.Sprintf("DB_MAX_OPEN_CONNS=%d", conns))
+			}
+
+			// Span root proses migrasi menjadi anak span ini lewat TRACEPARENT
+			_, span := tracing.Start(tracing.Context(), "migration "+m.Name, "migration.name", m.Name, "db.max_open_conns", conns)
+			if span != nil {
+				cmd.Env = append(cmd.Env, "TRACEPARENT="+span.Traceparent())
+			}
+
+			err := cmd.Run()
+			span.SetError(err)
+			span.End()
+			w.Flush()
+			done <- finished{name: m.Name, err: err, duration: time.Since(startTime)}
+		}()
+	}
+
+	for {
+		// Tandai skipped migrasi yang dependensinya gagal atau ikut di-skip
+		for _, m := range migrations {
+			if results[m.Name].Status != "" {
+				continue
+			}
+			for _, dep := range m.DependsOn {
+				if status := results[dep].Status; status == "failed" || status == "skipped" {
+					results[m.Name].Status = "skipped"
+					results[m.Name].Err = fmt.Errorf("dependency %s %s", dep, status)
+					out.Log(m.Name, i18n.T("runner.skipped", dep, status))
+					break
+				}
+			}
+		}
+
+		for _, m := range migrations {
+			if results[m.Name].Status != "" || !dependenciesDone(m, results) || !fits(m) {
+				continue
+			}
+			start(m)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		f := <-done
+		running--
+		for _, db := range mustFind(migrations, f.name).Databases {
+			dbUsage[db] -= conns
+		}
+
+		result := results[f.name]
+		result.Duration = f.duration
+		if f.err != nil {
+			result.Status = "failed"
+			result.Err = f.err
+			out.Log(f.name, i18n.T("runner.failed", f.duration.Round(time.Second), f.err))
+		} else {
+			result.Status = "done"
+			out.Log(f.name, i18n.T("runner.done", f.duration.Round(time.Second)))
+		}
+	}
+
+	var ordered []Result
+	var failed []string
+	for _, m := range migrations {
+		result := results[m.Name]
+		if result.Status == "" {
+			// Tidak pernah bisa jalan, misalnya kuota koneksi lebih kecil dari kebutuhan
+			result.Status = "skipped"
+		}
+		if result.Status != "done" {
+			failed = append(failed, m.Name)
+		}
+		ordered = append(ordered, *result)
+	}
+
+	if len(failed) > 0 {
+		return ordered, fmt.Errorf("migrations not completed: %s", strings.Join(failed, ", "))
+	}
+	return ordered, nil
+}
+
+func dependenciesDone(m Migration, results map[string]*Result) bool {
+	for _, dep := range m.DependsOn {
+		if results[dep].Status != "done" {
+			return false
+		}
+	}
+	return true
+}
+
+func mustFind(migrations []Migration, name string) Migration {
+	m, _ := Find(migrations, name)
+	return m
+}
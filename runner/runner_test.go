@@ -0,0 +1,189 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Runner menjalankan executable-nya sendiri dengan ChildCommand. Di test,
+// executable itu adalah binary test, jadi TestMain berperan sebagai proses
+// migrasi: mencatat waktu mulai dan selesainya ke RUNNER_TEST_LOG lalu gagal
+// jika namanya ada di RUNNER_TEST_FAIL.
+func TestMain(m *testing.M) {
+	if len(os.Args) == 3 && os.Args[1] == ChildCommand {
+		os.Exit(fakeMigration(os.Args[2]))
+	}
+	os.Exit(m.Run())
+}
+
+func fakeMigration(name string) int {
+	if err := logEvent(name, "start"); err != nil {
+		return 3
+	}
+	// Cukup lama agar migrasi yang berjalan bersamaan pasti tumpang tindih
+	time.Sleep(100 * time.Millisecond)
+	if err := logEvent(name, "end"); err != nil {
+		return 3
+	}
+
+	for _, failing := range strings.Split(os.Getenv("RUNNER_TEST_FAIL"), ",") {
+		if failing == name {
+			return 1
+		}
+	}
+	return 0
+}
+
+func logEvent(name, event string) error {
+	f, err := os.OpenFile(os.Getenv("RUNNER_TEST_LOG"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s %s %d\n", name, event, time.Now().UnixNano())
+	return err
+}
+
+// span adalah waktu mulai dan selesai satu proses migrasi palsu
+type span struct {
+	start, end int64
+}
+
+func readSpans(t *testing.T, path string) map[string]span {
+	t.Helper()
+	spans := make(map[string]span)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return spans
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var (
+			name, event string
+			at          int64
+		)
+		if _, err := fmt.Sscanf(scanner.Text(), "%s %s %d", &name, &event, &at); err != nil {
+			t.Fatalf("log line %q: %v", scanner.Text(), err)
+		}
+		s := spans[name]
+		if event == "start" {
+			s.start = at
+		} else {
+			s.end = at
+		}
+		spans[name] = s
+	}
+	return spans
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		opts       Options
+		fail       string
+		want       map[string]string // status per migrasi
+		wantErr    bool
+		serial     bool // tidak ada dua migrasi yang boleh berjalan bersamaan
+	}{
+		{
+			name: "all done",
+			migrations: []Migration{
+				{Name: "user", Databases: []string{"identity"}},
+				{Name: "organization", DependsOn: []string{"user"}, Databases: []string{"identity"}},
+				{Name: "package", DependsOn: []string{"organization"}, Databases: []string{"umrah"}},
+			},
+			opts: Options{MaxConnsPerDB: 8, ConnsPerMigration: 4},
+			want: map[string]string{"user": "done", "organization": "done", "package": "done"},
+		},
+		{
+			name: "failed dependency skips dependents transitively",
+			migrations: []Migration{
+				{Name: "user"},
+				{Name: "hotel"},
+				{Name: "organization", DependsOn: []string{"user"}},
+				{Name: "package", DependsOn: []string{"organization", "hotel"}},
+			},
+			opts:    Options{ConnsPerMigration: 2},
+			fail:    "user",
+			want:    map[string]string{"user": "failed", "hotel": "done", "organization": "skipped", "package": "skipped"},
+			wantErr: true,
+		},
+		{
+			name: "connections per migration are capped by the database budget",
+			migrations: []Migration{
+				{Name: "user", Databases: []string{"identity"}},
+				{Name: "bdm", Databases: []string{"identity"}},
+			},
+			opts:   Options{MaxConnsPerDB: 2, ConnsPerMigration: 4},
+			want:   map[string]string{"user": "done", "bdm": "done"},
+			serial: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "runs.log")
+			t.Setenv("RUNNER_TEST_LOG", logPath)
+			t.Setenv("RUNNER_TEST_FAIL", tt.fail)
+
+			results, err := Run(tt.migrations, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got := make(map[string]string)
+			var order []string
+			for _, result := range results {
+				got[result.Name] = result.Status
+				order = append(order, result.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statuses = %v, want %v", got, tt.want)
+			}
+
+			// Hasil mengikuti urutan deklarasi, bukan urutan selesai
+			for i, m := range tt.migrations {
+				if order[i] != m.Name {
+					t.Errorf("results order = %v, want declaration order", order)
+					break
+				}
+			}
+
+			// Setiap migrasi hanya dimulai setelah semua dependensinya selesai
+			spans := readSpans(t, logPath)
+			for _, m := range tt.migrations {
+				s, started := spans[m.Name]
+				if started != (got[m.Name] == "done" || got[m.Name] == "failed") {
+					t.Errorf("%s started = %v with status %s", m.Name, started, got[m.Name])
+				}
+				for _, dep := range m.DependsOn {
+					if started && spans[dep].end > s.start {
+						t.Errorf("%s started before its dependency %s finished", m.Name, dep)
+					}
+				}
+			}
+
+			if tt.serial {
+				for a, sa := range spans {
+					for b, sb := range spans {
+						if a < b && sa.start < sb.end && sb.start < sa.end {
+							t.Errorf("%s and %s ran at the same time", a, b)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -3,10 +3,11 @@ package airline
 import (
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 	"github.com/ApesJs/go-migration-app/service/airline/helper"
-	"github.com/schollz/progressbar/v3"
-	"log"
-	"strconv"
 )
 
 func AirlineService() {
@@ -19,45 +20,26 @@ func AirlineService() {
 	// Prepare statements untuk airline
 	insertStmt, err := helper.InsertAirlineStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
 	checkStmt, err := helper.CheckExistingAirlineStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing check statement:", err)
+		logging.Fatal("Error preparing check statement", "error", err)
 	}
 	defer checkStmt.Close()
 
-	// Prepare statements untuk update package
-	getNewIDStmt, err := helper.GetNewAirlineIDStmt(devGeneralDB)
-	if err != nil {
-		log.Fatal("Error preparing get new ID statement:", err)
-	}
-	defer getNewIDStmt.Close()
-
-	updateDepartureStmt, err := helper.UpdatePackageDepartureAirlineStmt(devUmrahDB)
-	if err != nil {
-		log.Fatal("Error preparing update departure statement:", err)
-	}
-	defer updateDepartureStmt.Close()
-
-	updateArrivalStmt, err := helper.UpdatePackageArrivalAirlineStmt(devUmrahDB)
-	if err != nil {
-		log.Fatal("Error preparing update arrival statement:", err)
-	}
-	defer updateArrivalStmt.Close()
-
 	// Read Indonesian airlines
 	indoAirlines, err := helper.ReadAirlineJSON("service/airline/seed/airline/airline-indo.json")
 	if err != nil {
-		log.Fatal("Error reading Indonesian airlines:", err)
+		logging.Fatal("Error reading Indonesian airlines", "error", err)
 	}
 
 	// Read Arab airlines
 	arabAirlines, err := helper.ReadAirlineJSON("service/airline/seed/airline/airline-arab.json")
 	if err != nil {
-		log.Fatal("Error reading Arab airlines:", err)
+		logging.Fatal("Error reading Arab airlines", "error", err)
 	}
 
 	// Process data with country information
@@ -67,49 +49,43 @@ func AirlineService() {
 	allAirlines = append(allAirlines,
 		helper.ProcessAirlineData(arabAirlines, "ARAB SAUDI", "682")...)
 
+	report.SetTotal(len(allAirlines))
+
 	// Create progress bar untuk insert airline
-	bar := progressbar.NewOptions(len(allAirlines),
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/2][reset] Inserting airlines..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	plan := progress.NewPlan("airlines", "package references")
+	bar := plan.Start("airlines", len(allAirlines))
 
 	// Statistics
 	var (
 		successCount int
 		errorCount   int
 		skipCount    int
-		updateCount  int
 		updateErrors int
 	)
 
 	// Begin transaction untuk airline
 	tx, err := devGeneralDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Process each airline
 	for _, airline := range allAirlines {
+		logging.SetRecord(airline.Code)
 		// Check if airline already exists
 		var count int
 		err := checkStmt.QueryRow(airline.Code).Scan(&count)
 		if err != nil {
-			log.Printf("Error checking existing airline %s: %v", airline.Code, err)
+			report.Errorf("Error checking existing airline %s: %v", airline.Code, err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		if count > 0 {
 			skipCount++
+			report.Add(report.OutcomeSkipped, 1)
 			bar.Add(1)
 			continue
 		}
@@ -126,101 +102,53 @@ func AirlineService() {
 		)
 
 		if err != nil {
-			log.Printf("Error inserting airline %s: %v", airline.Code, err)
+			report.Errorf("Error inserting airline %s: %v", airline.Code, err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 		} else {
 			successCount++
+			report.Add(report.OutcomeInserted, 1)
 		}
 		bar.Add(1)
 	}
+	logging.SetRecord("")
+	bar.Finish()
 
 	// Commit transaction airline
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
-	// Create progress bar untuk update package
-	updateBar := progressbar.NewOptions(len(allAirlines),
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][2/2][reset] Updating package references..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
-
-	// Begin transaction untuk update package
-	txUmrah, err := devUmrahDB.Begin()
-	if err != nil {
-		log.Fatal("Error starting package update transaction:", err)
-	}
-
-	// Prepare statements dalam transaction
-	txUpdateDepartureStmt := txUmrah.Stmt(updateDepartureStmt)
-	txUpdateArrivalStmt := txUmrah.Stmt(updateArrivalStmt)
-
-	// Update references di tabel package
-	for _, airline := range allAirlines {
-		// Get new airline ID
-		var newID int
-		err := getNewIDStmt.QueryRow(airline.Code).Scan(&newID)
-		if err != nil {
-			log.Printf("Error getting new ID for airline %s: %v", airline.Code, err)
-			updateErrors++
-			updateBar.Add(1)
-			continue
-		}
-
-		// Convert ID to JSON string
-		idJSON := strconv.Itoa(newID)
-
-		// Update departure references berdasarkan nama airline
-		result, err := txUpdateDepartureStmt.Exec(idJSON, airline.Name)
-		if err != nil {
-			log.Printf("Error updating departure references for airline %s: %v", airline.Name, err)
-			updateErrors++
-		} else {
-			rows, _ := result.RowsAffected()
-			updateCount += int(rows)
-		}
-
-		// Update arrival references berdasarkan nama airline
-		result, err = txUpdateArrivalStmt.Exec(idJSON, airline.Name)
-		if err != nil {
-			log.Printf("Error updating arrival references for airline %s: %v", airline.Name, err)
-			updateErrors++
-		} else {
-			rows, _ := result.RowsAffected()
-			updateCount += int(rows)
-		}
-
-		updateBar.Add(1)
-	}
+	refBar := plan.Start("package references", 0)
 
-	// Commit transaction update package
-	err = txUmrah.Commit()
+	// Update referensi airline di package sekaligus lewat temp table mapping nama -> id
+	refStats, err := helper.UpdatePackageAirlineReferences(devGeneralDB, devUmrahDB)
 	if err != nil {
-		log.Printf("Error committing package update transaction: %v", err)
-		txUmrah.Rollback()
-		return
+		report.Errorf("Error updating package airline references: %v", err)
+		updateErrors++
+		refStats = &helper.PackageAirlineUpdateStats{}
 	}
+	refBar.Finish()
+
+	report.Inserted("airline", successCount)
+	report.Updated("package", int(refStats.DepartureUpdated+refStats.ArrivalUpdated))
+	report.Counter("airlines_in_mapping", refStats.MappedAirlines)
+	report.Counter("departure_references_updated", int(refStats.DepartureUpdated))
+	report.Counter("arrival_references_updated", int(refStats.ArrivalUpdated))
+	report.Counter("unmatched_departure_packages", helper.UnmatchedPackages(refStats.DepartureUnmatched))
+	report.Counter("unmatched_arrival_packages", helper.UnmatchedPackages(refStats.ArrivalUnmatched))
+	report.Counter("update_errors", updateErrors)
+
+	addUnmatchedAirlines("Departure", refStats.DepartureUnmatched)
+	addUnmatchedAirlines("Arrival", refStats.ArrivalUnmatched)
+	report.Finish()
+}
 
-	// Print summary
-	fmt.Printf("\nMigration Summary:\n")
-	fmt.Printf("----------------\n")
-	fmt.Printf("Airlines:\n")
-	fmt.Printf("  Total processed: %d\n", len(allAirlines))
-	fmt.Printf("  Successfully inserted: %d\n", successCount)
-	fmt.Printf("  Skipped (already exists): %d\n", skipCount)
-	fmt.Printf("  Failed: %d\n", errorCount)
-	fmt.Printf("\nPackage Updates:\n")
-	fmt.Printf("  References updated: %d\n", updateCount)
-	fmt.Printf("  Update errors: %d\n", updateErrors)
+func addUnmatchedAirlines(label string, unmatched []helper.UnmatchedAirline) {
+	for _, airline := range unmatched {
+		report.Item(fmt.Sprintf("Unmatched %s airlines (no master airline row)", label), fmt.Sprintf("%s (%d packages)", airline.Name, airline.Packages))
+	}
 }
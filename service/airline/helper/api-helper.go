@@ -1,7 +1,10 @@
 package helper
 
 import (
+	"database/sql"
 	"encoding/json"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/metrics"
 	"os"
 )
 
@@ -35,3 +38,92 @@ func ProcessAirlineData(airlines []Airline, countryName, countryID string) []Air
 
 	return result
 }
+
+// UpdatePackageAirlineReferences menyalin mapping nama -> id dari master airline ke temp table
+// di database umrah, lalu memperbarui departure dan arrival di package secara set-based.
+func UpdatePackageAirlineReferences(devGeneralDB, devUmrahDB *sql.DB) (*PackageAirlineUpdateStats, error) {
+	mapRows, err := GetAirlineIDMapRows(devGeneralDB)
+	if err != nil {
+		return nil, fmt.Errorf("error querying airline id map: %v", err)
+	}
+	defer mapRows.Close()
+
+	var (
+		names []string
+		ids   []int64
+	)
+	for mapRows.Next() {
+		var (
+			name string
+			id   int64
+		)
+		if err := mapRows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("error scanning airline id map: %v", err)
+		}
+		names = append(names, name)
+		ids = append(ids, id)
+	}
+	if err := mapRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating airline id map: %v", err)
+	}
+
+	tx, err := devUmrahDB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting package update transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := CreateAirlineIDMapTable(tx, names, ids); err != nil {
+		return nil, err
+	}
+
+	stats := &PackageAirlineUpdateStats{MappedAirlines: len(names)}
+
+	stats.DepartureUpdated, err = UpdatePackageAirlineIDs(tx, "departure")
+	if err != nil {
+		return nil, fmt.Errorf("error updating departure airline IDs: %v", err)
+	}
+
+	stats.ArrivalUpdated, err = UpdatePackageAirlineIDs(tx, "arrival")
+	if err != nil {
+		return nil, fmt.Errorf("error updating arrival airline IDs: %v", err)
+	}
+
+	stats.DepartureUnmatched, err = scanUnmatchedAirlines(tx, "departure")
+	if err != nil {
+		return nil, err
+	}
+
+	stats.ArrivalUnmatched, err = scanUnmatchedAirlines(tx, "arrival")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := metrics.Commit(tx); err != nil {
+		return nil, fmt.Errorf("error committing package update transaction: %v", err)
+	}
+
+	return stats, nil
+}
+
+func scanUnmatchedAirlines(tx *sql.Tx, field string) ([]UnmatchedAirline, error) {
+	rows, err := GetUnmatchedPackageAirlines(tx, field)
+	if err != nil {
+		return nil, fmt.Errorf("error querying unmatched %s airlines: %v", field, err)
+	}
+	defer rows.Close()
+
+	var unmatched []UnmatchedAirline
+	for rows.Next() {
+		var (
+			name     sql.NullString
+			packages int
+		)
+		if err := rows.Scan(&name, &packages); err != nil {
+			return nil, fmt.Errorf("error scanning unmatched %s airlines: %v", field, err)
+		}
+		unmatched = append(unmatched, UnmatchedAirline{Name: name.String, Packages: packages})
+	}
+
+	return unmatched, rows.Err()
+}
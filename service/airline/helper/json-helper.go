@@ -11,3 +11,27 @@ type AirlineWithCountry struct {
 	CountryName string
 	CountryID   string
 }
+
+// Nama airline di package yang tidak ditemukan di master airline
+type UnmatchedAirline struct {
+	Name     string
+	Packages int
+}
+
+// Statistik update referensi airline di tabel package
+type PackageAirlineUpdateStats struct {
+	MappedAirlines     int
+	DepartureUpdated   int64
+	ArrivalUpdated     int64
+	DepartureUnmatched []UnmatchedAirline
+	ArrivalUnmatched   []UnmatchedAirline
+}
+
+// UnmatchedPackages menghitung total package yang nama airline-nya tidak cocok
+func UnmatchedPackages(unmatched []UnmatchedAirline) int {
+	total := 0
+	for _, airline := range unmatched {
+		total += airline.Packages
+	}
+	return total
+}
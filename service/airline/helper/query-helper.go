@@ -1,6 +1,10 @@
 package helper
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+)
 
 // InsertAirlineStmt prepares insert statement for airline
 func InsertAirlineStmt(db *sql.DB) (*sql.Stmt, error) {
@@ -23,48 +27,77 @@ func CheckExistingAirlineStmt(db *sql.DB) (*sql.Stmt, error) {
     `)
 }
 
-// GetNewAirlineIDStmt gets new airline ID by code
-func GetNewAirlineIDStmt(db *sql.DB) (*sql.Stmt, error) {
-	return db.Prepare(`
-        SELECT id 
-        FROM airline 
-        WHERE code = $1
+// GetAirlineIDMapRows mengambil pasangan nama -> id dari master airline.
+// Jika ada nama kembar, id terkecil yang dipakai.
+func GetAirlineIDMapRows(db *sql.DB) (*sql.Rows, error) {
+	return db.Query(`
+        SELECT DISTINCT ON (name) name, id
+        FROM airline
+        ORDER BY name, id
     `)
 }
 
-// query-helper.go
-func UpdatePackageDepartureAirlineStmt(db *sql.DB) (*sql.Stmt, error) {
-	return db.Prepare(`
-        UPDATE package 
-        SET departure = jsonb_set(
-            jsonb_set(
-                departure,
-                '{airline,id}',
-                $1::text::jsonb,
-                false
-            ),
-            '{airlineId}',
-            $1::text::jsonb,
-            false
-        )
-        WHERE departure->'airline'->>'name' = $2
+// CreateAirlineIDMapTable membuat temp table mapping nama airline -> id baru.
+// Temp table hanya hidup di dalam transaksi tx.
+func CreateAirlineIDMapTable(tx *sql.Tx, names []string, ids []int64) error {
+	_, err := tx.Exec(`
+        CREATE TEMP TABLE airline_id_map (
+            name TEXT PRIMARY KEY,
+            id   INTEGER NOT NULL
+        ) ON COMMIT DROP
     `)
+	if err != nil {
+		return fmt.Errorf("error creating airline_id_map: %v", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO airline_id_map (name, id)
+        SELECT * FROM unnest($1::text[], $2::int[])
+    `, pq.Array(names), pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error filling airline_id_map: %v", err)
+	}
+
+	return nil
 }
 
-func UpdatePackageArrivalAirlineStmt(db *sql.DB) (*sql.Stmt, error) {
-	return db.Prepare(`
-        UPDATE package 
-        SET arrival = jsonb_set(
+// UpdatePackageAirlineIDs mengganti {airline,id} dan {airlineId} pada kolom flight
+// (departure / arrival) untuk semua package sekaligus berdasarkan airline_id_map.
+func UpdatePackageAirlineIDs(tx *sql.Tx, field string) (int64, error) {
+	result, err := tx.Exec(fmt.Sprintf(`
+        UPDATE package p
+        SET %s = jsonb_set(
             jsonb_set(
-                arrival,
+                p.%s,
                 '{airline,id}',
-                $1::text::jsonb,
+                to_jsonb(m.id),
                 false
             ),
             '{airlineId}',
-            $1::text::jsonb,
+            to_jsonb(m.id),
             false
         )
-        WHERE arrival->'airline'->>'name' = $2
-    `)
+        FROM airline_id_map m
+        WHERE p.%s->'airline'->>'name' = m.name
+    `, field, field, field))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetUnmatchedPackageAirlines mengambil nama airline di package yang tidak ada di airline_id_map,
+// beserta jumlah package yang memakainya.
+func GetUnmatchedPackageAirlines(tx *sql.Tx, field string) (*sql.Rows, error) {
+	return tx.Query(fmt.Sprintf(`
+        SELECT p.%s->'airline'->>'name' AS name, COUNT(*)
+        FROM package p
+        WHERE p.%s IS NOT NULL AND p.%s != 'null'::jsonb
+        AND NOT EXISTS (
+            SELECT 1 FROM airline_id_map m WHERE m.name = p.%s->'airline'->>'name'
+        )
+        GROUP BY 1
+        ORDER BY 2 DESC
+    `, field, field, field, field))
 }
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 	"github.com/ApesJs/go-migration-app/service/airport/helper"
-	"github.com/schollz/progressbar/v3"
-	"log"
 	"os"
 	"time"
 )
@@ -20,57 +23,52 @@ func AirportService() {
 	defer devIdentityDB.Close()
 	defer prodExistingUmrahDB.Close()
 
-	fmt.Printf("\nPhase 1: Migrating Airports from JSON\n")
-	fmt.Printf("====================================\n")
+	// Total gabungan semua fase untuk run report
+	var total int
+
+	fmt.Print(report.Heading(i18n.T("airport.phase_indo"), "="))
 
 	// Read JSON file
 	jsonFile, err := os.ReadFile("service/airport/seed/airport/airport-indo.json")
 	if err != nil {
-		log.Fatal("Error reading JSON file:", err)
+		logging.Fatal("Error reading JSON file", "error", err)
 	}
 
 	// Parse JSON data
 	var airportsIndo []helper.AirportJSON
 	err = json.Unmarshal(jsonFile, &airportsIndo)
 	if err != nil {
-		log.Fatal("Error parsing JSON:", err)
+		logging.Fatal("Error parsing JSON", "error", err)
 	}
 
 	totalAirports := helper.TotalAirports(airportsIndo)
-	fmt.Printf("Found %d airports to transfer\n", totalAirports)
+	fmt.Println(i18n.T("airport.found", totalAirports))
 
 	// Prepare statements
 	getCityIDStmt, err := helper.GetCityIDFromLocationStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing get city ID statement:", err)
+		logging.Fatal("Error preparing get city ID statement", "error", err)
 	}
 	defer getCityIDStmt.Close()
 
 	checkAirportExistStmt, err := helper.CheckAirportExistStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing check airport statement:", err)
+		logging.Fatal("Error preparing check airport statement", "error", err)
 	}
 	defer checkAirportExistStmt.Close()
 
 	insertAirportStmt, err := helper.InsertAirportStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing insert airport statement:", err)
+		logging.Fatal("Error preparing insert airport statement", "error", err)
 	}
 	defer insertAirportStmt.Close()
 
+	total += totalAirports
+	report.SetTotal(total)
+
 	// Progress bar
-	bar := progressbar.NewOptions(totalAirports,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/1][reset] Transferring airports..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	plan := progress.NewPlan("airports (indo)", "location_province", "location_city", "airports (arab)")
+	bar := plan.Start("airports (indo)", totalAirports)
 
 	// Statistics
 	var (
@@ -85,32 +83,37 @@ func AirportService() {
 	// Begin transaction
 	tx, err := devGeneralDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Process each airport
 	for _, airport := range airportsIndo {
+		logging.SetRecord(airport.Code)
 		newID, err := helper.ProcessAirportIndo(tx, airport, getCityIDStmt, checkAirportExistStmt, insertAirportStmt)
 		if err != nil {
-			log.Printf("Error processing airport %s: %v", airport.Code, err)
+			report.Errorf("Error processing airport %s: %v", airport.Code, err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		if newID > 0 {
 			insertedCount++
+			report.Add(report.OutcomeInserted, 1)
 		} else {
 			skippedCount++
+			report.Add(report.OutcomeSkipped, 1)
 		}
 		processedCount++
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
@@ -118,65 +121,45 @@ func AirportService() {
 	// Update progress bar untuk selesai
 	bar.Finish()
 
-	duration := time.Since(startTime)
-
-	// Print summary
-	fmt.Printf("\nMigration Summary:\n")
-	fmt.Printf("=================\n")
-	fmt.Printf("Total airports found: %d\n", totalAirports)
-	fmt.Printf("Successfully processed airports: %d\n", processedCount)
-	fmt.Printf("Successfully inserted airports: %d\n", insertedCount)
-	fmt.Printf("Skipped (already exist): %d\n", skippedCount)
-	fmt.Printf("Failed transfers: %d\n", errorCount)
-	fmt.Printf("\nPerformance Metrics:\n")
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f airports/second\n", float64(processedCount)/duration.Seconds())
+	report.AddPhase("airports (indo)", time.Since(startTime), processedCount)
+	report.Inserted("airport", insertedCount)
 
 	// Part 1: Migrate Provinces
-	fmt.Printf("\nPhase 1.1: Migrating Provinces from JSON\n")
-	fmt.Printf("=====================================\n")
+	fmt.Print(report.Heading(i18n.T("airport.phase_provinces"), "="))
 
 	// Read Province JSON file
 	provinceFile, err := os.ReadFile("service/airport/seed/airport/airport-location-arab/airport-province-arab.json")
 	if err != nil {
-		log.Fatal("Error reading province JSON file:", err)
+		logging.Fatal("Error reading province JSON file", "error", err)
 	}
 
 	var provinces []helper.ProvinceJSON
 	err = json.Unmarshal(provinceFile, &provinces)
 	if err != nil {
-		log.Fatal("Error parsing province JSON:", err)
+		logging.Fatal("Error parsing province JSON", "error", err)
 	}
 
 	totalProvinces := helper.TotalProvinces(provinces)
-	fmt.Printf("Found %d provinces to transfer\n", totalProvinces)
+	fmt.Println(i18n.T("airport.found_provinces", totalProvinces))
 
 	// Prepare province statements
 	checkProvinceExistStmt, err := helper.CheckProvinceExistStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing check province statement:", err)
+		logging.Fatal("Error preparing check province statement", "error", err)
 	}
 	defer checkProvinceExistStmt.Close()
 
 	insertProvinceStmt, err := helper.InsertProvinceStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing insert province statement:", err)
+		logging.Fatal("Error preparing insert province statement", "error", err)
 	}
 	defer insertProvinceStmt.Close()
 
+	total += totalProvinces
+	report.SetTotal(total)
+
 	// Progress bar for provinces
-	provinceBar := progressbar.NewOptions(totalProvinces,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/3][reset] Transferring provinces..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	provinceBar := plan.Start("location_province", totalProvinces)
 
 	var (
 		processedProvinces int
@@ -190,95 +173,81 @@ func AirportService() {
 	// Begin province transaction
 	txProvince, err := devIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting province transaction:", err)
+		logging.Fatal("Error starting province transaction", "error", err)
 	}
 
 	// Process each province
 	for _, province := range provinces {
+		logging.SetRecord(province.Kode)
 		newID, err := helper.ProcessProvince(txProvince, province, checkProvinceExistStmt, insertProvinceStmt)
 		if err != nil {
-			log.Printf("Error processing province %s: %v", province.Kode, err)
+			report.Errorf("Error processing province %s: %v", province.Kode, err)
 			errorProvinces++
+			report.Add(report.OutcomeFailed, 1)
 			provinceBar.Add(1)
 			continue
 		}
 
 		if newID != "" {
 			insertedProvinces++
+			report.Add(report.OutcomeInserted, 1)
 		} else {
 			skippedProvinces++
+			report.Add(report.OutcomeSkipped, 1)
 		}
 		processedProvinces++
 		provinceBar.Add(1)
 	}
+	logging.SetRecord("")
+	provinceBar.Finish()
 
 	// Commit province transaction
-	err = txProvince.Commit()
+	err = metrics.Commit(txProvince)
 	if err != nil {
-		log.Printf("Error committing province transaction: %v", err)
+		report.Errorf("Error committing province transaction: %v", err)
 		txProvince.Rollback()
 		return
 	}
 
-	durationProvinces := time.Since(startTimeProvinces)
-
-	// Print province summary
-	fmt.Printf("\nProvince Migration Summary:\n")
-	fmt.Printf("==========================\n")
-	fmt.Printf("Total provinces found: %d\n", totalProvinces)
-	fmt.Printf("Successfully processed provinces: %d\n", processedProvinces)
-	fmt.Printf("Successfully inserted provinces: %d\n", insertedProvinces)
-	fmt.Printf("Skipped (already exist): %d\n", skippedProvinces)
-	fmt.Printf("Failed transfers: %d\n", errorProvinces)
-	fmt.Printf("\nPerformance Metrics:\n")
-	fmt.Printf("Duration: %s\n", durationProvinces.Round(time.Second))
-	fmt.Printf("Average speed: %.2f provinces/second\n", float64(processedProvinces)/durationProvinces.Seconds())
+	report.AddPhase("location_province", time.Since(startTimeProvinces), processedProvinces)
+	report.Inserted("location_province", insertedProvinces)
 
 	// Part 2: Migrate Cities
-	fmt.Printf("\nPhase 1.2: Migrating Cities from JSON\n")
-	fmt.Printf("===================================\n")
+	fmt.Print(report.Heading(i18n.T("airport.phase_cities"), "="))
 
 	// Read City JSON file
 	cityFile, err := os.ReadFile("service/airport/seed/airport/airport-location-arab/airport-city-arab.json")
 	if err != nil {
-		log.Fatal("Error reading city JSON file:", err)
+		logging.Fatal("Error reading city JSON file", "error", err)
 	}
 
 	var cities []helper.CityJSON
 	err = json.Unmarshal(cityFile, &cities)
 	if err != nil {
-		log.Fatal("Error parsing city JSON:", err)
+		logging.Fatal("Error parsing city JSON", "error", err)
 	}
 
 	totalCities := helper.TotalCities(cities)
-	fmt.Printf("Found %d cities to transfer\n", totalCities)
+	fmt.Println(i18n.T("airport.found_cities", totalCities))
 
 	// Prepare city statements
 	checkCityExistStmt, err := helper.CheckCityExistStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing check city statement:", err)
+		logging.Fatal("Error preparing check city statement", "error", err)
 	}
 	defer checkCityExistStmt.Close()
 
 	insertCityStmt, err := helper.InsertCityStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing insert city statement:", err)
+		logging.Fatal("Error preparing insert city statement", "error", err)
 	}
 	defer insertCityStmt.Close()
 
+	total += totalCities
+	report.SetTotal(total)
+
 	// Progress bar for cities
-	cityBar := progressbar.NewOptions(totalCities,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][2/3][reset] Transferring cities..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	cityBar := plan.Start("location_city", totalCities)
 
 	var (
 		processedCities int
@@ -292,101 +261,87 @@ func AirportService() {
 	// Begin city transaction
 	txCity, err := devIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting city transaction:", err)
+		logging.Fatal("Error starting city transaction", "error", err)
 	}
 
 	// Process each city
 	for _, city := range cities {
+		logging.SetRecord(city.Kode)
 		newID, err := helper.ProcessCity(txCity, city, checkCityExistStmt, insertCityStmt)
 		if err != nil {
-			log.Printf("Error processing city %s: %v", city.Kode, err)
+			report.Errorf("Error processing city %s: %v", city.Kode, err)
 			errorCities++
+			report.Add(report.OutcomeFailed, 1)
 			cityBar.Add(1)
 			continue
 		}
 
 		if newID != "" {
 			insertedCities++
+			report.Add(report.OutcomeInserted, 1)
 		} else {
 			skippedCities++
+			report.Add(report.OutcomeSkipped, 1)
 		}
 		processedCities++
 		cityBar.Add(1)
 	}
+	logging.SetRecord("")
+	cityBar.Finish()
 
 	// Commit city transaction
-	err = txCity.Commit()
+	err = metrics.Commit(txCity)
 	if err != nil {
-		log.Printf("Error committing city transaction: %v", err)
+		report.Errorf("Error committing city transaction: %v", err)
 		txCity.Rollback()
 		return
 	}
 
-	durationCities := time.Since(startTimeCities)
-
-	// Print city summary
-	fmt.Printf("\nCity Migration Summary:\n")
-	fmt.Printf("=====================\n")
-	fmt.Printf("Total cities found: %d\n", totalCities)
-	fmt.Printf("Successfully processed cities: %d\n", processedCities)
-	fmt.Printf("Successfully inserted cities: %d\n", insertedCities)
-	fmt.Printf("Skipped (already exist): %d\n", skippedCities)
-	fmt.Printf("Failed transfers: %d\n", errorCities)
-	fmt.Printf("\nPerformance Metrics:\n")
-	fmt.Printf("Duration: %s\n", durationCities.Round(time.Second))
-	fmt.Printf("Average speed: %.2f cities/second\n", float64(processedCities)/durationCities.Seconds())
+	report.AddPhase("location_city", time.Since(startTimeCities), processedCities)
+	report.Inserted("location_city", insertedCities)
 
 	// Part 3: Migrate Airports
-	fmt.Printf("\nPhase 1.3: Migrating Airports from JSON\n")
-	fmt.Printf("====================================\n")
+	fmt.Print(report.Heading(i18n.T("airport.phase_arab"), "="))
 
 	// Read Airport JSON file
 	airportFile, err := os.ReadFile("service/airport/seed/airport/airport-arab.json")
 	if err != nil {
-		log.Fatal("Error reading airport JSON file:", err)
+		logging.Fatal("Error reading airport JSON file", "error", err)
 	}
 
 	var airports []helper.AirportJSON
 	err = json.Unmarshal(airportFile, &airports)
 	if err != nil {
-		log.Fatal("Error parsing airport JSON:", err)
+		logging.Fatal("Error parsing airport JSON", "error", err)
 	}
 
 	totalAirports = helper.TotalAirports(airports)
-	fmt.Printf("Found %d airports to transfer\n", totalAirports)
+	fmt.Println(i18n.T("airport.found", totalAirports))
 
 	// Prepare airport statements
 	getCityIDStmt, err = helper.GetCityIDFromLocationStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing get city ID statement:", err)
+		logging.Fatal("Error preparing get city ID statement", "error", err)
 	}
 	defer getCityIDStmt.Close()
 
 	checkAirportExistStmt, err = helper.CheckAirportExistStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing check airport statement:", err)
+		logging.Fatal("Error preparing check airport statement", "error", err)
 	}
 	defer checkAirportExistStmt.Close()
 
 	insertAirportStmt, err = helper.InsertAirportStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing insert airport statement:", err)
+		logging.Fatal("Error preparing insert airport statement", "error", err)
 	}
 	defer insertAirportStmt.Close()
 
+	total += totalAirports
+	report.SetTotal(total)
+
 	// Progress bar for airports
-	airportBar := progressbar.NewOptions(totalAirports,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][3/3][reset] Transferring airports..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	airportBar := plan.Start("airports (arab)", totalAirports)
 
 	var (
 		processedAirports int
@@ -400,47 +355,44 @@ func AirportService() {
 	// Begin airport transaction
 	txAirport, err := devGeneralDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting airport transaction:", err)
+		logging.Fatal("Error starting airport transaction", "error", err)
 	}
 
 	// Process each airport
 	for _, airport := range airports {
+		logging.SetRecord(airport.Code)
 		newID, err := helper.ProcessAirport(txAirport, airport, getCityIDStmt, checkAirportExistStmt, insertAirportStmt)
 		if err != nil {
-			log.Printf("Error processing airport %s: %v", airport.Code, err)
+			report.Errorf("Error processing airport %s: %v", airport.Code, err)
 			errorAirports++
+			report.Add(report.OutcomeFailed, 1)
 			airportBar.Add(1)
 			continue
 		}
 
 		if newID > 0 {
 			insertedAirports++
+			report.Add(report.OutcomeInserted, 1)
 		} else {
 			skippedAirports++
+			report.Add(report.OutcomeSkipped, 1)
 		}
 		processedAirports++
 		airportBar.Add(1)
 	}
+	logging.SetRecord("")
+	airportBar.Finish()
 
 	// Commit airport transaction
-	err = txAirport.Commit()
+	err = metrics.Commit(txAirport)
 	if err != nil {
-		log.Printf("Error committing airport transaction: %v", err)
+		report.Errorf("Error committing airport transaction: %v", err)
 		txAirport.Rollback()
 		return
 	}
 
-	durationAirports := time.Since(startTimeAirports)
-
-	// Print airport summary
-	fmt.Printf("\nAirport Migration Summary:\n")
-	fmt.Printf("========================\n")
-	fmt.Printf("Total airports found: %d\n", totalAirports)
-	fmt.Printf("Successfully processed airports: %d\n", processedAirports)
-	fmt.Printf("Successfully inserted airports: %d\n", insertedAirports)
-	fmt.Printf("Skipped (already exist): %d\n", skippedAirports)
-	fmt.Printf("Failed transfers: %d\n", errorAirports)
-	fmt.Printf("\nPerformance Metrics:\n")
-	fmt.Printf("Duration: %s\n", durationAirports.Round(time.Second))
-	fmt.Printf("Average speed: %.2f airports/second\n", float64(processedAirports)/durationAirports.Seconds())
+	report.AddPhase("airports (arab)", time.Since(startTimeAirports), processedAirports)
+	report.Inserted("airport", insertedAirports)
+
+	report.Finish()
 }
@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/audit/helper"
+	"time"
+)
+
+// AuditService mencari placeholder dan referensi yatim di database target,
+// dikelompokkan per penyebab. Jika exportPath diisi, semua temuan ditulis ke file CSV/JSON.
+func AuditService(limit int, exportPath string) {
+	startTime := time.Now()
+
+	// Satu koneksi per database, dipakai bersama oleh semua check
+	connections := make(map[string]*sql.DB)
+	defer func() {
+		for _, db := range connections {
+			db.Close()
+		}
+	}()
+
+	var results []helper.CheckResult
+	for _, check := range helper.Checks {
+		db, ok := connections[check.Database]
+		if !ok {
+			db = check.Connect()
+			connections[check.Database] = db
+		}
+		results = append(results, helper.RunCheck(check, db))
+	}
+
+	var placeholderCount, orphanCount, failedCount int
+	fmt.Printf("\n%s\n", i18n.T("audit.title"))
+	fmt.Printf("------------------------\n")
+	for _, result := range results {
+		check := result.Check
+		fmt.Printf("\n[%s] %s\n", check.Category, check.Cause)
+		fmt.Printf("%s (%s.%s)\n", check.Description, check.Database, check.Table)
+
+		if result.Err != nil {
+			failedCount++
+			fmt.Println(i18n.T("check.error", result.Err))
+			continue
+		}
+
+		fmt.Println(i18n.T("audit.found", len(result.Findings)))
+		if check.Category == helper.CategoryPlaceholder {
+			placeholderCount += len(result.Findings)
+		} else {
+			orphanCount += len(result.Findings)
+		}
+
+		for i, finding := range result.Findings {
+			if limit > 0 && i >= limit {
+				fmt.Println(i18n.T("check.more", len(result.Findings)-limit))
+				break
+			}
+			fmt.Printf("%d. %s (%s)\n", i+1, finding.ID, finding.Detail)
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("audit.summary"))
+	fmt.Println(i18n.T("audit.placeholders", placeholderCount))
+	fmt.Println(i18n.T("audit.dangling", orphanCount))
+	if failedCount > 0 {
+		fmt.Println(i18n.T("check.failed", failedCount))
+	}
+
+	if exportPath != "" {
+		if err := helper.ExportFindings(exportPath, results); err != nil {
+			logging.Fatal("Error exporting findings", "error", err)
+		}
+		fmt.Println(i18n.T("audit.exported", exportPath))
+	}
+
+	fmt.Println(i18n.T("audit.finished", time.Since(startTime).Round(time.Millisecond)))
+}
@@ -0,0 +1,179 @@
+package helper
+
+import (
+	"database/sql"
+	"github.com/ApesJs/go-migration-app/database"
+)
+
+// Checks berisi semua placeholder dan referensi yatim yang diperiksa, mengikuti
+// database target yang dipakai service migrasi masing-masing
+var Checks = []Check{
+	{
+		Cause:       "package-fallback-instance",
+		Category:    CategoryPlaceholder,
+		Description: "package with organization_instance_id 9999 / \"Nama Travel Tidak di Temukan\" (PackageService)",
+		Database:    "dev umrah",
+		Connect:     database.ConnectionDevUmrahDB,
+		Table:       "package",
+		Query: `
+			SELECT id::text, title || ' (travel ' || organization_id::text || ')'
+			FROM package
+			WHERE organization_instance_id = 9999
+			OR organization_instance_name = 'Nama Travel Tidak di Temukan'
+		`,
+	},
+	{
+		Cause:       "instance-fallback-organization",
+		Category:    CategoryPlaceholder,
+		Description: "organization_instance pointing to fallback organization d0ac7aad-... (OrganizationInstanceService)",
+		Database:    "local identity",
+		Connect:     database.ConnectionLocalIdentityDB,
+		Table:       "organization_instance",
+		Query: `
+			SELECT id::text, name
+			FROM organization_instance
+			WHERE organization_id::text = 'd0ac7aad-54ac-41f1-ba1a-a9070c3f464c'
+		`,
+	},
+	{
+		Cause:       "organization-user-fallback-organization",
+		Category:    CategoryPlaceholder,
+		Description: "organization_user pointing to fallback organization d0ac7aad-... (OrganizationUserService)",
+		Database:    "local identity",
+		Connect:     database.ConnectionLocalIdentityDB,
+		Table:       "organization_user",
+		Query: `
+			SELECT organization_id::text || '/' || user_id::text, role
+			FROM organization_user
+			WHERE organization_id::text = 'd0ac7aad-54ac-41f1-ba1a-a9070c3f464c'
+		`,
+	},
+	{
+		Cause:       "instance-default-location",
+		Category:    CategoryPlaceholder,
+		Description: "organization_instance with default province 31 / city 3173",
+		Database:    "local identity",
+		Connect:     database.ConnectionLocalIdentityDB,
+		Table:       "organization_instance",
+		Query: `
+			SELECT id::text, name
+			FROM organization_instance
+			WHERE province_id::text = '31' AND city_id::text = '3173'
+			AND created_by = 'migration'
+		`,
+	},
+	{
+		Cause:       "instance-placeholder-email",
+		Category:    CategoryPlaceholder,
+		Description: "organization_instance with generated no-email-...@placeholder.com email",
+		Database:    "local identity",
+		Connect:     database.ConnectionLocalIdentityDB,
+		Table:       "organization_instance",
+		Query: `
+			SELECT id::text, name || ' <' || email || '>'
+			FROM organization_instance
+			WHERE email LIKE 'no-email-%@placeholder.com'
+		`,
+	},
+	{
+		Cause:       "instance-dangling-bdm",
+		Category:    CategoryOrphan,
+		Description: "organization_instance.bdm_id without a user",
+		Database:    "local identity",
+		Connect:     database.ConnectionLocalIdentityDB,
+		Table:       "organization_instance",
+		Query: `
+			SELECT i.id::text, i.name || ' (bdm ' || i.bdm_id::text || ')'
+			FROM organization_instance i
+			WHERE i.bdm_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM "user" u WHERE u.id::text = i.bdm_id::text)
+		`,
+	},
+	{
+		Cause:       "persona-dangling-bdm",
+		Category:    CategoryOrphan,
+		Description: "user_persona.bdm_user_id without a user",
+		Database:    "dev identity",
+		Connect:     database.ConnectionDevIdentityDB,
+		Table:       "user_persona",
+		Query: `
+			SELECT p.id::text, 'bdm_user_id ' || p.bdm_user_id::text
+			FROM user_persona p
+			WHERE p.bdm_user_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM "user" u WHERE u.id::text = p.bdm_user_id::text)
+		`,
+	},
+	{
+		Cause:       "persona-dangling-parent",
+		Category:    CategoryOrphan,
+		Description: "user_persona.parent_id without a user",
+		Database:    "dev identity",
+		Connect:     database.ConnectionDevIdentityDB,
+		Table:       "user_persona",
+		Query: `
+			SELECT p.id::text, 'parent_id ' || p.parent_id::text
+			FROM user_persona p
+			WHERE p.parent_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM "user" u WHERE u.id::text = p.parent_id::text)
+		`,
+	},
+	{
+		Cause:       "persona-dangling-travel",
+		Category:    CategoryOrphan,
+		Description: "user_persona.travel_id without an organization",
+		Database:    "dev identity",
+		Connect:     database.ConnectionDevIdentityDB,
+		Table:       "user_persona",
+		Query: `
+			SELECT p.id::text, 'travel_id ' || p.travel_id::text
+			FROM user_persona p
+			WHERE p.travel_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM organization o WHERE o.id::text = p.travel_id::text)
+		`,
+	},
+	{
+		Cause:       "persona-dangling-city",
+		Category:    CategoryOrphan,
+		Description: "user_persona.city_id without a location_city",
+		Database:    "dev identity",
+		Connect:     database.ConnectionDevIdentityDB,
+		Table:       "user_persona",
+		Query: `
+			SELECT p.id::text, 'city_id ' || p.city_id::text
+			FROM user_persona p
+			WHERE p.city_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM location_city c WHERE c.id::text = p.city_id::text)
+		`,
+	},
+}
+
+// RunCheck menjalankan satu Check terhadap database target
+func RunCheck(check Check, db *sql.DB) CheckResult {
+	result := CheckResult{Check: check}
+
+	rows, err := db.Query(check.Query)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, detail sql.NullString
+		if err := rows.Scan(&id, &detail); err != nil {
+			result.Err = err
+			return result
+		}
+		result.Findings = append(result.Findings, Finding{
+			Cause:    check.Cause,
+			Category: check.Category,
+			Database: check.Database,
+			Table:    check.Table,
+			ID:       id.String,
+			Detail:   detail.String,
+		})
+	}
+	result.Err = rows.Err()
+
+	return result
+}
@@ -0,0 +1,44 @@
+package helper
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportFindings menulis semua temuan ke file CSV atau JSON sesuai ekstensi path
+func ExportFindings(path string, results []CheckResult) error {
+	var findings []Finding
+	for _, result := range results {
+		findings = append(findings, result.Findings...)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if findings == nil {
+			findings = []Finding{}
+		}
+		return encoder.Encode(findings)
+	case ".csv":
+		writer := csv.NewWriter(file)
+		writer.Write([]string{"cause", "category", "database", "table", "id", "detail"})
+		for _, f := range findings {
+			writer.Write([]string{f.Cause, f.Category, f.Database, f.Table, f.ID, f.Detail})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported export format %q, use .csv or .json", filepath.Ext(path))
+	}
+}
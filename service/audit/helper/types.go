@@ -0,0 +1,37 @@
+package helper
+
+import "database/sql"
+
+// Kategori temuan audit
+const (
+	CategoryPlaceholder = "placeholder"
+	CategoryOrphan      = "orphan"
+)
+
+// Check adalah satu penyebab temuan. Query harus mengembalikan dua kolom: id baris dan detail.
+type Check struct {
+	Cause       string
+	Category    string
+	Description string
+	Database    string
+	Connect     func() *sql.DB
+	Table       string
+	Query       string
+}
+
+// Finding adalah satu baris target yang berisi placeholder atau referensi yatim
+type Finding struct {
+	Cause    string `json:"cause"`
+	Category string `json:"category"`
+	Database string `json:"database"`
+	Table    string `json:"table"`
+	ID       string `json:"id"`
+	Detail   string `json:"detail"`
+}
+
+// CheckResult adalah hasil satu Check. Err diisi jika query gagal, misalnya tabel tidak ada.
+type CheckResult struct {
+	Check    Check
+	Findings []Finding
+	Err      error
+}
@@ -3,6 +3,7 @@ package helper
 import (
 	"database/sql"
 	"fmt"
+	"github.com/ApesJs/go-migration-app/metrics"
 )
 
 func ProcessHotel(tx *sql.Tx, hotel PackageHotelJSON,
@@ -60,3 +61,92 @@ func ProcessHotel(tx *sql.Tx, hotel PackageHotelJSON,
 
 	return newID, nil
 }
+
+// UpdatePackageHotelReferences menyalin mapping nama -> id dari master hotel ke temp table
+// di database umrah, lalu memperbarui medina_hotel dan mecca_hotel di package secara set-based.
+func UpdatePackageHotelReferences(devGeneralDB, devUmrahDB *sql.DB) (*PackageHotelUpdateStats, error) {
+	mapRows, err := GetHotelIDMapRows(devGeneralDB)
+	if err != nil {
+		return nil, fmt.Errorf("error querying hotel id map: %v", err)
+	}
+	defer mapRows.Close()
+
+	var (
+		names []string
+		ids   []int64
+	)
+	for mapRows.Next() {
+		var (
+			name string
+			id   int64
+		)
+		if err := mapRows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("error scanning hotel id map: %v", err)
+		}
+		names = append(names, name)
+		ids = append(ids, id)
+	}
+	if err := mapRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hotel id map: %v", err)
+	}
+
+	tx, err := devUmrahDB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting package update transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := CreateHotelIDMapTable(tx, names, ids); err != nil {
+		return nil, err
+	}
+
+	stats := &PackageHotelUpdateStats{MappedHotels: len(names)}
+
+	stats.MedinaUpdated, err = UpdatePackageHotelIDs(tx, "medina_hotel")
+	if err != nil {
+		return nil, fmt.Errorf("error updating medina hotel IDs: %v", err)
+	}
+
+	stats.MeccaUpdated, err = UpdatePackageHotelIDs(tx, "mecca_hotel")
+	if err != nil {
+		return nil, fmt.Errorf("error updating mecca hotel IDs: %v", err)
+	}
+
+	stats.MedinaUnmatched, err = scanUnmatchedHotels(tx, "medina_hotel")
+	if err != nil {
+		return nil, err
+	}
+
+	stats.MeccaUnmatched, err = scanUnmatchedHotels(tx, "mecca_hotel")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := metrics.Commit(tx); err != nil {
+		return nil, fmt.Errorf("error committing package update transaction: %v", err)
+	}
+
+	return stats, nil
+}
+
+func scanUnmatchedHotels(tx *sql.Tx, field string) ([]UnmatchedHotel, error) {
+	rows, err := GetUnmatchedPackageHotels(tx, field)
+	if err != nil {
+		return nil, fmt.Errorf("error querying unmatched %s: %v", field, err)
+	}
+	defer rows.Close()
+
+	var unmatched []UnmatchedHotel
+	for rows.Next() {
+		var (
+			name     sql.NullString
+			packages int
+		)
+		if err := rows.Scan(&name, &packages); err != nil {
+			return nil, fmt.Errorf("error scanning unmatched %s: %v", field, err)
+		}
+		unmatched = append(unmatched, UnmatchedHotel{Name: name.String, Packages: packages})
+	}
+
+	return unmatched, rows.Err()
+}
@@ -16,3 +16,27 @@ type PackageHotelJSON struct {
 	ModifiedAt time.Time `json:"modifiedAt"`
 	ModifiedBy *string   `json:"modifiedBy"`
 }
+
+// Nama hotel di package yang tidak ditemukan di master hotel
+type UnmatchedHotel struct {
+	Name     string
+	Packages int
+}
+
+// Statistik update referensi hotel di tabel package
+type PackageHotelUpdateStats struct {
+	MappedHotels    int
+	MedinaUpdated   int64
+	MeccaUpdated    int64
+	MedinaUnmatched []UnmatchedHotel
+	MeccaUnmatched  []UnmatchedHotel
+}
+
+// UnmatchedPackages menghitung total package yang nama hotelnya tidak cocok
+func UnmatchedPackages(unmatched []UnmatchedHotel) int {
+	total := 0
+	for _, hotel := range unmatched {
+		total += hotel.Packages
+	}
+	return total
+}
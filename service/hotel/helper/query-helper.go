@@ -3,6 +3,7 @@ package helper
 import (
 	"database/sql"
 	"fmt"
+	"github.com/lib/pq"
 )
 
 func GetAllPackageHotelsStmt(localUmrahDB *sql.DB) (*sql.Stmt, error) {
@@ -43,16 +44,69 @@ func InsertHotelStmt(devGeneralDB *sql.DB) (*sql.Stmt, error) {
     `)
 }
 
-func UpdatePackageHotelIDStmt(localUmrahDB *sql.DB, field string) (*sql.Stmt, error) {
-	return localUmrahDB.Prepare(fmt.Sprintf(`
-        UPDATE package 
-        SET %s = jsonb_set(
-            %s,
-            '{id}',
-            $1::text::jsonb
-        )
-        WHERE %s->>'name' = $2
+// GetHotelIDMapRows mengambil pasangan nama -> id dari master hotel.
+// Jika ada nama kembar, id terkecil yang dipakai.
+func GetHotelIDMapRows(devGeneralDB *sql.DB) (*sql.Rows, error) {
+	return devGeneralDB.Query(`
+        SELECT DISTINCT ON (name) name, id
+        FROM hotel
+        ORDER BY name, id
+    `)
+}
+
+// CreateHotelIDMapTable membuat temp table mapping nama hotel -> id baru.
+// Temp table hanya hidup di dalam transaksi tx.
+func CreateHotelIDMapTable(tx *sql.Tx, names []string, ids []int64) error {
+	_, err := tx.Exec(`
+        CREATE TEMP TABLE hotel_id_map (
+            name TEXT PRIMARY KEY,
+            id   INTEGER NOT NULL
+        ) ON COMMIT DROP
+    `)
+	if err != nil {
+		return fmt.Errorf("error creating hotel_id_map: %v", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO hotel_id_map (name, id)
+        SELECT * FROM unnest($1::text[], $2::int[])
+    `, pq.Array(names), pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error filling hotel_id_map: %v", err)
+	}
+
+	return nil
+}
+
+// UpdatePackageHotelIDs mengganti {id} pada kolom hotel (medina_hotel / mecca_hotel)
+// untuk semua package sekaligus berdasarkan hotel_id_map.
+func UpdatePackageHotelIDs(tx *sql.Tx, field string) (int64, error) {
+	result, err := tx.Exec(fmt.Sprintf(`
+        UPDATE package p
+        SET %s = jsonb_set(p.%s, '{id}', to_jsonb(m.id))
+        FROM hotel_id_map m
+        WHERE p.%s->>'name' = m.name
     `, field, field, field))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetUnmatchedPackageHotels mengambil nama hotel di package yang tidak ada di hotel_id_map,
+// beserta jumlah package yang memakainya.
+func GetUnmatchedPackageHotels(tx *sql.Tx, field string) (*sql.Rows, error) {
+	return tx.Query(fmt.Sprintf(`
+        SELECT p.%s->>'name' AS name, COUNT(*)
+        FROM package p
+        WHERE p.%s IS NOT NULL AND p.%s != 'null'::jsonb
+        AND NOT EXISTS (
+            SELECT 1 FROM hotel_id_map m WHERE m.name = p.%s->>'name'
+        )
+        GROUP BY 1
+        ORDER BY 2 DESC
+    `, field, field, field, field))
 }
 
 func TotalHotels(devUmrahDB *sql.DB) (int, error) {
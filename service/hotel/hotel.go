@@ -6,12 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/export"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 	"github.com/ApesJs/go-migration-app/service/hotel/helper"
-	"github.com/schollz/progressbar/v3"
-	"log"
 	"time"
 )
 
+// emptyCityHotelList adalah hotel di JSON package yang city name-nya kosong, sehingga tidak dimigrasi
+var emptyCityHotelList = export.List{Name: "empty-city-hotels", Columns: []string{"hotel_id", "name", "address"}}
+
 func HotelService() {
 	// Koneksi Database
 	devUmrahDB := database.ConnectionDevUmrahDB()
@@ -23,67 +30,46 @@ func HotelService() {
 	defer devGeneralDB.Close()
 	defer devUmrahDB.Close()
 
-	fmt.Printf("\nPhase 1: Migrating Hotels from Package Table\n")
-	fmt.Printf("==========================================\n")
+	fmt.Print(report.Heading(i18n.T("hotel.phase_package"), "="))
 
 	// Get total number of hotels to process
 	totalHotels, err := helper.TotalHotels(devUmrahDB)
 	if err != nil {
-		log.Fatal("Error counting hotels:", err)
+		logging.Fatal("Error counting hotels", "error", err)
 	}
 
-	fmt.Printf("Found %d total hotels to transfer from package\n", totalHotels)
+	fmt.Println(i18n.T("hotel.found_package", totalHotels))
 
 	// Prepare statements
 	getAllPackageHotelsStmt, err := helper.GetAllPackageHotelsStmt(devUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing get all package hotels statement:", err)
+		logging.Fatal("Error preparing get all package hotels statement", "error", err)
 	}
 	defer getAllPackageHotelsStmt.Close()
 
 	getCityIDStmt, err := helper.GetCityIDStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing get city ID statement:", err)
+		logging.Fatal("Error preparing get city ID statement", "error", err)
 	}
 	defer getCityIDStmt.Close()
 
 	checkHotelExistStmt, err := helper.CheckHotelExistStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing check hotel statement:", err)
+		logging.Fatal("Error preparing check hotel statement", "error", err)
 	}
 	defer checkHotelExistStmt.Close()
 
 	insertHotelStmt, err := helper.InsertHotelStmt(devGeneralDB)
 	if err != nil {
-		log.Fatal("Error preparing insert hotel statement:", err)
+		logging.Fatal("Error preparing insert hotel statement", "error", err)
 	}
 	defer insertHotelStmt.Close()
 
-	updateMedinaHotelStmt, err := helper.UpdatePackageHotelIDStmt(devUmrahDB, "medina_hotel")
-	if err != nil {
-		log.Fatal("Error preparing update medina hotel statement:", err)
-	}
-	defer updateMedinaHotelStmt.Close()
-
-	updateMeccaHotelStmt, err := helper.UpdatePackageHotelIDStmt(devUmrahDB, "mecca_hotel")
-	if err != nil {
-		log.Fatal("Error preparing update mecca hotel statement:", err)
-	}
-	defer updateMeccaHotelStmt.Close()
+	report.SetTotal(totalHotels)
 
 	// Progress bar untuk Phase 1
-	bar := progressbar.NewOptions(totalHotels,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/3][reset] Transferring package hotels..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	plan := progress.NewPlan("package hotels", "package hotel references", "hotel image URLs", "td_hotel", "city names")
+	bar := plan.Start("package hotels", totalHotels)
 
 	// Statistics untuk Phase 1
 	var (
@@ -98,13 +84,13 @@ func HotelService() {
 	// Begin transaction
 	tx, err := devGeneralDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Get all package hotels
 	rows, err := getAllPackageHotelsStmt.Query()
 	if err != nil {
-		log.Fatal("Error querying package hotels:", err)
+		logging.Fatal("Error querying package hotels", "error", err)
 	}
 	defer rows.Close()
 
@@ -113,8 +99,9 @@ func HotelService() {
 		var medinaHotelJSON, meccaHotelJSON sql.NullString
 		err := rows.Scan(&medinaHotelJSON, &meccaHotelJSON)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			report.Errorf("Error scanning row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -124,16 +111,19 @@ func HotelService() {
 			var hotel helper.PackageHotelJSON
 			err = json.Unmarshal([]byte(medinaHotelJSON.String), &hotel)
 			if err != nil {
-				log.Printf("Error unmarshaling medina hotel: %v, JSON: %s", err, medinaHotelJSON.String)
+				report.Errorf("Error unmarshaling medina hotel: %v, JSON: %s", err, medinaHotelJSON.String)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
 
 			// Log data hotel yang bermasalah
 			if hotel.CityName == "" {
-				log.Printf("Found medina hotel with empty city name: %+v", hotel)
+				report.Errorf("Found medina hotel with empty city name: %+v", hotel)
+				emptyCityHotelList.Add(fmt.Sprint(hotel.ID), hotel.Name, hotel.Address)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
@@ -141,22 +131,19 @@ func HotelService() {
 			// Process hotel
 			newID, err := helper.ProcessHotel(tx, hotel, getCityIDStmt, checkHotelExistStmt, insertHotelStmt)
 			if err != nil {
-				log.Printf("Error processing medina hotel: %v, Hotel Data: %+v", err, hotel)
+				report.Errorf("Error processing medina hotel: %v, Hotel Data: %+v", err, hotel)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
 
 			if newID > 0 {
-				// Update package with new hotel ID
-				_, err = updateMedinaHotelStmt.Exec(newID, hotel.Name)
-				if err != nil {
-					log.Printf("Error updating medina hotel ID: %v", err)
-					errorCount++
-				}
 				insertedCount++
+				report.Add(report.OutcomeInserted, 1)
 			} else {
 				skippedCount++
+				report.Add(report.OutcomeSkipped, 1)
 			}
 			processedCount++
 		}
@@ -166,8 +153,9 @@ func HotelService() {
 			var hotel helper.PackageHotelJSON
 			err = json.Unmarshal([]byte(meccaHotelJSON.String), &hotel)
 			if err != nil {
-				log.Printf("Error unmarshaling mecca hotel: %v", err)
+				report.Errorf("Error unmarshaling mecca hotel: %v", err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
@@ -175,22 +163,19 @@ func HotelService() {
 			// Process hotel
 			newID, err := helper.ProcessHotel(tx, hotel, getCityIDStmt, checkHotelExistStmt, insertHotelStmt)
 			if err != nil {
-				log.Printf("Error processing mecca hotel: %v", err)
+				report.Errorf("Error processing mecca hotel: %v", err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
 
 			if newID > 0 {
-				// Update package with new hotel ID
-				_, err = updateMeccaHotelStmt.Exec(newID, hotel.Name)
-				if err != nil {
-					log.Printf("Error updating mecca hotel ID: %v", err)
-					errorCount++
-				}
 				insertedCount++
+				report.Add(report.OutcomeInserted, 1)
 			} else {
 				skippedCount++
+				report.Add(report.OutcomeSkipped, 1)
 			}
 			processedCount++
 		}
@@ -199,19 +184,28 @@ func HotelService() {
 	}
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
 	// Update progress bar untuk standardisasi nama kota
 	bar.Finish()
-	fmt.Printf("\n[2/3] Standardizing hotel names...\n")
+	refBar := plan.Start("package hotel references", 0)
+
+	// Update id hotel di package sekaligus lewat temp table mapping nama -> id
+	hotelRefStats, err := helper.UpdatePackageHotelReferences(devGeneralDB, devUmrahDB)
+	if err != nil {
+		report.Errorf("Error updating package hotel references: %v", err)
+		hotelRefStats = &helper.PackageHotelUpdateStats{}
+	}
+
+	refBar.Finish()
 
 	// Update hotel images to include full URL
-	fmt.Printf("\n[3/3] Updating hotel image URLs...\n")
+	imageBar := plan.Start("hotel image URLs", 0)
 
 	updateMedinaImageResult, err := devUmrahDB.Exec(`
         UPDATE package
@@ -227,7 +221,7 @@ func HotelService() {
         WHERE medina_hotel->>'logo' IS NOT NULL AND medina_hotel->>'logo' != ''
     `)
 	if err != nil {
-		log.Printf("Error updating Madinah hotel images: %v", err)
+		report.Errorf("Error updating Madinah hotel images: %v", err)
 	}
 
 	updateMeccaImageResult, err := devUmrahDB.Exec(`
@@ -244,33 +238,26 @@ func HotelService() {
         WHERE mecca_hotel->>'logo' IS NOT NULL AND mecca_hotel->>'logo' != ''
     `)
 	if err != nil {
-		log.Printf("Error updating Mecca hotel images: %v", err)
+		report.Errorf("Error updating Mecca hotel images: %v", err)
 	}
+	imageBar.Finish()
 
 	// Get affected rows
 	medinaImageRowsAffected, _ := updateMedinaImageResult.RowsAffected()
 	meccaImageRowsAffected, _ := updateMeccaImageResult.RowsAffected()
 
-	durationPhase1 := time.Since(startTime)
-
-	// Print summary untuk Phase 1
-	fmt.Printf("\nPhase 1 Migration Summary:\n")
-	fmt.Printf("------------------------\n")
-	fmt.Printf("Total hotels found: %d\n", totalHotels)
-	fmt.Printf("Successfully processed hotels: %d\n", processedCount)
-	fmt.Printf("Successfully inserted hotels: %d\n", insertedCount)
-	fmt.Printf("Skipped (already exist): %d\n", skippedCount)
-	fmt.Printf("Failed transfers: %d\n", errorCount)
-	fmt.Printf("\nStandardization Results:\n")
-	fmt.Printf("Updated Madinah hotel images: %d\n", medinaImageRowsAffected)
-	fmt.Printf("Updated Makkah hotel images: %d\n", meccaImageRowsAffected)
-	fmt.Printf("\nPerformance Metrics:\n")
-	fmt.Printf("Duration: %s\n", durationPhase1.Round(time.Second))
-	fmt.Printf("Average speed: %.2f hotels/second\n", float64(processedCount)/durationPhase1.Seconds())
+	report.AddPhase("package hotels", time.Since(startTime), processedCount+errorCount)
+	report.Counter("hotels_in_mapping", hotelRefStats.MappedHotels)
+	report.Updated("package", int(hotelRefStats.MedinaUpdated+hotelRefStats.MeccaUpdated+medinaImageRowsAffected+meccaImageRowsAffected))
+	report.Counter("unmatched_madinah_hotel_packages", helper.UnmatchedPackages(hotelRefStats.MedinaUnmatched))
+	report.Counter("unmatched_makkah_hotel_packages", helper.UnmatchedPackages(hotelRefStats.MeccaUnmatched))
+
+	// Nama hotel di package yang tidak ada di master hotel
+	addUnmatchedHotels("Madinah", hotelRefStats.MedinaUnmatched)
+	addUnmatchedHotels("Makkah", hotelRefStats.MeccaUnmatched)
 
 	// Phase 2: Migrasi dari td_hotel
-	fmt.Printf("\nPhase 2: Migrating Hotels from td_hotel Table\n")
-	fmt.Printf("==========================================\n")
+	fmt.Print(report.Heading(i18n.T("hotel.phase_td_hotel"), "="))
 
 	// Get total hotels from td_hotel
 	var totalTdHotels int
@@ -280,24 +267,15 @@ func HotelService() {
         WHERE soft_delete = false
     `).Scan(&totalTdHotels)
 	if err != nil {
-		log.Fatal("Error counting td_hotels:", err)
+		logging.Fatal("Error counting td_hotels", "error", err)
 	}
 
-	fmt.Printf("Found %d hotels to transfer from td_hotel\n", totalTdHotels)
+	fmt.Println(i18n.T("hotel.found_td_hotel", totalTdHotels))
+
+	report.SetTotal(totalHotels + totalTdHotels)
 
 	// Progress bar untuk Phase 2
-	barPhase2 := progressbar.NewOptions(totalTdHotels,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/2][reset] Transferring td_hotels..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}))
+	barPhase2 := plan.Start("td_hotel", totalTdHotels)
 
 	// Statistics untuk Phase 2
 	var (
@@ -312,7 +290,7 @@ func HotelService() {
 	// Begin transaction untuk Phase 2
 	txTd, err := devGeneralDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction for td_hotel:", err)
+		logging.Fatal("Error starting transaction for td_hotel", "error", err)
 	}
 
 	// Query untuk mengambil data dari td_hotel dengan city name
@@ -330,7 +308,7 @@ func HotelService() {
         WHERE h.soft_delete = false
     `)
 	if err != nil {
-		log.Fatal("Error querying td_hotel:", err)
+		logging.Fatal("Error querying td_hotel", "error", err)
 	}
 	defer tdHotelRows.Close()
 
@@ -356,21 +334,25 @@ func HotelService() {
 			&cityName,
 		)
 		if err != nil {
-			log.Printf("Error scanning td_hotel row: %v", err)
+			report.Errorf("Error scanning td_hotel row: %v", err)
 			errorTdCount++
+			report.Add(report.OutcomeFailed, 1)
 			barPhase2.Add(1)
 			continue
 		}
+		logging.SetRecord(name)
 
 		// Check if hotel already exists
 		var existingID int
 		err = checkHotelExistStmt.QueryRow(name).Scan(&existingID)
 		if err != sql.ErrNoRows {
 			if err != nil {
-				log.Printf("Error checking hotel existence: %v", err)
+				report.Errorf("Error checking hotel existence: %v", err)
 				errorTdCount++
+				report.Add(report.OutcomeFailed, 1)
 			} else {
 				skippedTdCount++
+				report.Add(report.OutcomeSkipped, 1)
 			}
 			barPhase2.Add(1)
 			continue
@@ -392,27 +374,31 @@ func HotelService() {
 		).Scan(&newID)
 
 		if err != nil {
-			log.Printf("Error inserting hotel: %v", err)
+			report.Errorf("Error inserting hotel: %v", err)
 			errorTdCount++
+			report.Add(report.OutcomeFailed, 1)
 			barPhase2.Add(1)
 			continue
 		}
 
 		insertedTdCount++
+		report.Add(report.OutcomeInserted, 1)
 		processedTdCount++
 		barPhase2.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction Phase 2
-	err = txTd.Commit()
+	err = metrics.Commit(txTd)
 	if err != nil {
-		log.Printf("Error committing td_hotel transaction: %v", err)
+		report.Errorf("Error committing td_hotel transaction: %v", err)
 		txTd.Rollback()
 		return
 	}
 
 	// Update progress bar untuk selesai
 	barPhase2.Finish()
+	cityBar := plan.Start("city names", 0)
 
 	// Standardisasi nama kota Mekah/Mekkah
 	updateMeccaResult, err := devGeneralDB.Exec(`
@@ -422,7 +408,7 @@ func HotelService() {
 		OR city_name = 'Mekah'
 	`)
 	if err != nil {
-		log.Printf("Error updating Mecca city names: %v", err)
+		report.Errorf("Error updating Mecca city names: %v", err)
 	}
 
 	// Standardisasi nama kota Madinah
@@ -432,37 +418,26 @@ func HotelService() {
 		WHERE city_name = 'Madinah'
 	`)
 	if err != nil {
-		log.Printf("Error updating Madinah city names: %v", err)
+		report.Errorf("Error updating Madinah city names: %v", err)
 	}
 
 	meccaRowsAffected, _ := updateMeccaResult.RowsAffected()
 	madinahRowsAffected, _ := updateMadinahResult.RowsAffected()
 
-	fmt.Printf("\n[3/3] City name standardization completed!\n")
-	fmt.Printf("Standardized %d Mecca hotel records\n", meccaRowsAffected)
-	fmt.Printf("Standardized %d Madinah hotel records\n", madinahRowsAffected)
-
-	durationTd := time.Since(startTimeTd)
-
-	// Print summary untuk Phase 2
-	fmt.Printf("\nPhase 2 Migration Summary:\n")
-	fmt.Printf("------------------------\n")
-	fmt.Printf("Total td_hotels found: %d\n", totalTdHotels)
-	fmt.Printf("Successfully processed hotels: %d\n", processedTdCount)
-	fmt.Printf("Successfully inserted hotels: %d\n", insertedTdCount)
-	fmt.Printf("Skipped (already exist): %d\n", skippedTdCount)
-	fmt.Printf("Failed transfers: %d\n", errorTdCount)
-	fmt.Printf("\nPerformance Metrics:\n")
-	fmt.Printf("Duration: %s\n", durationTd.Round(time.Second))
-	fmt.Printf("Average speed: %.2f hotels/second\n", float64(processedTdCount)/durationTd.Seconds())
-
-	// Print overall summary
-	fmt.Printf("\nOverall Migration Summary:\n")
-	fmt.Printf("======================\n")
-	fmt.Printf("Total Phase 1 - Package Hotels: %d\n", processedCount)
-	fmt.Printf("Total Phase 2 - TD Hotels: %d\n", processedTdCount)
-	fmt.Printf("Total Hotels Processed: %d\n", processedCount+processedTdCount)
-	fmt.Printf("Total Hotels Inserted: %d\n", insertedCount+insertedTdCount)
-	fmt.Printf("Total Hotels Skipped: %d\n", skippedCount+skippedTdCount)
-	fmt.Printf("Total Errors: %d\n", errorCount+errorTdCount)
+	cityBar.Finish()
+	fmt.Printf("\n%s\n", i18n.T("standardize.completed"))
+	fmt.Println(i18n.T("standardize.mecca", meccaRowsAffected))
+	fmt.Println(i18n.T("standardize.madinah", madinahRowsAffected))
+
+	report.AddPhase("td_hotel", time.Since(startTimeTd), processedTdCount+skippedTdCount+errorTdCount)
+
+	report.Inserted("hotel", insertedCount+insertedTdCount)
+	report.Updated("hotel", int(meccaRowsAffected+madinahRowsAffected))
+	report.Finish()
+}
+
+func addUnmatchedHotels(city string, unmatched []helper.UnmatchedHotel) {
+	for _, hotel := range unmatched {
+		report.Item(fmt.Sprintf("Unmatched %s hotels (no master hotel row)", city), fmt.Sprintf("%s (%d packages)", hotel.Name, hotel.Packages))
+	}
 }
@@ -0,0 +1,158 @@
+package helper
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// Connections menyimpan satu koneksi per database yang dipakai inspector
+type Connections struct {
+	dbs map[string]*sql.DB
+}
+
+func NewConnections() *Connections {
+	return &Connections{dbs: make(map[string]*sql.DB)}
+}
+
+func (c *Connections) Get(name string) (*sql.DB, error) {
+	if db, ok := c.dbs[name]; ok {
+		return db, nil
+	}
+	connect, ok := Databases[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown database: %s", name)
+	}
+	db := connect()
+	c.dbs[name] = db
+	return db, nil
+}
+
+func (c *Connections) CloseAll() {
+	for _, db := range c.dbs {
+		db.Close()
+	}
+}
+
+// LoadRecords mengambil record legacy secara acak (sample) atau berdasarkan id,
+// lalu mengambil semua potongan sumber dan target untuk setiap record
+func LoadRecords(inspector Inspector, conns *Connections, sample int, id string) ([]Record, error) {
+	sourceDB, err := conns.Get(sourceDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	key := pq.QuoteIdentifier(inspector.Key)
+	table := pq.QuoteIdentifier(inspector.Table)
+
+	var rows *sql.Rows
+	if id != "" {
+		rows, err = sourceDB.Query(fmt.Sprintf(
+			`SELECT t.%s::text, row_to_json(t)::text FROM %s t WHERE t.%s::text = $1`, key, table, key), id)
+	} else {
+		where := "true"
+		if inspector.Filter != "" {
+			where = inspector.Filter
+		}
+		rows, err = sourceDB.Query(fmt.Sprintf(
+			`SELECT t.%s::text, row_to_json(t)::text FROM %s t WHERE %s ORDER BY random() LIMIT $1`, key, table, where), sample)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", inspector.Table, err)
+	}
+
+	type sourceRow struct {
+		key  string
+		json []byte
+	}
+	var mainRows []sourceRow
+	for rows.Next() {
+		var row sourceRow
+		if err := rows.Scan(&row.key, &row.json); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning %s: %v", inspector.Table, err)
+		}
+		mainRows = append(mainRows, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", inspector.Table, err)
+	}
+
+	var records []Record
+	for _, row := range mainRows {
+		var values map[string]interface{}
+		if err := json.Unmarshal(row.json, &values); err != nil {
+			return nil, fmt.Errorf("error decoding %s row %s: %v", inspector.Table, row.key, err)
+		}
+
+		record := Record{
+			Key:    row.key,
+			Source: []NamedJSON{{Name: inspector.Table, Database: sourceDatabase, JSON: row.json}},
+		}
+
+		for _, part := range inspector.Source {
+			result, err := loadPart(conns, part, values)
+			if err != nil {
+				return nil, err
+			}
+			record.Source = append(record.Source, result)
+		}
+		for _, part := range inspector.Target {
+			result, err := loadPart(conns, part, values)
+			if err != nil {
+				return nil, err
+			}
+			record.Target = append(record.Target, result)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// loadPart menjalankan query Part dengan nilai kolom Param dari baris sumber utama
+func loadPart(conns *Connections, part Part, values map[string]interface{}) (NamedJSON, error) {
+	result := NamedJSON{Name: part.Name, Database: part.Database, JSON: []byte("null")}
+
+	param, ok := values[part.Param]
+	if !ok || param == nil {
+		return result, nil
+	}
+
+	db, err := conns.Get(part.Database)
+	if err != nil {
+		return result, err
+	}
+
+	if err := db.QueryRow(part.Query, fmt.Sprint(param)).Scan(&result.JSON); err != nil {
+		return result, fmt.Errorf("error reading %s: %v", part.Name, err)
+	}
+
+	return result, nil
+}
+
+// PrettyJSON menggabungkan potongan menjadi satu objek JSON yang rapi, urutan potongan tetap
+func PrettyJSON(parts []NamedJSON) ([]byte, error) {
+	var raw []byte
+	raw = append(raw, '{')
+	for i, part := range parts {
+		if i > 0 {
+			raw = append(raw, ',')
+		}
+		name, _ := json.Marshal(part.Name)
+		raw = append(raw, name...)
+		raw = append(raw, ':')
+		raw = append(raw, part.JSON...)
+	}
+	raw = append(raw, '}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
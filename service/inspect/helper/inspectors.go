@@ -0,0 +1,172 @@
+package helper
+
+import (
+	"database/sql"
+	"github.com/ApesJs/go-migration-app/database"
+	packageHelper "github.com/ApesJs/go-migration-app/service/package/helper"
+)
+
+const sourceDatabase = "prod existing umrah"
+
+// Databases memetakan nama database di Part ke fungsi koneksinya
+var Databases = map[string]func() *sql.DB{
+	sourceDatabase:   database.ConnectionProdExistingUmrahDB,
+	"local identity": database.ConnectionLocalIdentityDB,
+	"dev identity":   database.ConnectionDevIdentityDB,
+	"dev umrah":      database.ConnectionDevUmrahDB,
+	"dev general":    database.ConnectionDevGeneralDB,
+}
+
+// Inspectors berisi entitas yang bisa diinspeksi, database target mengikuti service migrasinya
+var Inspectors = []Inspector{
+	{
+		Name:        "package",
+		Description: "td_package with hotels, airlines and itinerary vs package, package_variant and package_itinerary",
+		Table:       "td_package",
+		Key:         "id",
+		Filter:      packageHelper.ActivePackageFilter,
+		Source: []Part{
+			{
+				Name: "td_package_hotel", Database: sourceDatabase, Param: "id",
+				Query: `
+					SELECT COALESCE(json_agg(x), '[]')::text FROM (
+						SELECT h.*, c.name AS city_name
+						FROM td_package_hotel ph
+						JOIN td_hotel h ON h.id = ph.hotel_id
+						LEFT JOIN td_city c ON c.id = h.city_id
+						WHERE ph.package_id::text = $1
+					) x
+				`,
+			},
+			{
+				Name: "td_airline", Database: sourceDatabase, Param: "id",
+				Query: `
+					SELECT COALESCE(json_agg(x), '[]')::text FROM (
+						SELECT 'departure' AS leg, a.*
+						FROM td_package p JOIN td_airline a ON a.id = p.departure_airline_id
+						WHERE p.id::text = $1
+						UNION ALL
+						SELECT 'arrival' AS leg, a.*
+						FROM td_package p JOIN td_airline a ON a.id = p.arrival_airline_id
+						WHERE p.id::text = $1
+					) x
+				`,
+			},
+			{
+				Name: "td_package_itinerary", Database: sourceDatabase, Param: "id",
+				Query: `
+					SELECT COALESCE(json_agg(x ORDER BY x."time"), '[]')::text FROM (
+						SELECT i.*, c.name AS city_name
+						FROM td_package_itinerary i
+						LEFT JOIN td_city c ON c.id = i.city_id
+						WHERE i.package_id::text = $1 AND i.soft_delete = false
+					) x
+				`,
+			},
+		},
+		Target: []Part{
+			{
+				Name: "package", Database: "dev umrah", Param: "slug",
+				Query: `
+					SELECT COALESCE(json_agg(p), '[]')::text
+					FROM package p
+					WHERE p.slug = $1 AND p.created_by = 'migration'
+				`,
+			},
+			{
+				Name: "package_variant", Database: "dev umrah", Param: "slug",
+				Query: `
+					SELECT COALESCE(json_agg(v), '[]')::text
+					FROM package_variant v
+					WHERE v.package_id IN (SELECT id FROM package WHERE slug = $1 AND created_by = 'migration')
+				`,
+			},
+			{
+				Name: "package_itinerary", Database: "dev umrah", Param: "slug",
+				Query: `
+					SELECT COALESCE(json_agg(i ORDER BY i.id), '[]')::text
+					FROM package_itinerary i
+					WHERE i.package_id IN (SELECT id FROM package WHERE slug = $1 AND created_by = 'migration')
+				`,
+			},
+		},
+	},
+	{
+		Name:        "user",
+		Description: "td_user vs user",
+		Table:       "td_user",
+		Key:         "id",
+		Filter:      "role = 'user' AND soft_delete = false",
+		Target: []Part{
+			{
+				Name: "user", Database: "local identity", Param: "id",
+				Query: `SELECT COALESCE(json_agg(u), '[]')::text FROM "user" u WHERE u.id::text = $1`,
+			},
+		},
+	},
+	{
+		Name:        "wukala",
+		Description: "td_travel_agent vs user_persona and wukala_setting",
+		Table:       "td_travel_agent",
+		Key:         "user_id",
+		Source: []Part{
+			{
+				Name: "td_user", Database: sourceDatabase, Param: "user_id",
+				Query: `SELECT COALESCE(json_agg(u), '[]')::text FROM td_user u WHERE u.id::text = $1`,
+			},
+		},
+		Target: []Part{
+			{
+				Name: "user_persona", Database: "dev identity", Param: "user_id",
+				Query: `SELECT COALESCE(json_agg(p), '[]')::text FROM user_persona p WHERE p.id::text = $1`,
+			},
+			{
+				Name: "wukala_setting", Database: "dev umrah", Param: "code",
+				Query: `SELECT COALESCE(json_agg(w), '[]')::text FROM wukala_setting w WHERE w.referral_code = left($1, 8)`,
+			},
+		},
+	},
+	{
+		Name:        "organization",
+		Description: "td_travel vs organization, organization_instance and organization_user",
+		Table:       "td_travel",
+		Key:         "id",
+		Target: []Part{
+			{
+				Name: "organization", Database: "local identity", Param: "id",
+				Query: `SELECT COALESCE(json_agg(o), '[]')::text FROM organization o WHERE o.id::text = $1`,
+			},
+			{
+				Name: "organization_instance", Database: "local identity", Param: "id",
+				Query: `SELECT COALESCE(json_agg(i), '[]')::text FROM organization_instance i WHERE i.organization_id::text = $1`,
+			},
+			{
+				Name: "organization_user", Database: "local identity", Param: "id",
+				Query: `SELECT COALESCE(json_agg(u), '[]')::text FROM organization_user u WHERE u.organization_id::text = $1`,
+			},
+		},
+	},
+	{
+		Name:        "hotel",
+		Description: "td_hotel vs hotel (matched by name)",
+		Table:       "td_hotel",
+		Key:         "id",
+		Filter:      "soft_delete = false",
+		Target: []Part{
+			{
+				Name: "hotel", Database: "dev general", Param: "name",
+				Query: `SELECT COALESCE(json_agg(h), '[]')::text FROM hotel h WHERE h.name = $1`,
+			},
+		},
+	},
+}
+
+// FindInspector mencari inspector berdasarkan nama
+func FindInspector(name string) (Inspector, bool) {
+	for _, inspector := range Inspectors {
+		if inspector.Name == name {
+			return inspector, true
+		}
+	}
+	return Inspector{}, false
+}
@@ -0,0 +1,36 @@
+package helper
+
+// Part adalah satu potongan data yang ditampilkan untuk sebuah record.
+// Query menerima $1 berupa nilai kolom Param dari baris sumber utama dan
+// harus mengembalikan satu kolom JSON (biasanya json_agg).
+type Part struct {
+	Name     string
+	Database string
+	Param    string
+	Query    string
+}
+
+// Inspector mendefinisikan cara mengambil satu record legacy beserta hasil migrasinya
+type Inspector struct {
+	Name        string
+	Description string
+	Table       string // tabel legacy utama
+	Key         string // kolom yang dipakai untuk --id
+	Filter      string // filter baris legacy yang ikut dimigrasi, opsional
+	Source      []Part // potongan tambahan dari database legacy
+	Target      []Part // potongan dari database target
+}
+
+// NamedJSON adalah hasil satu Part
+type NamedJSON struct {
+	Name     string
+	Database string
+	JSON     []byte
+}
+
+// Record adalah satu record legacy beserta hasil migrasinya
+type Record struct {
+	Key    string
+	Source []NamedJSON
+	Target []NamedJSON
+}
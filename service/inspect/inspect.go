@@ -0,0 +1,91 @@
+package inspect
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/inspect/helper"
+	"golang.org/x/term"
+	"os"
+	"strings"
+)
+
+const defaultWidth = 160
+
+// InspectService menampilkan record legacy dan hasil migrasinya berdampingan
+// sebagai JSON. Jika id kosong, sample record diambil secara acak.
+func InspectService(name string, sample int, id string) {
+	inspector, ok := helper.FindInspector(name)
+	if !ok {
+		logging.Fatal("Unknown entity", "entity", name)
+	}
+
+	conns := helper.NewConnections()
+	defer conns.CloseAll()
+
+	records, err := helper.LoadRecords(inspector, conns, sample, id)
+	if err != nil {
+		logging.Fatal("Error loading records", "error", err)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("\n%s\n", i18n.T("inspect.none", inspector.Table))
+		return
+	}
+
+	width := defaultWidth
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 40 {
+			width = w
+		}
+	}
+	columnWidth := (width - 3) / 2
+
+	for i, record := range records {
+		source, err := helper.PrettyJSON(record.Source)
+		if err != nil {
+			logging.Fatal("Error formatting source JSON", "error", err)
+		}
+		target, err := helper.PrettyJSON(record.Target)
+		if err != nil {
+			logging.Fatal("Error formatting target JSON", "error", err)
+		}
+
+		fmt.Printf("\n[%d/%d] %s %s = %s\n", i+1, len(records), inspector.Table, inspector.Key, record.Key)
+		fmt.Println(strings.Repeat("=", width-1))
+		printSideBySide(i18n.T("inspect.legacy"), i18n.T("inspect.migrated"), string(source), string(target), columnWidth)
+	}
+}
+
+// printSideBySide mencetak dua teks dalam dua kolom, baris yang terlalu panjang dipotong ke baris berikutnya
+func printSideBySide(leftTitle, rightTitle, left, right string, width int) {
+	leftLines := wrapLines(left, width)
+	rightLines := wrapLines(right, width)
+
+	fmt.Printf("%-*s | %s\n", width, leftTitle, rightTitle)
+	fmt.Printf("%s-+-%s\n", strings.Repeat("-", width), strings.Repeat("-", width))
+
+	for i := 0; i < len(leftLines) || i < len(rightLines); i++ {
+		var l, r string
+		if i < len(leftLines) {
+			l = leftLines[i]
+		}
+		if i < len(rightLines) {
+			r = rightLines[i]
+		}
+		fmt.Printf("%s%s | %s\n", l, strings.Repeat(" ", width-len([]rune(l))), r)
+	}
+}
+
+func wrapLines(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		for len(runes) > width {
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
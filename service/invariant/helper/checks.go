@@ -0,0 +1,139 @@
+package helper
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	"os"
+)
+
+// Databases memetakan nama database di file invariant ke fungsi koneksinya
+var Databases = map[string]func() *sql.DB{
+	"prod existing umrah": database.ConnectionProdExistingUmrahDB,
+	"prod identity":       database.ConnectionProdIdentityDB,
+	"local identity":      database.ConnectionLocalIdentityDB,
+	"dev identity":        database.ConnectionDevIdentityDB,
+	"dev umrah":           database.ConnectionDevUmrahDB,
+	"dev general":         database.ConnectionDevGeneralDB,
+}
+
+// LoadInvariants membaca dan memvalidasi file invariant (JSON array)
+func LoadInvariants(path string) ([]Invariant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var invariants []Invariant
+	if err := json.Unmarshal(data, &invariants); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, inv := range invariants {
+		if inv.Name == "" {
+			return nil, fmt.Errorf("invariant without name in %s", path)
+		}
+		if seen[inv.Name] {
+			return nil, fmt.Errorf("duplicate invariant %q in %s", inv.Name, path)
+		}
+		seen[inv.Name] = true
+
+		if _, ok := Databases[inv.Database]; !ok {
+			return nil, fmt.Errorf("invariant %q: unknown database %q", inv.Name, inv.Database)
+		}
+
+		switch {
+		case inv.Query != "" && inv.Keys == "" && inv.MustExistIn == nil:
+		case inv.Query == "" && inv.Keys != "" && inv.MustExistIn != nil:
+			if inv.MustExistIn.Query == "" {
+				return nil, fmt.Errorf("invariant %q: must_exist_in needs a query", inv.Name)
+			}
+			if _, ok := Databases[inv.MustExistIn.Database]; !ok {
+				return nil, fmt.Errorf("invariant %q: unknown database %q", inv.Name, inv.MustExistIn.Database)
+			}
+		default:
+			return nil, fmt.Errorf("invariant %q needs either query, or keys with must_exist_in", inv.Name)
+		}
+	}
+
+	return invariants, nil
+}
+
+// Connections menyimpan satu koneksi per database yang dipakai invariant
+type Connections struct {
+	dbs map[string]*sql.DB
+}
+
+func NewConnections() *Connections {
+	return &Connections{dbs: make(map[string]*sql.DB)}
+}
+
+func (c *Connections) Get(name string) *sql.DB {
+	if db, ok := c.dbs[name]; ok {
+		return db
+	}
+	db := Databases[name]()
+	c.dbs[name] = db
+	return db
+}
+
+func (c *Connections) CloseAll() {
+	for _, db := range c.dbs {
+		db.Close()
+	}
+}
+
+// Check menjalankan satu invariant
+func Check(conns *Connections, inv Invariant) Result {
+	result := Result{Invariant: inv}
+
+	if inv.MustExistIn == nil {
+		result.Violations, result.Err = loadKeys(conns.Get(inv.Database), inv.Query)
+		return result
+	}
+
+	keys, err := loadKeys(conns.Get(inv.Database), inv.Keys)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Checked = len(keys)
+
+	existing, err := loadKeys(conns.Get(inv.MustExistIn.Database), inv.MustExistIn.Query)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	exists := make(map[string]bool, len(existing))
+	for _, key := range existing {
+		exists[key] = true
+	}
+	for _, key := range keys {
+		if !exists[key] {
+			result.Violations = append(result.Violations, key)
+		}
+	}
+
+	return result
+}
+
+func loadKeys(db *sql.DB, query string) ([]string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key sql.NullString
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.String)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,36 @@
+package helper
+
+// Invariant adalah satu aturan yang harus berlaku setelah migrasi.
+//
+// Bentuk pertama: Query mengembalikan satu kolom teks berisi key setiap baris
+// yang melanggar aturan.
+//
+// Bentuk kedua (lintas database): Keys mengembalikan key yang wajib ada di
+// MustExistIn, misalnya code di identity yang harus punya referral_code di umrah.
+type Invariant struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Database    string     `json:"database"`
+	Query       string     `json:"query,omitempty"`
+	Keys        string     `json:"keys,omitempty"`
+	MustExistIn *Reference `json:"must_exist_in,omitempty"`
+}
+
+// Reference adalah query di database lain yang menghasilkan key pembanding
+type Reference struct {
+	Database string `json:"database"`
+	Query    string `json:"query"`
+}
+
+// Result adalah hasil satu Invariant
+type Result struct {
+	Invariant  Invariant
+	Checked    int // jumlah key yang diperiksa, hanya untuk bentuk lintas database
+	Violations []string
+	Err        error
+}
+
+// Passed true jika invariant terpenuhi
+func (r Result) Passed() bool {
+	return r.Err == nil && len(r.Violations) == 0
+}
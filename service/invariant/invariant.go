@@ -0,0 +1,88 @@
+package invariant
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/invariant/helper"
+	"time"
+)
+
+// InvariantService memeriksa invariant dari file setelah migrasi dan
+// mengembalikan false jika ada yang dilanggar atau gagal dijalankan.
+// names membatasi invariant yang diperiksa.
+func InvariantService(path string, names []string, limit int) bool {
+	invariants, err := helper.LoadInvariants(path)
+	if err != nil {
+		logging.Fatal("Error loading invariants", "error", err)
+	}
+
+	if len(names) > 0 {
+		var selected []helper.Invariant
+		for _, name := range names {
+			found := false
+			for _, inv := range invariants {
+				if inv.Name == name {
+					selected = append(selected, inv)
+					found = true
+				}
+			}
+			if !found {
+				logging.Fatal("Unknown invariant", "invariant", name)
+			}
+		}
+		invariants = selected
+	}
+
+	conns := helper.NewConnections()
+	defer conns.CloseAll()
+
+	startTime := time.Now()
+
+	fmt.Printf("\n%s\n", i18n.T("invariant.title", path))
+	fmt.Printf("------------------------\n")
+
+	var violated, failed int
+	for _, inv := range invariants {
+		result := helper.Check(conns, inv)
+
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Printf("\n[ERROR] %s\n", inv.Name)
+			fmt.Printf("%s\n", inv.Description)
+			fmt.Println(i18n.T("check.error", result.Err))
+			continue
+		case result.Passed():
+			fmt.Printf("\n[OK]    %s\n", inv.Name)
+			continue
+		}
+
+		violated++
+		fmt.Printf("\n[FAIL]  %s\n", inv.Name)
+		fmt.Printf("%s\n", inv.Description)
+		if result.Checked > 0 {
+			fmt.Println(i18n.T("invariant.violations_of", len(result.Violations), result.Checked))
+		} else {
+			fmt.Println(i18n.T("invariant.violations", len(result.Violations)))
+		}
+		for i, key := range result.Violations {
+			if limit > 0 && i >= limit {
+				fmt.Println(i18n.T("invariant.more", len(result.Violations)-limit))
+				break
+			}
+			fmt.Printf("%d. %s\n", i+1, key)
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("check.summary"))
+	fmt.Println(i18n.T("invariant.checked", len(invariants)))
+	fmt.Println(i18n.T("invariant.passed", len(invariants)-violated-failed))
+	fmt.Println(i18n.T("invariant.violated", violated))
+	if failed > 0 {
+		fmt.Println(i18n.T("check.failed", failed))
+	}
+	fmt.Println(i18n.T("invariant.finished", time.Since(startTime).Round(time.Millisecond)))
+
+	return violated == 0 && failed == 0
+}
@@ -0,0 +1,87 @@
+package legacy
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/service/legacy/helper"
+	"sort"
+	"time"
+)
+
+// GenerateLegacyService membuat schema legacy (td_*, tr_rda) di database lokal
+// dan mengisinya dengan data sintetis untuk benchmark dan regression test migrasi.
+func GenerateLegacyService(opts helper.GenerateOptions) {
+	// Buat database legacy lokal jika belum ada
+	database.CreateLocalLegacyDB()
+
+	localLegacyDB := database.ConnectionLocalLegacyDB()
+	defer localLegacyDB.Close()
+
+	generator, err := helper.NewGenerator(opts,
+		"service/airline/seed/airline/airline-indo.json",
+		"service/airline/seed/airline/airline-arab.json",
+	)
+	if err != nil {
+		logging.Fatal("Error preparing generator", "error", err)
+	}
+
+	// Begin transaction
+	tx, err := localLegacyDB.Begin()
+	if err != nil {
+		logging.Fatal("Error starting transaction", "error", err)
+	}
+	defer tx.Rollback()
+
+	if err := helper.CreateLegacySchema(tx, opts.Reset); err != nil {
+		logging.Fatal("Error creating legacy schema", "error", err)
+	}
+
+	totalRows := generator.TotalRows()
+	fmt.Println(i18n.T("legacy.generating", totalRows, opts.Scale, opts.Seed, opts.AnomalyRate))
+
+	// Membuat progress bar
+	bar := progress.Single("legacy rows", totalRows)
+
+	startTime := time.Now()
+
+	stats, err := generator.Generate(tx, bar)
+	if err != nil {
+		logging.Fatal("Error generating legacy data", "error", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		logging.Fatal("Error committing transaction", "error", err)
+	}
+
+	duration := time.Since(startTime)
+
+	bar.Finish()
+	fmt.Print(report.Heading(i18n.T("legacy.summary"), "-"))
+
+	tables := make([]string, 0, len(stats.Rows))
+	for table := range stats.Rows {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	total := 0
+	for _, table := range tables {
+		fmt.Printf("%-22s %d\n", table+":", stats.Rows[table])
+		total += stats.Rows[table]
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("legacy.anomalies"))
+	fmt.Println(i18n.T("legacy.duplicate_emails", stats.DuplicateEmails))
+	fmt.Println(i18n.T("legacy.duplicate_phones", stats.DuplicatePhones))
+	fmt.Println(i18n.T("legacy.duplicate_codes", stats.DuplicateCodes))
+	fmt.Println(i18n.T("legacy.nulls", stats.NullValues))
+	fmt.Println(i18n.T("legacy.overlong", stats.OverlongValues))
+	fmt.Println(i18n.T("legacy.orphans", stats.OrphanRefs))
+	fmt.Printf("\n%s\n", i18n.T("summary.duration", duration.Round(time.Second)))
+	fmt.Println(i18n.T("legacy.speed", float64(total)/duration.Seconds()))
+}
@@ -0,0 +1,586 @@
+package helper
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/progress"
+	airlineHelper "github.com/ApesJs/go-migration-app/service/airline/helper"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/lib/pq"
+	"strings"
+	"time"
+)
+
+// Kota legacy, termasuk ejaan ganda Mekah/Mekkah yang memang ada di data lama
+var legacyCities = []string{
+	"Madinah", "Mekah", "Mekkah", "Jeddah",
+	"Jakarta", "Surabaya", "Bandung", "Medan", "Makassar", "Semarang",
+	"Yogyakarta", "Palembang", "Balikpapan", "Pekanbaru", "Banjarmasin",
+	"Padang", "Malang", "Denpasar",
+}
+
+// Airline yang tidak ada di seed master airline, untuk menguji referensi yang tidak cocok
+var unknownAirlines = []airlineHelper.Airline{
+	{Name: "Legacy Charter Air", Code: "LCA"},
+	{Name: "Garuda Indonesia (Charter)", Code: "GA1"},
+}
+
+var itineraryActivities = []string{
+	"Tiba di Bandara King Abdulaziz",
+	"Check-in hotel dan istirahat",
+	"Sholat berjamaah di Masjidil Haram",
+	"Umrah pertama",
+	"City tour Makkah",
+	"Ziarah Jabal Uhud",
+	"Sholat di Masjid Nabawi",
+	"Ziarah Raudhah",
+	"Perjalanan ke Madinah",
+	"Kembali ke tanah air",
+}
+
+// Generator membuat data legacy yang konsisten secara referensial.
+// Semua id yang sudah dibuat disimpan agar tabel berikutnya bisa mereferensikannya.
+type Generator struct {
+	faker *gofakeit.Faker
+	opts  GenerateOptions
+	stats *GenerateStats
+	bar   *progress.Bar
+
+	cityIDs        map[string]string
+	airlineIDs     []string
+	rdaIDs         []string
+	userIDs        []string
+	userEmails     []string
+	userPhones     []string
+	agentUserIDs   []string
+	agentCodes     []string
+	travelIDs      []string
+	hotelIDsByCity map[string][]string
+	packages       []legacyPackage
+
+	airlines []airlineHelper.Airline
+}
+
+func NewGenerator(opts GenerateOptions, airlineSeedFiles ...string) (*Generator, error) {
+	if opts.Scale < 1 {
+		opts.Scale = 1
+	}
+
+	g := &Generator{
+		faker:          gofakeit.New(opts.Seed),
+		opts:           opts,
+		stats:          &GenerateStats{Rows: make(map[string]int)},
+		cityIDs:        make(map[string]string),
+		hotelIDsByCity: make(map[string][]string),
+	}
+
+	for _, file := range airlineSeedFiles {
+		airlines, err := airlineHelper.ReadAirlineJSON(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading airline seed %s: %v", file, err)
+		}
+		g.airlines = append(g.airlines, airlines...)
+	}
+	g.airlines = append(g.airlines, unknownAirlines...)
+
+	return g, nil
+}
+
+// TotalRows menghitung perkiraan total baris untuk progress bar.
+// Jumlah itinerary per package ditentukan di sini agar total-nya pasti.
+func (g *Generator) TotalRows() int {
+	scale := g.opts.Scale
+	users := baseUserCount * scale
+	travels := baseTravelCount * scale
+	packages := basePackageCount * scale
+
+	if g.packages == nil {
+		g.packages = make([]legacyPackage, packages)
+		for i := range g.packages {
+			g.packages[i].Itineraries = g.faker.Number(minItineraryPerPack, maxItineraryPerPack)
+		}
+	}
+
+	itineraries := 0
+	for _, pkg := range g.packages {
+		itineraries += pkg.Itineraries
+	}
+
+	return len(legacyCities) + len(g.airlines) + baseRdaCount*scale + users +
+		travels + int(float64(users)*agentRatio) + travels*travelUsersPerTravel +
+		baseHotelCount*scale + packages + packages*2 + itineraries
+}
+
+// Generate mengisi semua tabel legacy di dalam satu transaksi
+func (g *Generator) Generate(tx *sql.Tx, bar *progress.Bar) (*GenerateStats, error) {
+	g.bar = bar
+	g.TotalRows()
+	startTime := time.Now()
+
+	steps := []func(*sql.Tx) error{
+		g.generateCities,
+		g.generateAirlines,
+		g.generateRdas,
+		g.generateUsers,
+		g.generateTravels,
+		g.generateTravelAgents,
+		g.generateTravelUsers,
+		g.generateHotels,
+		g.generatePackages,
+		g.generatePackageHotels,
+		g.generateItineraries,
+	}
+
+	for _, step := range steps {
+		if err := step(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	g.stats.Duration = time.Since(startTime)
+	return g.stats, nil
+}
+
+// copyInto menulis count baris ke table memakai COPY agar cepat untuk skala besar
+func (g *Generator) copyInto(tx *sql.Tx, table string, columns []string, count int, row func(i int) []interface{}) error {
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return fmt.Errorf("error preparing copy into %s: %v", table, err)
+	}
+
+	for i := 0; i < count; i++ {
+		if _, err := stmt.Exec(row(i)...); err != nil {
+			stmt.Close()
+			return fmt.Errorf("error copying row %d into %s: %v", i, table, err)
+		}
+		if g.bar != nil {
+			g.bar.Add(1)
+		}
+	}
+
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return fmt.Errorf("error flushing copy into %s: %v", table, err)
+	}
+
+	g.stats.Rows[table] += count
+	return stmt.Close()
+}
+
+func (g *Generator) anomaly() bool {
+	return g.faker.Rand.Float64() < g.opts.AnomalyRate
+}
+
+// nullable mengembalikan NULL sesuai anomaly rate, selain itu value apa adanya
+func (g *Generator) nullable(value interface{}) interface{} {
+	if g.anomaly() {
+		g.stats.NullValues++
+		return nil
+	}
+	return value
+}
+
+// overlong membuat value melebihi limit kolom target sesuai anomaly rate
+func (g *Generator) overlong(value string, limit int, suffix string) string {
+	if !g.anomaly() {
+		return value
+	}
+	g.stats.OverlongValues++
+	for len(value) <= limit {
+		value += suffix
+	}
+	return value
+}
+
+// orphan mengganti id dengan UUID acak yang tidak ada di tabel referensi
+func (g *Generator) orphan(id string) string {
+	if g.anomaly() {
+		g.stats.OrphanRefs++
+		return g.faker.UUID()
+	}
+	return id
+}
+
+func (g *Generator) pick(ids []string) string {
+	return ids[g.faker.Number(0, len(ids)-1)]
+}
+
+func (g *Generator) timestamps() (time.Time, time.Time) {
+	createdAt := g.faker.DateRange(
+		time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	)
+	updatedAt := createdAt.Add(time.Duration(g.faker.Number(0, 90*24)) * time.Hour)
+	return createdAt, updatedAt
+}
+
+func (g *Generator) phone() string {
+	return g.faker.Numerify("08##########")
+}
+
+func (g *Generator) imagePath(folder string) string {
+	return fmt.Sprintf("%s/%s.jpg", folder, g.faker.LetterN(12))
+}
+
+func (g *Generator) generateCities(tx *sql.Tx) error {
+	columns := []string{"id", "name"}
+	return g.copyInto(tx, "td_city", columns, len(legacyCities), func(i int) []interface{} {
+		id := g.faker.UUID()
+		g.cityIDs[legacyCities[i]] = id
+		return []interface{}{id, legacyCities[i]}
+	})
+}
+
+func (g *Generator) generateAirlines(tx *sql.Tx) error {
+	columns := []string{"id", "code", "name", "logo", "created_at", "updated_at"}
+	return g.copyInto(tx, "td_airline", columns, len(g.airlines), func(i int) []interface{} {
+		id := g.faker.UUID()
+		g.airlineIDs = append(g.airlineIDs, id)
+		createdAt, updatedAt := g.timestamps()
+		return []interface{}{
+			id, g.airlines[i].Code, g.airlines[i].Name,
+			g.nullable(g.imagePath("airline")), createdAt, updatedAt,
+		}
+	})
+}
+
+func (g *Generator) generateRdas(tx *sql.Tx) error {
+	columns := []string{"id", "name", "email", "phone", "created_at", "updated_at"}
+	return g.copyInto(tx, "tr_rda", columns, baseRdaCount*g.opts.Scale, func(i int) []interface{} {
+		id := g.faker.UUID()
+		g.rdaIDs = append(g.rdaIDs, id)
+		createdAt, updatedAt := g.timestamps()
+		return []interface{}{id, g.faker.Name(), g.faker.Email(), g.phone(), createdAt, updatedAt}
+	})
+}
+
+func (g *Generator) generateUsers(tx *sql.Tx) error {
+	columns := []string{
+		"id", "name", "email", "role", "image", "phone", "address",
+		"gender", "job", "pob", "dob", "soft_delete", "created_at", "updated_at",
+	}
+	genders := []string{"laki-laki", "perempuan"}
+
+	return g.copyInto(tx, "td_user", columns, baseUserCount*g.opts.Scale, func(i int) []interface{} {
+		id := g.faker.UUID()
+		g.userIDs = append(g.userIDs, id)
+
+		email := strings.ToLower(g.faker.Email())
+		if len(g.userEmails) > 0 && g.anomaly() {
+			email = g.pick(g.userEmails)
+			g.stats.DuplicateEmails++
+		}
+		g.userEmails = append(g.userEmails, email)
+
+		phone := g.phone()
+		if len(g.userPhones) > 0 && g.anomaly() {
+			phone = g.pick(g.userPhones)
+			g.stats.DuplicatePhones++
+		}
+		g.userPhones = append(g.userPhones, phone)
+
+		role := "user"
+		if g.faker.Number(1, 100) <= 3 {
+			role = "admin"
+		}
+
+		createdAt, updatedAt := g.timestamps()
+		dob := g.faker.DateRange(
+			time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2005, 12, 31, 0, 0, 0, 0, time.UTC),
+		)
+
+		return []interface{}{
+			id,
+			g.faker.Name(),
+			email,
+			role,
+			g.nullable(g.imagePath("user")),
+			g.nullable(g.overlong(phone, 16, " / "+g.phone())),
+			g.nullable(g.faker.Street() + ", " + g.faker.City()),
+			g.nullable(g.overlong(g.faker.RandomString(genders), 16, " (tidak diisi)")),
+			g.nullable(g.faker.JobTitle()),
+			g.nullable(g.overlong(g.faker.RandomString(legacyCities[4:]), 10, " Kabupaten")),
+			g.nullable(dob),
+			g.faker.Number(1, 100) <= 2,
+			createdAt,
+			updatedAt,
+		}
+	})
+}
+
+func (g *Generator) generateTravels(tx *sql.Tx) error {
+	columns := []string{
+		"id", "name", "slug", "desc", "address", "image", "phone", "email",
+		"rda_id", "xendit_channel", "xendit_account_number", "xendit_account_name",
+		"pic_name", "pic_phone", "tagline", "action_profile", "action_package",
+		"own_guide", "fee_type", "fee_amount", "ppiu", "pihk", "is_consultation",
+		"city_id", "is_active", "soft_delete", "created_at", "updated_at",
+	}
+	banks := []string{"BCA", "BNI", "BRI", "MANDIRI", "BSI"}
+
+	return g.copyInto(tx, "td_travel", columns, baseTravelCount*g.opts.Scale, func(i int) []interface{} {
+		id := g.faker.UUID()
+		g.travelIDs = append(g.travelIDs, id)
+
+		name := g.faker.Company() + " Tour & Travel"
+		var slug interface{} = strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+		if g.anomaly() {
+			slug = nil
+			g.stats.NullValues++
+		}
+
+		var rdaID interface{} = g.orphan(g.pick(g.rdaIDs))
+		if g.anomaly() {
+			rdaID = nil
+			g.stats.NullValues++
+		}
+
+		createdAt, updatedAt := g.timestamps()
+		return []interface{}{
+			id,
+			name,
+			slug,
+			g.nullable(g.faker.Sentence(12)),
+			g.nullable(g.faker.Street() + ", " + g.faker.City()),
+			g.nullable(g.imagePath("travel")),
+			g.nullable(g.phone()),
+			g.nullable(strings.ToLower(g.faker.Email())),
+			rdaID,
+			g.nullable(g.faker.RandomString(banks)),
+			g.nullable(g.faker.Numerify("##########")),
+			g.nullable(name),
+			g.nullable(g.faker.Name()),
+			g.nullable(g.phone()),
+			g.nullable(g.faker.Sentence(5)),
+			g.faker.Bool(),
+			g.faker.Bool(),
+			g.faker.Bool(),
+			g.nullable(g.faker.RandomString([]string{"nominal", "percentage"})),
+			g.faker.Float64Range(0, 1500000),
+			g.nullable(g.faker.Numerify("PPIU/###/####")),
+			g.nullable(g.faker.Numerify("PIHK/###/####")),
+			g.faker.Bool(),
+			g.nullable(g.cityIDs[g.faker.RandomString(legacyCities[4:])]),
+			g.faker.Number(1, 100) <= 90,
+			g.faker.Number(1, 100) <= 3,
+			createdAt,
+			updatedAt,
+		}
+	})
+}
+
+func (g *Generator) generateTravelAgents(tx *sql.Tx) error {
+	columns := []string{
+		"id", "user_id", "travel_id", "parent_id", "rda_id", "city_id", "approved_by",
+		"phone", "desc", "code", "fee", "fee_type", "discount", "discount_type",
+		"web_visit", "alias", "nik", "instagram", "account_bank", "account_number",
+		"account_name", "address", "activated_at", "approved_at", "created_at", "updated_at",
+	}
+	count := int(float64(len(g.userIDs)) * agentRatio)
+	feeTypes := []string{"nominal", "percentage"}
+
+	return g.copyInto(tx, "td_travel_agent", columns, count, func(i int) []interface{} {
+		// Wukala diambil berurutan dari user agar user_id unik
+		userID := g.userIDs[i*len(g.userIDs)/count]
+
+		var parentID interface{}
+		if len(g.agentUserIDs) > 0 && g.faker.Number(1, 100) <= 30 {
+			parentID = g.orphan(g.pick(g.agentUserIDs))
+		}
+		g.agentUserIDs = append(g.agentUserIDs, userID)
+
+		code := strings.ToUpper(g.faker.LetterN(uint(g.faker.Number(5, 8))))
+		if len(g.agentCodes) > 0 && g.anomaly() {
+			code = g.pick(g.agentCodes)
+			g.stats.DuplicateCodes++
+		}
+		g.agentCodes = append(g.agentCodes, code)
+
+		phone := g.phone()
+		if g.anomaly() {
+			phone = g.pick(g.userPhones)
+			g.stats.DuplicatePhones++
+		}
+
+		createdAt, updatedAt := g.timestamps()
+		activatedAt := createdAt.Add(time.Duration(g.faker.Number(1, 72)) * time.Hour)
+
+		return []interface{}{
+			g.faker.UUID(),
+			userID,
+			g.nullable(g.orphan(g.pick(g.travelIDs))),
+			parentID,
+			g.nullable(g.orphan(g.pick(g.rdaIDs))),
+			g.nullable(g.orphan(g.cityIDs[g.faker.RandomString(legacyCities[4:])])),
+			g.nullable(g.pick(g.rdaIDs)),
+			g.nullable(g.overlong(phone, 16, " / "+g.phone())),
+			g.nullable(g.faker.Sentence(10)),
+			g.nullable(g.overlong(code, 8, "X")),
+			g.nullable(g.faker.Float64Range(0, 500000)),
+			g.nullable(g.overlong(g.faker.RandomString(feeTypes), 12, "_per_jamaah")),
+			g.nullable(g.faker.Float64Range(0, 250000)),
+			g.nullable(g.overlong(g.faker.RandomString(feeTypes), 12, "_per_jamaah")),
+			g.nullable(g.faker.Number(0, 5000)),
+			g.nullable(g.faker.Username()),
+			g.nullable(g.faker.Numerify("################")),
+			g.nullable("@" + g.faker.Username()),
+			g.nullable(g.faker.RandomString([]string{"BCA", "BNI", "BRI", "MANDIRI", "BSI"})),
+			g.nullable(g.faker.Numerify("##########")),
+			g.nullable(g.faker.Name()),
+			g.nullable(g.faker.Street() + ", " + g.faker.City()),
+			g.nullable(activatedAt),
+			g.nullable(activatedAt),
+			g.nullable(createdAt),
+			g.nullable(updatedAt),
+		}
+	})
+}
+
+func (g *Generator) generateTravelUsers(tx *sql.Tx) error {
+	columns := []string{"id", "travel_id", "user_id", "role"}
+	count := len(g.travelIDs) * travelUsersPerTravel
+
+	return g.copyInto(tx, "td_travel_user", columns, count, func(i int) []interface{} {
+		role := "admin"
+		if i%travelUsersPerTravel != 0 {
+			role = "staff"
+		}
+		return []interface{}{
+			g.faker.UUID(),
+			g.orphan(g.travelIDs[i/travelUsersPerTravel]),
+			g.orphan(g.pick(g.userIDs)),
+			role,
+		}
+	})
+}
+
+func (g *Generator) generateHotels(tx *sql.Tx) error {
+	columns := []string{"id", "name", "address", "rate", "logo", "city_id", "soft_delete", "created_at", "updated_at"}
+	hotelCities := []string{"Madinah", "Mekah", "Mekkah", "Jeddah"}
+
+	return g.copyInto(tx, "td_hotel", columns, baseHotelCount*g.opts.Scale, func(i int) []interface{} {
+		id := g.faker.UUID()
+		city := hotelCities[i%len(hotelCities)]
+		g.hotelIDsByCity[city] = append(g.hotelIDsByCity[city], id)
+
+		createdAt, updatedAt := g.timestamps()
+		return []interface{}{
+			id,
+			fmt.Sprintf("%s %s Hotel", g.faker.LastName(), city),
+			g.faker.Street() + ", " + city,
+			g.faker.Number(3, 5),
+			g.imagePath("hotel"),
+			g.cityIDs[city],
+			g.faker.Number(1, 100) <= 3,
+			createdAt,
+			updatedAt,
+		}
+	})
+}
+
+func (g *Generator) generatePackages(tx *sql.Tx) error {
+	columns := []string{
+		"id", "travel_id", "departure_airline_id", "arrival_airline_id", "name", "slug",
+		"image", "type", "share_desc", "term_condition", "facility", "currency",
+		"dp_type", "dp_amount", "fee_type", "fee_amount", "departure_date", "arrival_date",
+		"price_double", "price_triple", "price_quad", "closed", "soft_delete",
+		"created_at", "updated_at",
+	}
+
+	return g.copyInto(tx, "td_package", columns, len(g.packages), func(i int) []interface{} {
+		id := g.faker.UUID()
+		departureDate := g.faker.DateRange(
+			time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		)
+		arrivalDate := departureDate.AddDate(0, 0, g.faker.Number(9, 16))
+		g.packages[i].ID = id
+		g.packages[i].DepartureDate = departureDate
+
+		packageType := "1"
+		if g.faker.Number(1, 100) <= 10 {
+			packageType = "2"
+		}
+
+		name := fmt.Sprintf("Paket Umrah %d Hari %s", arrivalDate.Sub(departureDate)/(24*time.Hour), departureDate.Format("January 2006"))
+		priceQuad := g.faker.Price(25000000, 45000000)
+		createdAt, updatedAt := g.timestamps()
+
+		return []interface{}{
+			id,
+			g.orphan(g.pick(g.travelIDs)),
+			g.pick(g.airlineIDs),
+			g.pick(g.airlineIDs),
+			name,
+			g.nullable(strings.ToLower(strings.ReplaceAll(name, " ", "-")) + "-" + g.faker.LetterN(4)),
+			g.nullable(g.imagePath("package")),
+			packageType,
+			g.nullable(g.faker.Paragraph(1, 3, 12, " ")),
+			g.nullable(g.faker.Paragraph(1, 4, 12, " ")),
+			g.nullable(g.faker.Sentence(15)),
+			"IDR",
+			g.faker.RandomString([]string{"fixed", "percentage"}),
+			g.faker.Price(1000000, 10000000),
+			g.faker.RandomString([]string{"nominal", "percentage"}),
+			g.faker.Price(0, 2000000),
+			departureDate,
+			arrivalDate,
+			priceQuad + g.faker.Price(4000000, 8000000),
+			priceQuad + g.faker.Price(1000000, 4000000),
+			priceQuad,
+			g.faker.Number(0, 30),
+			g.faker.Number(1, 100) <= 5,
+			createdAt,
+			updatedAt,
+		}
+	})
+}
+
+func (g *Generator) generatePackageHotels(tx *sql.Tx) error {
+	columns := []string{"id", "package_id", "hotel_id"}
+
+	return g.copyInto(tx, "td_package_hotel", columns, len(g.packages)*2, func(i int) []interface{} {
+		pkg := g.packages[i/2]
+		city := "Madinah"
+		if i%2 == 1 {
+			city = g.faker.RandomString([]string{"Mekah", "Mekkah"})
+		}
+		return []interface{}{g.faker.UUID(), pkg.ID, g.orphan(g.pick(g.hotelIDsByCity[city]))}
+	})
+}
+
+func (g *Generator) generateItineraries(tx *sql.Tx) error {
+	columns := []string{"id", "package_id", "time", "activity", "city_id", "soft_delete", "created_at"}
+
+	// Ratakan daftar itinerary per package menjadi satu urutan baris
+	type itineraryRef struct {
+		pkg   legacyPackage
+		index int
+	}
+	var refs []itineraryRef
+	for _, pkg := range g.packages {
+		for j := 0; j < pkg.Itineraries; j++ {
+			refs = append(refs, itineraryRef{pkg: pkg, index: j})
+		}
+	}
+	itineraryCities := []string{"Jeddah", "Mekah", "Madinah"}
+
+	return g.copyInto(tx, "td_package_itinerary", columns, len(refs), func(i int) []interface{} {
+		ref := refs[i]
+		day := ref.index / 2
+		activityTime := ref.pkg.DepartureDate.
+			AddDate(0, 0, day).
+			Add(time.Duration(g.faker.Number(5, 21)) * time.Hour)
+
+		return []interface{}{
+			g.faker.UUID(),
+			ref.pkg.ID,
+			activityTime,
+			g.faker.RandomString(itineraryActivities),
+			g.cityIDs[itineraryCities[day%len(itineraryCities)]],
+			g.faker.Number(1, 100) <= 2,
+			activityTime.AddDate(0, 0, -30),
+		}
+	})
+}
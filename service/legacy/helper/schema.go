@@ -0,0 +1,203 @@
+package helper
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// LegacyTables berisi urutan tabel legacy (umrah lama) yang dibuat generate-legacy.
+// Urutan drop adalah kebalikan dari urutan ini.
+var LegacyTables = []string{
+	"td_city",
+	"td_airline",
+	"tr_rda",
+	"td_user",
+	"td_travel",
+	"td_travel_agent",
+	"td_travel_user",
+	"td_hotel",
+	"td_package",
+	"td_package_hotel",
+	"td_package_itinerary",
+}
+
+// Sengaja tanpa foreign key, karena data legacy memang memiliki referensi yatim
+var legacySchemaQueries = []string{
+	`CREATE TABLE IF NOT EXISTS td_city (
+		id         UUID PRIMARY KEY,
+		name       TEXT NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_airline (
+		id         UUID PRIMARY KEY,
+		code       TEXT NOT NULL,
+		name       TEXT NOT NULL,
+		logo       TEXT,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS tr_rda (
+		id         UUID PRIMARY KEY,
+		name       TEXT NOT NULL,
+		email      TEXT NOT NULL,
+		phone      TEXT NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_user (
+		id          UUID PRIMARY KEY,
+		name        TEXT NOT NULL,
+		email       TEXT NOT NULL,
+		role        TEXT NOT NULL,
+		image       TEXT,
+		phone       TEXT,
+		address     TEXT,
+		gender      TEXT,
+		job         TEXT,
+		pob         TEXT,
+		dob         TIMESTAMP WITH TIME ZONE,
+		soft_delete BOOLEAN NOT NULL DEFAULT false,
+		created_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at  TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_travel (
+		id                    UUID PRIMARY KEY,
+		name                  TEXT NOT NULL,
+		slug                  TEXT,
+		"desc"                TEXT,
+		address               TEXT,
+		image                 TEXT,
+		phone                 TEXT,
+		email                 TEXT,
+		rda_id                UUID,
+		xendit_channel        TEXT,
+		xendit_account_number TEXT,
+		xendit_account_name   TEXT,
+		pic_name              TEXT,
+		pic_phone             TEXT,
+		tagline               TEXT,
+		action_profile        BOOLEAN NOT NULL DEFAULT false,
+		action_package        BOOLEAN NOT NULL DEFAULT false,
+		own_guide             BOOLEAN NOT NULL DEFAULT false,
+		fee_type              TEXT,
+		fee_amount            DOUBLE PRECISION NOT NULL DEFAULT 0,
+		ppiu                  TEXT,
+		pihk                  TEXT,
+		is_consultation       BOOLEAN NOT NULL DEFAULT false,
+		city_id               UUID,
+		is_active             BOOLEAN NOT NULL DEFAULT true,
+		soft_delete           BOOLEAN NOT NULL DEFAULT false,
+		created_at            TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at            TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_travel_agent (
+		id             UUID PRIMARY KEY,
+		user_id        UUID NOT NULL,
+		travel_id      UUID,
+		parent_id      UUID,
+		rda_id         UUID,
+		city_id        UUID,
+		approved_by    UUID,
+		phone          TEXT,
+		"desc"         TEXT,
+		code           TEXT,
+		fee            DOUBLE PRECISION,
+		fee_type       TEXT,
+		discount       DOUBLE PRECISION,
+		discount_type  TEXT,
+		web_visit      INTEGER,
+		alias          TEXT,
+		nik            TEXT,
+		instagram      TEXT,
+		account_bank   TEXT,
+		account_number TEXT,
+		account_name   TEXT,
+		address        TEXT,
+		activated_at   TIMESTAMP WITH TIME ZONE,
+		approved_at    TIMESTAMP WITH TIME ZONE,
+		created_at     TIMESTAMP WITH TIME ZONE,
+		updated_at     TIMESTAMP WITH TIME ZONE
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_travel_user (
+		id        UUID PRIMARY KEY,
+		travel_id UUID NOT NULL,
+		user_id   UUID NOT NULL,
+		role      TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_hotel (
+		id          UUID PRIMARY KEY,
+		name        TEXT NOT NULL,
+		address     TEXT NOT NULL,
+		rate        INTEGER NOT NULL,
+		logo        TEXT NOT NULL,
+		city_id     UUID NOT NULL,
+		soft_delete BOOLEAN NOT NULL DEFAULT false,
+		created_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at  TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_package (
+		id                   UUID PRIMARY KEY,
+		travel_id            UUID NOT NULL,
+		departure_airline_id UUID NOT NULL,
+		arrival_airline_id   UUID NOT NULL,
+		name                 TEXT NOT NULL,
+		slug                 TEXT,
+		image                TEXT,
+		type                 TEXT NOT NULL,
+		share_desc           TEXT,
+		term_condition       TEXT,
+		facility             TEXT,
+		currency             TEXT NOT NULL,
+		dp_type              TEXT NOT NULL,
+		dp_amount            DOUBLE PRECISION NOT NULL,
+		fee_type             TEXT NOT NULL,
+		fee_amount           DOUBLE PRECISION NOT NULL,
+		departure_date       DATE NOT NULL,
+		arrival_date         DATE NOT NULL,
+		price_double         DOUBLE PRECISION NOT NULL,
+		price_triple         DOUBLE PRECISION NOT NULL,
+		price_quad           DOUBLE PRECISION NOT NULL,
+		closed               INTEGER NOT NULL DEFAULT 0,
+		soft_delete          BOOLEAN NOT NULL DEFAULT false,
+		created_at           TIMESTAMP WITH TIME ZONE NOT NULL,
+		updated_at           TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_package_hotel (
+		id         UUID PRIMARY KEY,
+		package_id UUID NOT NULL,
+		hotel_id   UUID NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS td_package_itinerary (
+		id          UUID PRIMARY KEY,
+		package_id  UUID NOT NULL,
+		"time"      TIMESTAMP WITH TIME ZONE NOT NULL,
+		activity    TEXT NOT NULL,
+		city_id     UUID NOT NULL,
+		soft_delete BOOLEAN NOT NULL DEFAULT false,
+		created_at  TIMESTAMP WITH TIME ZONE NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS td_travel_agent_user_id_idx ON td_travel_agent (user_id)`,
+	`CREATE INDEX IF NOT EXISTS td_package_hotel_package_id_idx ON td_package_hotel (package_id)`,
+	`CREATE INDEX IF NOT EXISTS td_package_itinerary_package_id_idx ON td_package_itinerary (package_id)`,
+}
+
+// CreateLegacySchema membuat semua tabel legacy. Jika reset true, tabel lama di-drop dulu.
+func CreateLegacySchema(tx *sql.Tx, reset bool) error {
+	if reset {
+		for i := len(LegacyTables) - 1; i >= 0; i-- {
+			_, err := tx.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS %s`, LegacyTables[i]))
+			if err != nil {
+				return fmt.Errorf("error dropping %s: %v", LegacyTables[i], err)
+			}
+		}
+	}
+
+	for _, query := range legacySchemaQueries {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("error creating legacy schema: %v", err)
+		}
+	}
+
+	return nil
+}
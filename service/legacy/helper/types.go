@@ -0,0 +1,43 @@
+package helper
+
+import "time"
+
+// GenerateOptions mengatur ukuran dan sifat dataset legacy sintetis
+type GenerateOptions struct {
+	Scale       int     // pengali jumlah baris, 1 = dataset kecil
+	Seed        int64   // seed gofakeit agar dataset bisa diulang
+	AnomalyRate float64 // peluang (0-1) menyisipkan duplikat, NULL dan nilai kepanjangan
+	Reset       bool    // drop tabel legacy sebelum generate
+}
+
+// Jumlah baris dasar untuk Scale = 1
+const (
+	baseRdaCount     = 10
+	baseTravelCount  = 50
+	baseUserCount    = 2000
+	baseHotelCount   = 60
+	basePackageCount = 300
+
+	agentRatio           = 0.2
+	travelUsersPerTravel = 2
+	minItineraryPerPack  = 3
+	maxItineraryPerPack  = 8
+)
+
+// GenerateStats mencatat hasil generate per tabel dan anomali yang disisipkan
+type GenerateStats struct {
+	Rows            map[string]int
+	DuplicateEmails int
+	DuplicatePhones int
+	DuplicateCodes  int
+	NullValues      int
+	OverlongValues  int
+	OrphanRefs      int
+	Duration        time.Duration
+}
+
+type legacyPackage struct {
+	ID            string
+	DepartureDate time.Time
+	Itineraries   int
+}
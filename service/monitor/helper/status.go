@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// StatusBoard menyimpan status drift terbaru untuk endpoint dan file status
+type StatusBoard struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+func (b *StatusBoard) Set(status Status) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.status = status
+}
+
+func (b *StatusBoard) Get() Status {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.status
+}
+
+// ServeHTTP mengembalikan status terbaru sebagai JSON. Status code 409 jika drifting,
+// supaya bisa langsung dipakai health check.
+func (b *StatusBoard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := b.Get()
+
+	w.Header().Set("Content-Type", "application/json")
+	if status.Drifting {
+		w.WriteHeader(http.StatusConflict)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(status)
+}
+
+// WriteStatusFile menulis status ke file lewat file sementara, supaya pembaca
+// tidak pernah melihat file yang setengah ditulis
+func WriteStatusFile(path string, status Status) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".drift-status-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// EnsureTable membuat tabel riwayat drift di database lokal jika belum ada
+func EnsureTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS migration_drift (
+			id           BIGSERIAL PRIMARY KEY,
+			checked_at   TIMESTAMPTZ NOT NULL,
+			entity       TEXT NOT NULL,
+			source_count INTEGER NOT NULL,
+			target_count INTEGER NOT NULL,
+			matched      INTEGER NOT NULL,
+			missing      INTEGER NOT NULL,
+			extra        INTEGER NOT NULL,
+			duration_ms  BIGINT NOT NULL,
+			error        TEXT
+		);
+		CREATE INDEX IF NOT EXISTS migration_drift_entity_checked_at
+			ON migration_drift (entity, checked_at DESC);
+	`)
+	if err != nil {
+		return fmt.Errorf("error creating migration_drift table: %v", err)
+	}
+	return nil
+}
+
+// SaveCheck menyimpan hasil satu pemeriksaan
+func SaveCheck(db *sql.DB, check Check) error {
+	var checkErr interface{}
+	if check.Error != "" {
+		checkErr = check.Error
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO migration_drift (
+			checked_at, entity, source_count, target_count,
+			matched, missing, extra, duration_ms, error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, check.CheckedAt, check.Entity, check.SourceCount, check.TargetCount,
+		check.Matched, check.Missing, check.Extra, check.DurationMs, checkErr)
+	if err != nil {
+		return fmt.Errorf("error saving drift check: %v", err)
+	}
+	return nil
+}
+
+// LastMissing mengembalikan jumlah missing terakhir yang berhasil dicek untuk entitas,
+// dipakai untuk menghitung NewMissing setelah monitor di-restart
+func LastMissing(db *sql.DB, entity string) (int, bool, error) {
+	var missing int
+	err := db.QueryRow(`
+		SELECT missing FROM migration_drift
+		WHERE entity = $1 AND error IS NULL
+		ORDER BY checked_at DESC
+		LIMIT 1
+	`, entity).Scan(&missing)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return missing, true, nil
+}
@@ -0,0 +1,26 @@
+package helper
+
+import "time"
+
+// Check adalah hasil rekonsiliasi satu entitas pada satu putaran monitor
+type Check struct {
+	CheckedAt   time.Time `json:"checked_at"`
+	Entity      string    `json:"entity"`
+	SourceCount int       `json:"source_count"`
+	TargetCount int       `json:"target_count"`
+	Matched     int       `json:"matched"`
+	Missing     int       `json:"missing"` // ada di legacy tapi belum dimigrasi
+	Extra       int       `json:"extra"`
+	NewMissing  int       `json:"new_missing"` // selisih Missing dengan putaran sebelumnya
+	DurationMs  int64     `json:"duration_ms"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// Status adalah drift terbaru yang ditampilkan lewat file atau endpoint status
+type Status struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	NextCheck time.Time `json:"next_check"`
+	Interval  string    `json:"interval"`
+	Drifting  bool      `json:"drifting"` // true jika ada record legacy yang belum dimigrasi
+	Checks    []Check   `json:"checks"`
+}
@@ -0,0 +1,172 @@
+package monitor
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/monitor/helper"
+	reconcileHelper "github.com/ApesJs/go-migration-app/service/reconcile/helper"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DefaultEntities adalah entitas yang dipantau jika tidak dipilih
+var DefaultEntities = []string{"user", "wukala", "organization", "package"}
+
+// Options mengatur monitor drift
+type Options struct {
+	Interval   time.Duration
+	StatusFile string // kosong = tidak menulis file status
+	Listen     string // alamat endpoint status, kosong = tanpa HTTP
+	Once       bool   // satu putaran saja, untuk cron
+}
+
+// MonitorService menjalankan rekonsiliasi berulang selama masa parallel-run.
+// Hasil setiap putaran disimpan di tabel migration_drift (database local general)
+// dan drift terbaru ditampilkan lewat file status dan/atau endpoint HTTP.
+func MonitorService(names []string, opts Options) {
+	if len(names) == 0 {
+		names = DefaultEntities
+	}
+
+	var entities []reconcileHelper.Entity
+	for _, name := range names {
+		entity, ok := reconcileHelper.FindEntity(name)
+		if !ok {
+			logging.Fatal("Unknown entity", "entity", name)
+		}
+		entities = append(entities, entity)
+	}
+
+	// Setiap putaran harus membaca snapshot baru, bukan snapshot yang di-export run lain
+	os.Unsetenv("PROD_EXISTING_DB_SNAPSHOT_ID")
+
+	localGeneralDB := database.ConnectionLocalGeneralDB()
+	defer localGeneralDB.Close()
+
+	if err := helper.EnsureTable(localGeneralDB); err != nil {
+		logging.Fatal("Error creating drift history table", "error", err)
+	}
+
+	lastMissing := make(map[string]int)
+	for _, entity := range entities {
+		missing, ok, err := helper.LastMissing(localGeneralDB, entity.Name)
+		if err != nil {
+			logging.Fatal("Error reading drift history", "error", err)
+		}
+		if ok {
+			lastMissing[entity.Name] = missing
+		}
+	}
+
+	board := &helper.StatusBoard{}
+	if opts.Listen != "" && !opts.Once {
+		mux := http.NewServeMux()
+		mux.Handle("/status", board)
+		go func() {
+			if err := http.ListenAndServe(opts.Listen, mux); err != nil {
+				logging.Fatal("Error starting status endpoint", "error", err)
+			}
+		}()
+		fmt.Println(i18n.T("monitor.listening", opts.Listen))
+	}
+
+	for {
+		startTime := time.Now()
+		status := helper.Status{
+			UpdatedAt: startTime,
+			NextCheck: startTime.Add(opts.Interval),
+			Interval:  opts.Interval.String(),
+		}
+
+		fmt.Printf("\n%s\n", i18n.T("monitor.check", startTime.Format(time.RFC3339)))
+		for _, entity := range entities {
+			check := checkEntity(entity)
+
+			if check.Error == "" {
+				if previous, ok := lastMissing[entity.Name]; ok {
+					check.NewMissing = check.Missing - previous
+				}
+				lastMissing[entity.Name] = check.Missing
+				if check.Missing > 0 {
+					status.Drifting = true
+				}
+			}
+
+			if err := helper.SaveCheck(localGeneralDB, check); err != nil {
+				slog.Warn("Could not save drift check", "entity", check.Entity, "error", err)
+			}
+			status.Checks = append(status.Checks, check)
+			printCheck(check)
+		}
+
+		board.Set(status)
+		if opts.StatusFile != "" {
+			if err := helper.WriteStatusFile(opts.StatusFile, status); err != nil {
+				slog.Warn("Could not write status file", "path", opts.StatusFile, "error", err)
+			}
+		}
+
+		if opts.Once {
+			return
+		}
+
+		wait := time.Until(status.NextCheck)
+		fmt.Println(i18n.T("monitor.next", wait.Round(time.Second)))
+		time.Sleep(wait)
+	}
+}
+
+// checkEntity menjalankan rekonsiliasi satu entitas dengan snapshot sumber baru.
+// Database yang tidak bisa dihubungi dicatat sebagai error check, monitor tetap berjalan.
+func checkEntity(entity reconcileHelper.Entity) helper.Check {
+	check := helper.Check{CheckedAt: time.Now(), Entity: entity.Name}
+
+	result, err := compareEntity(entity)
+	check.DurationMs = time.Since(check.CheckedAt).Milliseconds()
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	check.SourceCount = result.SourceCount
+	check.TargetCount = result.TargetCount
+	check.Matched = result.Matched
+	check.Missing = len(result.Missing)
+	check.Extra = len(result.Extra)
+	return check
+}
+
+func compareEntity(entity reconcileHelper.Entity) (*reconcileHelper.Result, error) {
+	defer database.ReleaseSourceSnapshot()
+
+	sourceDB, err := entity.Source.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer sourceDB.Close()
+
+	targetDB, err := entity.Target.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer targetDB.Close()
+
+	return reconcileHelper.Compare(entity, sourceDB, targetDB)
+}
+
+func printCheck(check helper.Check) {
+	if check.Error != "" {
+		fmt.Printf("- %-14s %s\n", check.Entity, i18n.T("monitor.error", check.Error))
+		return
+	}
+
+	line := fmt.Sprintf("- %-14s %s", check.Entity, i18n.T("monitor.line", check.SourceCount, check.TargetCount, check.Missing, check.Extra))
+	if check.NewMissing > 0 {
+		line += " " + i18n.T("monitor.new_missing", check.NewMissing)
+	}
+	fmt.Println(line)
+}
@@ -0,0 +1,173 @@
+package helper
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"os"
+)
+
+// DefaultForeignKeys berisi semua referensi legacy yang dipakai service migrasi
+var DefaultForeignKeys = []ForeignKey{
+	{
+		Name: "td_travel.rda_id", Table: "td_travel", Key: "id", Column: "rda_id",
+		ParentTable: "tr_rda", ParentColumn: "id",
+		Owner: "c.name",
+	},
+	{
+		Name: "td_travel_agent.parent_id", Table: "td_travel_agent", Key: "user_id", Column: "parent_id",
+		ParentTable: "td_travel_agent", ParentColumn: "user_id",
+		Owner: "(SELECT u.name || ' <' || u.email || '>' FROM td_user u WHERE u.id = c.user_id)",
+	},
+	{
+		Name: "td_travel_agent.rda_id", Table: "td_travel_agent", Key: "user_id", Column: "rda_id",
+		ParentTable: "tr_rda", ParentColumn: "id",
+		Owner: "(SELECT u.name || ' <' || u.email || '>' FROM td_user u WHERE u.id = c.user_id)",
+	},
+	{
+		Name: "td_travel_agent.travel_id", Table: "td_travel_agent", Key: "user_id", Column: "travel_id",
+		ParentTable: "td_travel", ParentColumn: "id",
+		Owner: "(SELECT u.name || ' <' || u.email || '>' FROM td_user u WHERE u.id = c.user_id)",
+	},
+	{
+		Name: "td_travel_agent.city_id", Table: "td_travel_agent", Key: "user_id", Column: "city_id",
+		ParentTable: "td_city", ParentColumn: "id",
+		Owner: "(SELECT u.name || ' <' || u.email || '>' FROM td_user u WHERE u.id = c.user_id)",
+	},
+	{
+		Name: "td_travel_user.user_id", Table: "td_travel_user", Key: "id", Column: "user_id",
+		ParentTable: "td_user", ParentColumn: "id",
+		Owner: "(SELECT t.name FROM td_travel t WHERE t.id = c.travel_id) || ' (' || c.role || ')'",
+	},
+	{
+		Name: "td_travel_user.travel_id", Table: "td_travel_user", Key: "id", Column: "travel_id",
+		ParentTable: "td_travel", ParentColumn: "id",
+		Owner: "(SELECT u.name || ' <' || u.email || '>' FROM td_user u WHERE u.id = c.user_id)",
+	},
+	{
+		Name: "td_package.travel_id", Table: "td_package", Key: "id", Column: "travel_id",
+		ParentTable: "td_travel", ParentColumn: "id",
+		Owner: "c.name || ' (' || c.departure_date || ')'",
+		Where: "c.soft_delete = false",
+	},
+	{
+		Name: "td_package.departure_airline_id", Table: "td_package", Key: "id", Column: "departure_airline_id",
+		ParentTable: "td_airline", ParentColumn: "id",
+		Owner: "c.name || ' (' || c.departure_date || ')'",
+		Where: "c.soft_delete = false",
+	},
+	{
+		Name: "td_package.arrival_airline_id", Table: "td_package", Key: "id", Column: "arrival_airline_id",
+		ParentTable: "td_airline", ParentColumn: "id",
+		Owner: "c.name || ' (' || c.departure_date || ')'",
+		Where: "c.soft_delete = false",
+	},
+	{
+		Name: "td_package_hotel.hotel_id", Table: "td_package_hotel", Key: "id", Column: "hotel_id",
+		ParentTable: "td_hotel", ParentColumn: "id",
+		Owner: "(SELECT p.name FROM td_package p WHERE p.id = c.package_id)",
+	},
+}
+
+// LoadForeignKeys membaca daftar ForeignKey dari file JSON. Entri dengan nama yang
+// sama dengan bawaan menggantikan entri bawaan, sisanya ditambahkan.
+func LoadForeignKeys(path string) ([]ForeignKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var custom []ForeignKey
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	foreignKeys := append([]ForeignKey(nil), DefaultForeignKeys...)
+	for _, fk := range custom {
+		if fk.Name == "" || fk.Table == "" || fk.Key == "" || fk.Column == "" || fk.ParentTable == "" || fk.ParentColumn == "" {
+			return nil, fmt.Errorf("foreign key %q in %s needs name, table, key, column, parent_table and parent_column", fk.Name, path)
+		}
+		if fk.Owner == "" {
+			fk.Owner = "''"
+		}
+
+		replaced := false
+		for i := range foreignKeys {
+			if foreignKeys[i].Name == fk.Name {
+				foreignKeys[i] = fk
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+
+	return foreignKeys, nil
+}
+
+func filter(fk ForeignKey) string {
+	where := fmt.Sprintf("c.%s IS NOT NULL", pq.QuoteIdentifier(fk.Column))
+	if fk.Where != "" {
+		where += " AND (" + fk.Where + ")"
+	}
+	return where
+}
+
+func buildCountQuery(fk ForeignKey) string {
+	return fmt.Sprintf(`SELECT COUNT(*) FROM %s c WHERE %s`, pq.QuoteIdentifier(fk.Table), filter(fk))
+}
+
+func buildOrphanQuery(fk ForeignKey) string {
+	return fmt.Sprintf(`
+		SELECT c.%[1]s::text, c.%[2]s::text, (%[3]s)::text
+		FROM %[4]s c
+		WHERE %[5]s
+		AND NOT EXISTS (SELECT 1 FROM %[6]s p WHERE p.%[7]s = c.%[2]s)
+		ORDER BY c.%[2]s
+	`, pq.QuoteIdentifier(fk.Key), pq.QuoteIdentifier(fk.Column), fk.Owner,
+		pq.QuoteIdentifier(fk.Table), filter(fk),
+		pq.QuoteIdentifier(fk.ParentTable), pq.QuoteIdentifier(fk.ParentColumn))
+}
+
+// FindOrphans mencari baris yang referensinya tidak ada di tabel induk
+func FindOrphans(db *sql.DB, fk ForeignKey) Result {
+	result := Result{ForeignKey: fk}
+
+	if err := db.QueryRow(buildCountQuery(fk)).Scan(&result.Checked); err != nil {
+		result.Err = err
+		return result
+	}
+
+	rows, err := db.Query(buildOrphanQuery(fk))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer rows.Close()
+
+	missing := make(map[string]bool)
+	for rows.Next() {
+		var ownerID, value, owner sql.NullString
+		if err := rows.Scan(&ownerID, &value, &owner); err != nil {
+			result.Err = err
+			return result
+		}
+
+		missing[value.String] = true
+		result.Orphans = append(result.Orphans, Orphan{
+			Check:   fk.Name,
+			Table:   fk.Table,
+			OwnerID: ownerID.String,
+			Column:  fk.Column,
+			Missing: value.String,
+			Owner:   owner.String,
+		})
+	}
+	result.DistinctMissing = len(missing)
+	result.Err = rows.Err()
+
+	return result
+}
@@ -0,0 +1,41 @@
+package helper
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExportOrphans menulis semua orphan ke file CSV atau JSON sesuai ekstensi path
+func ExportOrphans(path string, results []Result) error {
+	orphans := []Orphan{}
+	for _, result := range results {
+		orphans = append(orphans, result.Orphans...)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(orphans)
+	case ".csv":
+		writer := csv.NewWriter(file)
+		writer.Write([]string{"check", "table", "owner_id", "column", "missing_id", "owner"})
+		for _, o := range orphans {
+			writer.Write([]string{o.Check, o.Table, o.OwnerID, o.Column, o.Missing, o.Owner})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported export format %q, use .csv or .json", filepath.Ext(path))
+	}
+}
@@ -0,0 +1,33 @@
+package helper
+
+// ForeignKey adalah satu referensi di database legacy yang diperiksa.
+// Owner adalah ekspresi SQL (alias tabel pemilik: c) yang menjelaskan baris pemilik.
+type ForeignKey struct {
+	Name         string `json:"name"`
+	Table        string `json:"table"`
+	Key          string `json:"key"`
+	Column       string `json:"column"`
+	ParentTable  string `json:"parent_table"`
+	ParentColumn string `json:"parent_column"`
+	Owner        string `json:"owner"`
+	Where        string `json:"where,omitempty"`
+}
+
+// Orphan adalah satu baris yang referensinya tidak ditemukan
+type Orphan struct {
+	Check   string `json:"check"`
+	Table   string `json:"table"`
+	OwnerID string `json:"owner_id"`
+	Column  string `json:"column"`
+	Missing string `json:"missing_id"`
+	Owner   string `json:"owner"`
+}
+
+// Result adalah hasil satu ForeignKey
+type Result struct {
+	ForeignKey      ForeignKey
+	Checked         int // baris dengan nilai referensi tidak NULL
+	Orphans         []Orphan
+	DistinctMissing int
+	Err             error
+}
@@ -0,0 +1,92 @@
+package orphan
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/orphan/helper"
+	"time"
+)
+
+// OrphanReportService memeriksa semua referensi legacy sebelum migrasi dan
+// mencetak orphan beserta record pemiliknya. configPath opsional untuk
+// menambah/mengganti daftar referensi, names membatasi referensi yang diperiksa.
+func OrphanReportService(names []string, configPath string, limit int, exportPath string) {
+	foreignKeys := helper.DefaultForeignKeys
+	if configPath != "" {
+		var err error
+		foreignKeys, err = helper.LoadForeignKeys(configPath)
+		if err != nil {
+			logging.Fatal("Error loading foreign key config", "error", err)
+		}
+	}
+
+	if len(names) > 0 {
+		var selected []helper.ForeignKey
+		for _, name := range names {
+			found := false
+			for _, fk := range foreignKeys {
+				if fk.Name == name {
+					selected = append(selected, fk)
+					found = true
+				}
+			}
+			if !found {
+				logging.Fatal("Unknown reference", "reference", name)
+			}
+		}
+		foreignKeys = selected
+	}
+
+	prodExistingUmrahDB := database.ConnectionProdExistingUmrahDB()
+	defer prodExistingUmrahDB.Close()
+
+	startTime := time.Now()
+	var results []helper.Result
+	for _, fk := range foreignKeys {
+		results = append(results, helper.FindOrphans(prodExistingUmrahDB, fk))
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("orphan.title"))
+	fmt.Printf("------------------------\n")
+
+	var totalOrphans, failedCount int
+	for _, result := range results {
+		fk := result.ForeignKey
+		fmt.Printf("\n%s -> %s.%s\n", fk.Name, fk.ParentTable, fk.ParentColumn)
+
+		if result.Err != nil {
+			failedCount++
+			fmt.Println(i18n.T("check.error", result.Err))
+			continue
+		}
+
+		fmt.Println(i18n.T("orphan.checked", result.Checked, len(result.Orphans), result.DistinctMissing))
+		totalOrphans += len(result.Orphans)
+
+		for i, orphan := range result.Orphans {
+			if limit > 0 && i >= limit {
+				fmt.Println(i18n.T("check.more", len(result.Orphans)-limit))
+				break
+			}
+			fmt.Println(i18n.T("orphan.item", i+1, fk.Key, orphan.OwnerID, orphan.Missing, orphan.Owner))
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("check.summary"))
+	fmt.Println(i18n.T("orphan.references", len(results)))
+	fmt.Println(i18n.T("orphan.rows", totalOrphans))
+	if failedCount > 0 {
+		fmt.Println(i18n.T("check.failed", failedCount))
+	}
+
+	if exportPath != "" {
+		if err := helper.ExportOrphans(exportPath, results); err != nil {
+			logging.Fatal("Error exporting orphans", "error", err)
+		}
+		fmt.Println(i18n.T("orphan.exported", exportPath))
+	}
+
+	fmt.Println(i18n.T("orphan.finished", time.Since(startTime).Round(time.Millisecond)))
+}
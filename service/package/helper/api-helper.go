@@ -2,13 +2,44 @@ package helper
 
 import (
 	"fmt"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/tracing"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
+// Percobaan request ke API identity, error jaringan dan status 5xx/429 diulang
+// dengan jeda yang berlipat
+const (
+	apiAttempts = 3
+	apiBackoff  = time.Second
+)
+
+// GetOrganizationInstance mengambil organization instance dari API identity,
+// dengan retry untuk error sementara
 func GetOrganizationInstance(organizationID string, organizationInstanceID int) ([]byte, error) {
-	// Buat HTTP client
-	client := &http.Client{}
+	var (
+		body      []byte
+		err       error
+		retryable bool
+	)
+	for attempt := 1; attempt <= apiAttempts; attempt++ {
+		body, retryable, err = getOrganizationInstance(organizationID, organizationInstanceID)
+		if err == nil || !retryable || attempt == apiAttempts {
+			break
+		}
+		metrics.Add("migration_retries_total", "Retried operations after a transient error.", 1, "operation", "organization_instance_api")
+		slog.Warn("Retrying organization instance request", "organization_instance_id", organizationInstanceID, "attempt", attempt, "error", err)
+		time.Sleep(apiBackoff << (attempt - 1))
+	}
+	return body, err
+}
+
+func getOrganizationInstance(organizationID string, organizationInstanceID int) ([]byte, bool, error) {
+	// Buat HTTP client, setiap request menjadi span saat tracing aktif
+	client := &http.Client{Transport: tracing.Transport(nil)}
 
 	// Buat URL dengan organization instance ID
 	url := fmt.Sprintf("https://dev.api.moslem101.com/identity/v1/organization-instance/%d", organizationInstanceID)
@@ -16,7 +47,7 @@ func GetOrganizationInstance(organizationID string, organizationInstanceID int)
 	// Buat request
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+		return nil, false, fmt.Errorf("error creating request: %v", err)
 	}
 
 	// Set headers
@@ -27,20 +58,21 @@ func GetOrganizationInstance(organizationID string, organizationInstanceID int)
 	// Kirim request
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending request: %v", err)
+		return nil, true, fmt.Errorf("error sending request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	// Baca response body
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
+		return nil, true, fmt.Errorf("error reading response: %v", err)
 	}
 
 	// Cek status code
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error response status: %d, body: %s", resp.StatusCode, string(body))
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, retryable, fmt.Errorf("error response status: %d, body: %s", resp.StatusCode, string(body))
 	}
 
-	return body, nil
+	return body, false, nil
 }
@@ -4,13 +4,20 @@ import (
 	"database/sql"
 )
 
+// MinDepartureDate adalah tanggal keberangkatan paling awal paket yang dimigrasi
+const MinDepartureDate = "2025-01-10"
+
+// ActivePackageFilter adalah kondisi WHERE td_package untuk paket yang dimigrasi.
+// Dipakai juga oleh reconcile, verify, checksum, inspect dan profile supaya
+// semuanya membandingkan paket yang sama.
+const ActivePackageFilter = "soft_delete = false AND departure_date >= '" + MinDepartureDate + "'"
+
 func TotalRows(prodExistingUmrahDB *sql.DB) (int, error) {
 	var totalRows int
 	err := prodExistingUmrahDB.QueryRow(`
         SELECT COUNT(*) 
         FROM td_package 
-       	WHERE soft_delete = false
-		AND departure_date >= '2025-01-10'`).Scan(&totalRows)
+        WHERE ` + ActivePackageFilter).Scan(&totalRows)
 
 	return totalRows, err
 }
@@ -92,9 +99,7 @@ func GetDataPackage(prodExistingUmrahDB *sql.DB) (*sql.Rows, error) {
                departure_date, arrival_date, price_double, price_triple,
                price_quad, closed
         FROM td_package 
-        WHERE soft_delete = false
-        AND departure_date >= '2025-01-10'
-    `)
+        WHERE ` + ActivePackageFilter)
 }
 
 func GetPackageItineraryStmt(prodExistingUmrahDB *sql.DB) (*sql.Stmt, error) {
@@ -5,13 +5,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/export"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/ledger"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 	"github.com/ApesJs/go-migration-app/service/package/helper"
-	"github.com/schollz/progressbar/v3"
-	"log"
+	"github.com/ApesJs/go-migration-app/tracing"
+	"log/slog"
 	"sort"
 	"time"
 )
 
+// missingOrgInstanceList adalah travel tanpa organization_instance yang paketnya diarahkan ke 9999
+var missingOrgInstanceList = export.List{Name: "missing-org-instances", Columns: []string{"travel_id", "travel_name"}}
+
 func PackageService() {
 	// Koneksi Database
 	prodExistingUmrahDB := database.ConnectionProdExistingUmrahDB()
@@ -32,86 +42,77 @@ func PackageService() {
 	// Menghitung total records yang akan ditransfer
 	totalRows, err := helper.TotalRows(prodExistingUmrahDB)
 	if err != nil {
-		log.Fatal("Error counting rows:", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Found %d total packages to transfer\n", totalRows)
+	fmt.Println(i18n.T("package.found", totalRows))
+
+	report.SetTotal(totalRows)
 
 	// Membuat progress bar
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/3][reset] Transferring packages..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	plan := progress.NewPlan("transfer", "standardize")
+	bar := plan.Start("transfer", totalRows)
 
 	// Statement untuk mengecek organization_instance_id
 	orgInstanceStmt, err := helper.OrgInsStmt(devIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing organization instance statement:", err)
+		logging.Fatal("Error preparing organization instance statement", "error", err)
 	}
 	defer orgInstanceStmt.Close()
 
 	// Statement untuk mendapatkan nama travel
 	travelStmt, err := helper.TravelStmt(prodExistingUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing travel statement:", err)
+		logging.Fatal("Error preparing travel statement", "error", err)
 	}
 	defer travelStmt.Close()
 
 	// Statement untuk mengecek hotel data
 	hotelStmt, err := helper.HotelStmt(prodExistingUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing hotel check statement:", err)
+		logging.Fatal("Error preparing hotel check statement", "error", err)
 	}
 	defer hotelStmt.Close()
 
 	// Statement untuk mengambil data airline
 	airlineStmt, err := helper.AirlineStmt(prodExistingUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing airline statement:", err)
+		logging.Fatal("Error preparing airline statement", "error", err)
 	}
 	defer airlineStmt.Close()
 
 	// Statement untuk insert ke tabel package
 	insertPackageStmt, err := helper.InsertPackageStmt(devUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing package insert statement:", err)
+		logging.Fatal("Error preparing package insert statement", "error", err)
 	}
 	defer insertPackageStmt.Close()
 
 	// Statement untuk insert ke tabel package_variant
 	insertVariantStmt, err := helper.InsertVariantStmt(devUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing variant insert statement:", err)
+		logging.Fatal("Error preparing variant insert statement", "error", err)
 	}
 	defer insertVariantStmt.Close()
 
 	// Query untuk mengambil data package
 	rows, err := helper.GetDataPackage(prodExistingUmrahDB)
 	if err != nil {
-		log.Fatal("Error querying source database:", err)
+		logging.Fatal("Error querying source database", "error", err)
 	}
 	defer rows.Close()
 
 	// Statement untuk mengambil data itinerary
 	itineraryStmt, err := helper.GetPackageItineraryStmt(prodExistingUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing itinerary statement:", err)
+		logging.Fatal("Error preparing itinerary statement", "error", err)
 	}
 	defer itineraryStmt.Close()
 
 	// Statement untuk insert itinerary
 	insertItineraryStmt, err := helper.InsertItineraryStmt(devUmrahDB)
 	if err != nil {
-		log.Fatal("Error preparing itinerary insert statement:", err)
+		logging.Fatal("Error preparing itinerary insert statement", "error", err)
 	}
 	defer insertItineraryStmt.Close()
 
@@ -122,12 +123,13 @@ func PackageService() {
 		variantCount        int
 		itineraryCount      int
 		missingOrgInstances []helper.MissingOrgInstance
+		lossy               ledger.Buffer // perubahan lossy, ditulis ke ledger setelah commit
 	)
 
 	// Begin transaction
 	tx, err := devUmrahDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Prepare statements dalam transaksi
@@ -177,11 +179,13 @@ func PackageService() {
 			&priceQuad, &closed,
 		)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			report.Errorf("Error scanning row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(id)
 
 		// Menghitung released_at sebelum insert ke package_variant
 		releasedAt := departureDate.AddDate(0, 0, -closed)
@@ -202,7 +206,7 @@ func PackageService() {
 				if err != nil {
 					travelName = "Unknown Travel Name"
 					if err != sql.ErrNoRows {
-						log.Printf("Error getting travel name for ID %s: %v", travelID, err)
+						report.Errorf("Error getting travel name for ID %s: %v", travelID, err)
 					}
 				}
 
@@ -210,10 +214,11 @@ func PackageService() {
 					TravelID:   travelID,
 					TravelName: travelName,
 				})
-				log.Printf("No organization_instance found for travel_id %s (%s), using default value 9999", travelID, travelName)
+				slog.Warn("No organization_instance found, using default value 9999", "travel_id", travelID, "travel_name", travelName)
 			} else {
-				log.Printf("Error querying organization_instance_id for travel_id %s: %v", travelID, err)
+				report.Errorf("Error querying organization_instance_id for travel_id %s: %v", travelID, err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
@@ -223,7 +228,7 @@ func PackageService() {
 		if organizationInstanceID != 9999 {
 			apiResponse, err := helper.GetOrganizationInstance(travelID, organizationInstanceID)
 			if err != nil {
-				log.Printf("Error getting organization instance data: %v", err)
+				report.Errorf("Error getting organization instance data: %v", err)
 				orgInstanceJSON = []byte(`{"status": "error fetching data"}`)
 			} else {
 				orgInstanceJSON = apiResponse
@@ -235,8 +240,9 @@ func PackageService() {
 		// Process hotel data
 		hotelRows, err := hotelStmt.Query(id)
 		if err != nil {
-			log.Printf("Error querying hotel data: %v", err)
+			report.Errorf("Error querying hotel data: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -261,7 +267,7 @@ func PackageService() {
 				&cityID, &cityName,
 			)
 			if err != nil {
-				log.Printf("Error scanning hotel row: %v", err)
+				report.Errorf("Error scanning hotel row: %v", err)
 				continue
 			}
 
@@ -297,16 +303,18 @@ func PackageService() {
 
 		medinaHotelJSON, err := json.Marshal(medinaHotel)
 		if err != nil {
-			log.Printf("Error marshaling medina hotel: %v", err)
+			report.Errorf("Error marshaling medina hotel: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		meccaHotelJSON, err := json.Marshal(meccaHotel)
 		if err != nil {
-			log.Printf("Error marshaling mecca hotel: %v", err)
+			report.Errorf("Error marshaling mecca hotel: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -328,8 +336,9 @@ func PackageService() {
 			&airlineUpdatedAt,
 		)
 		if err != nil && err != sql.ErrNoRows {
-			log.Printf("Error getting airline data: %v", err)
+			report.Errorf("Error getting airline data: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -337,8 +346,9 @@ func PackageService() {
 		// Create flight JSONs
 		departureFlight, err := helper.CreateDepartureJSON(airlineCode, airlineLogo, airlineName, airlineCreatedAt, airlineUpdatedAt, airlineStmt, arrivalAirlineID)
 		if err != nil && err != sql.ErrNoRows {
-			log.Printf("Error creating departure flight: %v", err)
+			report.Errorf("Error creating departure flight: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -347,16 +357,18 @@ func PackageService() {
 
 		departureJSON, err := json.Marshal(departureFlight)
 		if err != nil {
-			log.Printf("Error marshaling departure flight: %v", err)
+			report.Errorf("Error marshaling departure flight: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		arrivalJSON, err := json.Marshal(arrivalFlight)
 		if err != nil {
-			log.Printf("Error marshaling arrival flight: %v", err)
+			report.Errorf("Error marshaling arrival flight: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -373,6 +385,24 @@ func PackageService() {
 			changeDpType = "nominal"
 		}
 
+		// Harga dan nominal disimpan sebagai bilangan bulat, pecahannya dibuang.
+		// Event dicatat ke ledger hanya jika record ini tersimpan.
+		var recordLossy ledger.Buffer
+		for _, amount := range []struct {
+			entity, field string
+			value         float64
+		}{
+			{"package", "dp_amount", dpAmount},
+			{"package", "fee_amount", feeAmount},
+			{"package_variant", "price_double", priceDouble},
+			{"package_variant", "price_triple", priceTriple},
+			{"package_variant", "price_quad", priceQuad},
+		} {
+			if amount.value != float64(int64(amount.value)) {
+				recordLossy.Record(amount.entity, id, amount.field, fmt.Sprint(amount.value), fmt.Sprint(int64(amount.value)), ledger.ReasonTruncated)
+			}
+		}
+
 		// Insert package and get ID
 		var packageID int
 		err = txInsertPackageStmt.QueryRow(
@@ -405,8 +435,9 @@ func PackageService() {
 		).Scan(&packageID)
 
 		if err != nil {
-			log.Printf("Error inserting package: %v", err)
+			report.Errorf("Error inserting package: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -432,8 +463,9 @@ func PackageService() {
 		)
 
 		if err != nil {
-			log.Printf("Error inserting package variant: %v", err)
+			report.Errorf("Error inserting package variant: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -441,8 +473,9 @@ func PackageService() {
 		// Process itinerary data
 		itineraryRows, err := itineraryStmt.Query(id)
 		if err != nil {
-			log.Printf("Error querying itinerary data: %v", err)
+			report.Errorf("Error querying itinerary data: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -470,7 +503,7 @@ func PackageService() {
 
 			err := itineraryRows.Scan(&activityTime, &activity, &cityID, &createTime, &cityName)
 			if err != nil {
-				log.Printf("Error scanning itinerary row: %v", err)
+				report.Errorf("Error scanning itinerary row: %v", err)
 				continue
 			}
 
@@ -482,6 +515,9 @@ func PackageService() {
 		}
 		itineraryRows.Close()
 
+		// Grouping dicatat sebagai span sendiri untuk membedakannya dari query dan insert
+		_, groupSpan := tracing.Start(tracing.Context(), "itinerary grouping", "package.id", id, "itinerary.activities", len(activities))
+
 		// Sort activities berdasarkan waktu untuk satu hari
 		sort.Slice(activities, func(i, j int) bool {
 			return activities[i].Time.Before(activities[j].Time)
@@ -519,11 +555,15 @@ func PackageService() {
 			}
 		}
 
+		groupSpan.SetAttr("itinerary.days", len(agendaItems))
+		groupSpan.End()
+
 		// Convert to JSON
 		agendaJSON, err := json.Marshal(agendaItems)
 		if err != nil {
-			log.Printf("Error marshaling agenda: %v", err)
+			report.Errorf("Error marshaling agenda: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -538,29 +578,37 @@ func PackageService() {
 		)
 
 		if err != nil {
-			log.Printf("Error inserting itinerary: %v", err)
+			report.Errorf("Error inserting itinerary: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		transferredCount++
+		report.Add(report.OutcomeTransferred, 1)
+		lossy.Append(&recordLossy)
 		variantCount++
 		itineraryCount++
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
+	lossy.Flush()
+
+	report.AddPhase("transfer", time.Since(startTime), transferredCount+errorCount)
+	standardizeStart := time.Now()
 
 	// Update progress bar untuk standardisasi nama kota
 	bar.Finish()
-	fmt.Printf("\n[2/3] Standardizing city names...\n")
+	standardizeBar := plan.Start("standardize", 0)
 
 	// Standardisasi nama kota Mekah/Mekkah
 	updateMeccaResult, err := devUmrahDB.Exec(`
@@ -574,7 +622,7 @@ func PackageService() {
 		OR mecca_hotel->>'cityName' = 'Mekah'
 	`)
 	if err != nil {
-		log.Printf("Error updating Mecca city names: %v", err)
+		report.Errorf("Error updating Mecca city names: %v", err)
 	}
 
 	// Standardisasi nama kota Madinah
@@ -588,7 +636,7 @@ func PackageService() {
 		WHERE medina_hotel->>'cityName' = 'Madinah'
 	`)
 	if err != nil {
-		log.Printf("Error updating Madinah city names: %v", err)
+		report.Errorf("Error updating Madinah city names: %v", err)
 	}
 
 	// Penambahan https untuk url gambar yang tidak lengkap
@@ -618,31 +666,25 @@ func PackageService() {
 	packageThumbnailRowsAfected, _ := updatePackageThumbnailResult.RowsAffected()
 	variantThumbnailRowsAfected, _ := updateVariantThumbnailResult.RowsAffected()
 
-	fmt.Printf("\n[3/3] City name standardization completed!\n")
-	fmt.Printf("Standardized %d Mecca hotel records\n", meccaRowsAffected)
-	fmt.Printf("Standardized %d Madinah hotel records\n", madinahRowsAffected)
-	fmt.Printf("Change %d Package Thumbnail url\n", packageThumbnailRowsAfected)
-	fmt.Printf("Change %d Variant Thumbnail url\n", variantThumbnailRowsAfected)
-
-	duration := time.Since(startTime)
-
-	fmt.Printf("\nTransfer Summary:\n")
-	fmt.Printf("----------------\n")
-	fmt.Printf("Total records: %d\n", totalRows)
-	fmt.Printf("Successfully transferred packages: %d\n", transferredCount)
-	fmt.Printf("Successfully transferred variants: %d\n", variantCount)
-	fmt.Printf("Successfully transferred itineraries: %d\n", itineraryCount)
-	fmt.Printf("Failed transfers: %d\n", errorCount)
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f records/second\n", float64(transferredCount)/duration.Seconds())
-
-	// Tampilkan daftar travel_id yang tidak memiliki organization_instance
-	if len(missingOrgInstances) > 0 {
-		fmt.Printf("\nTravels without organization instance (using default value 9999):\n")
-		fmt.Printf("-------------------------------------------------------\n")
-		for i, travel := range missingOrgInstances {
-			fmt.Printf("%d. ID: %s\n   Name: %s\n", i+1, travel.TravelID, travel.TravelName)
-		}
-		fmt.Printf("\nTotal missing organization instances: %d\n", len(missingOrgInstances))
+	standardizeBar.Finish()
+	fmt.Printf("\n%s\n", i18n.T("standardize.completed"))
+	fmt.Println(i18n.T("standardize.mecca", meccaRowsAffected))
+	fmt.Println(i18n.T("standardize.madinah", madinahRowsAffected))
+	fmt.Println(i18n.T("package.thumbnails", packageThumbnailRowsAfected))
+	fmt.Println(i18n.T("package.variant_thumbnails", variantThumbnailRowsAfected))
+
+	report.AddPhase("standardize", time.Since(standardizeStart), int(meccaRowsAffected+madinahRowsAffected))
+
+	report.Inserted("package", transferredCount)
+	report.Inserted("package_variant", variantCount)
+	report.Inserted("package_itinerary", itineraryCount)
+	report.Updated("package", int(meccaRowsAffected+madinahRowsAffected+packageThumbnailRowsAfected))
+	report.Updated("package_variant", int(variantThumbnailRowsAfected))
+	report.Counter("standardized_mecca_hotels", int(meccaRowsAffected))
+	report.Counter("standardized_madinah_hotels", int(madinahRowsAffected))
+	for _, travel := range missingOrgInstances {
+		report.Placeholder(fmt.Sprintf("organization_instance 9999 for travel %s (%s)", travel.TravelID, travel.TravelName))
+		missingOrgInstanceList.Add(travel.TravelID, travel.TravelName)
 	}
+	report.Finish()
 }
@@ -0,0 +1,39 @@
+package helper
+
+import packageHelper "github.com/ApesJs/go-migration-app/service/package/helper"
+
+const (
+	phoneFormat = `^\+?[0-9]{8,15}$`
+	emailFormat = `^[^@\s]+@[^@\s]+\.[^@\s]+$`
+
+	activeUser    = "role = 'user' AND soft_delete = false"
+	activePackage = packageHelper.ActivePackageFilter
+)
+
+// Columns berisi kolom legacy yang dibaca setiap migrasi beserta batas kolom targetnya.
+// Batas panjang mengikuti pemotongan yang dilakukan service migrasi.
+var Columns = []Column{
+	{Migration: "user", Table: "td_user", Key: "id", Column: "email", Filter: activeUser, MaxLength: 255, Format: emailFormat},
+	{Migration: "user", Table: "td_user", Key: "id", Column: "name", Filter: activeUser, MaxLength: 255},
+
+	{Migration: "user-persona", Table: "td_user", Key: "id", Column: "phone", Filter: activeUser, MaxLength: 16, Format: phoneFormat},
+	{Migration: "user-persona", Table: "td_user", Key: "id", Column: "gender", Filter: activeUser, MaxLength: 16, Distribution: true},
+	{Migration: "user-persona", Table: "td_user", Key: "id", Column: "pob", Filter: activeUser, MaxLength: 10},
+	{Migration: "user-persona", Table: "td_user", Key: "id", Column: "job", Filter: activeUser},
+
+	{Migration: "wukala-persona", Table: "td_travel_agent", Key: "user_id", Column: "phone", MaxLength: 16, Format: phoneFormat},
+	{Migration: "wukala-persona", Table: "td_travel_agent", Key: "user_id", Column: "code", MaxLength: 8, Format: `^[A-Za-z0-9]+$`},
+	{Migration: "wukala-persona", Table: "td_travel_agent", Key: "user_id", Column: "fee_type", MaxLength: 12, Distribution: true},
+	{Migration: "wukala-persona", Table: "td_travel_agent", Key: "user_id", Column: "discount_type", MaxLength: 12, Distribution: true},
+	{Migration: "wukala-persona", Table: "td_travel_agent", Key: "user_id", Column: "nik", Format: `^[0-9]{16}$`},
+
+	{Migration: "organization", Table: "td_travel", Key: "id", Column: "slug", Format: `^[a-z0-9-]+$`},
+	{Migration: "organization-instance", Table: "td_travel", Key: "id", Column: "email", Filter: "rda_id IS NOT NULL", Format: emailFormat},
+	{Migration: "organization-instance", Table: "td_travel", Key: "id", Column: "phone", Filter: "rda_id IS NOT NULL", Format: phoneFormat},
+	{Migration: "organization-instance", Table: "td_travel", Key: "id", Column: "fee_type", Filter: "rda_id IS NOT NULL", Distribution: true},
+
+	{Migration: "package", Table: "td_package", Key: "id", Column: "dp_type", Filter: activePackage, Distribution: true},
+	{Migration: "package", Table: "td_package", Key: "id", Column: "fee_type", Filter: activePackage, Distribution: true},
+	{Migration: "package", Table: "td_package", Key: "id", Column: "currency", Filter: activePackage, Distribution: true},
+	{Migration: "package", Table: "td_package", Key: "id", Column: "slug", Filter: activePackage, Format: `^[a-z0-9-]+$`},
+}
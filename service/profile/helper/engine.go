@@ -0,0 +1,104 @@
+package helper
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+const topValues = 10
+
+// Run menjalankan profiling satu kolom. samples adalah jumlah contoh nilai
+// yang melebihi MaxLength yang diambil.
+func Run(db *sql.DB, column Column, samples int) Profile {
+	profile := Profile{Column: column}
+
+	col := "t." + pq.QuoteIdentifier(column.Column)
+	value := col + "::text"
+	from := fmt.Sprintf("%s t", pq.QuoteIdentifier(column.Table))
+	where := "true"
+	if column.Filter != "" {
+		where = column.Filter
+	}
+
+	tooLong := "0"
+	if column.MaxLength > 0 {
+		tooLong = fmt.Sprintf("COUNT(*) FILTER (WHERE length(%s) > %d)", value, column.MaxLength)
+	}
+	invalid := "0"
+	if column.Format != "" {
+		invalid = fmt.Sprintf("COUNT(*) FILTER (WHERE %s <> '' AND %s !~ %s)", value, value, pq.QuoteLiteral(column.Format))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE %[1]s IS NULL),
+			COUNT(*) FILTER (WHERE %[2]s = ''),
+			COUNT(DISTINCT %[1]s),
+			COALESCE(MAX(length(%[2]s)), 0),
+			%[3]s,
+			%[4]s
+		FROM %[5]s
+		WHERE %[6]s
+	`, col, value, tooLong, invalid, from, where)
+
+	if err := db.QueryRow(query).Scan(
+		&profile.Rows, &profile.Nulls, &profile.Empty, &profile.Distinct,
+		&profile.MaxLength, &profile.TooLong, &profile.Invalid,
+	); err != nil {
+		profile.Err = fmt.Errorf("error profiling %s.%s: %v", column.Table, column.Column, err)
+		return profile
+	}
+
+	if column.Distribution {
+		rows, err := db.Query(fmt.Sprintf(`
+			SELECT COALESCE(%s, '<null>'), COUNT(*)
+			FROM %s
+			WHERE %s
+			GROUP BY 1
+			ORDER BY 2 DESC, 1
+			LIMIT %d
+		`, value, from, where, topValues))
+		if err != nil {
+			profile.Err = fmt.Errorf("error reading values of %s.%s: %v", column.Table, column.Column, err)
+			return profile
+		}
+		for rows.Next() {
+			var v Value
+			if err := rows.Scan(&v.Value, &v.Count); err != nil {
+				rows.Close()
+				profile.Err = err
+				return profile
+			}
+			profile.Values = append(profile.Values, v)
+		}
+		rows.Close()
+	}
+
+	if column.MaxLength > 0 && profile.TooLong > 0 && samples > 0 {
+		rows, err := db.Query(fmt.Sprintf(`
+			SELECT t.%s::text, %s, length(%s)
+			FROM %s
+			WHERE (%s) AND length(%s) > %d
+			ORDER BY length(%s) DESC
+			LIMIT %d
+		`, pq.QuoteIdentifier(column.Key), value, value, from, where, value, column.MaxLength, value, samples))
+		if err != nil {
+			profile.Err = fmt.Errorf("error reading long values of %s.%s: %v", column.Table, column.Column, err)
+			return profile
+		}
+		for rows.Next() {
+			var s Sample
+			if err := rows.Scan(&s.Key, &s.Value, &s.Length); err != nil {
+				rows.Close()
+				profile.Err = err
+				return profile
+			}
+			profile.Samples = append(profile.Samples, s)
+		}
+		rows.Close()
+	}
+
+	return profile
+}
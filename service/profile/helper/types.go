@@ -0,0 +1,56 @@
+package helper
+
+// Column adalah satu kolom legacy yang dibaca migrasi.
+// MaxLength adalah panjang kolom target (0 jika tidak dibatasi), nilai yang lebih
+// panjang akan dipotong oleh migrasi. Format adalah regex Postgres untuk nilai valid.
+type Column struct {
+	Migration    string
+	Table        string
+	Key          string
+	Column       string
+	Filter       string
+	MaxLength    int
+	Format       string
+	Distribution bool // tampilkan sebaran nilai, untuk kolom enum seperti fee_type
+}
+
+// Value adalah satu nilai dan jumlah barisnya
+type Value struct {
+	Value string
+	Count int
+}
+
+// Sample adalah satu baris yang nilainya melebihi MaxLength
+type Sample struct {
+	Key    string
+	Value  string
+	Length int
+}
+
+// Profile adalah hasil profiling satu Column
+type Profile struct {
+	Column    Column
+	Rows      int
+	Nulls     int
+	Empty     int
+	Distinct  int
+	MaxLength int
+	TooLong   int
+	Invalid   int
+	Values    []Value  // hanya jika Column.Distribution
+	Samples   []Sample // contoh nilai yang melebihi MaxLength
+	Err       error
+}
+
+// Ratio menghitung persentase dari jumlah baris
+func (p Profile) Ratio(n int) float64 {
+	if p.Rows == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(p.Rows)
+}
+
+// Flagged true jika ada nilai yang akan dipotong atau formatnya tidak valid
+func (p Profile) Flagged() bool {
+	return p.TooLong > 0 || p.Invalid > 0
+}
@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/profile/helper"
+	"time"
+)
+
+// ProfileService memprofil kolom legacy yang dibaca migrasi sebelum migrasi dijalankan:
+// rasio NULL, jumlah nilai unik, panjang maksimum dibanding batas target, format
+// tidak valid dan sebaran nilai. names membatasi migrasi yang diprofil.
+// Mengembalikan false jika ada nilai yang akan dipotong atau formatnya tidak valid.
+func ProfileService(names []string, samples int) bool {
+	columns := helper.Columns
+	if len(names) > 0 {
+		columns = nil
+		for _, name := range names {
+			found := false
+			for _, column := range helper.Columns {
+				if column.Migration == name {
+					columns = append(columns, column)
+					found = true
+				}
+			}
+			if !found {
+				logging.Fatal("No profiled columns for migration", "migration", name)
+			}
+		}
+	}
+
+	prodExistingUmrahDB := database.ConnectionProdExistingUmrahDB()
+	defer prodExistingUmrahDB.Close()
+
+	startTime := time.Now()
+
+	fmt.Printf("\n%s\n", i18n.T("profile.title"))
+	fmt.Printf("------------------------\n")
+
+	var flagged, failed int
+	migration := ""
+	for _, column := range columns {
+		if column.Migration != migration {
+			migration = column.Migration
+			fmt.Printf("\n== %s ==\n", migration)
+		}
+
+		profile := helper.Run(prodExistingUmrahDB, column, samples)
+
+		fmt.Printf("\n%s.%s\n", column.Table, column.Column)
+		if profile.Err != nil {
+			failed++
+			fmt.Println(i18n.T("profile.error", profile.Err))
+			continue
+		}
+
+		fmt.Println(i18n.T("profile.rows", profile.Rows, profile.Nulls, profile.Ratio(profile.Nulls),
+			profile.Empty, profile.Ratio(profile.Empty), profile.Distinct))
+
+		if column.MaxLength > 0 {
+			mark := ""
+			if profile.TooLong > 0 {
+				mark = " " + i18n.T("profile.truncated", profile.TooLong)
+			}
+			fmt.Println(i18n.T("profile.max_length_limit", profile.MaxLength, column.MaxLength) + mark)
+		} else {
+			fmt.Println(i18n.T("profile.max_length", profile.MaxLength))
+		}
+		if column.Format != "" {
+			fmt.Println(i18n.T("profile.invalid", profile.Invalid, profile.Ratio(profile.Invalid), column.Format))
+		}
+
+		if profile.Flagged() {
+			flagged++
+		}
+
+		for _, sample := range profile.Samples {
+			fmt.Printf("  %s\n", i18n.T("profile.sample", column.Key, sample.Key, sample.Value, sample.Length))
+		}
+
+		if len(profile.Values) > 0 {
+			fmt.Println(i18n.T("profile.values"))
+			for _, v := range profile.Values {
+				fmt.Printf("  %-20s %8d (%.1f%%)\n", v.Value, v.Count, profile.Ratio(v.Count))
+			}
+			if profile.Distinct > len(profile.Values) {
+				fmt.Printf("  %s\n", i18n.T("profile.distinct_total", profile.Distinct))
+			}
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("check.summary"))
+	fmt.Println(i18n.T("profile.columns", len(columns)))
+	fmt.Println(i18n.T("profile.flagged", flagged))
+	if failed > 0 {
+		fmt.Println(i18n.T("profile.failed", failed))
+	}
+	fmt.Println(i18n.T("profile.finished", time.Since(startTime).Round(time.Millisecond)))
+
+	return flagged == 0 && failed == 0
+}
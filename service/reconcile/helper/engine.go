@@ -0,0 +1,81 @@
+package helper
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type keySet struct {
+	order      []string
+	details    map[string]string
+	duplicates int
+}
+
+// loadKeys membaca semua key dan detail dari satu sisi. Key NULL dianggap string kosong.
+func loadKeys(db *sql.DB, query string) (*keySet, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := &keySet{details: make(map[string]string)}
+	for rows.Next() {
+		var key, detail sql.NullString
+		if err := rows.Scan(&key, &detail); err != nil {
+			return nil, err
+		}
+
+		if _, exists := set.details[key.String]; exists {
+			set.duplicates++
+			continue
+		}
+		set.details[key.String] = detail.String
+		set.order = append(set.order, key.String)
+	}
+
+	return set, rows.Err()
+}
+
+// Compare mencocokkan key sumber dan target lalu mengelompokkan hasilnya
+// menjadi matched, missing (hanya di sumber) dan extra (hanya di target)
+func Compare(entity Entity, sourceDB, targetDB *sql.DB) (*Result, error) {
+	startTime := time.Now()
+
+	source, err := loadKeys(sourceDB, entity.Source.Query)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s source keys: %v", entity.Name, err)
+	}
+
+	target, err := loadKeys(targetDB, entity.Target.Query)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s target keys: %v", entity.Name, err)
+	}
+
+	result := &Result{
+		Entity:           entity.Name,
+		SourceCount:      len(source.order),
+		TargetCount:      len(target.order),
+		SourceDuplicates: source.duplicates,
+		TargetDuplicates: target.duplicates,
+	}
+
+	for _, key := range source.order {
+		if _, exists := target.details[key]; exists {
+			result.Matched++
+			continue
+		}
+		result.Missing = append(result.Missing, Record{Key: key, Detail: source.details[key]})
+	}
+
+	for _, key := range target.order {
+		if _, exists := source.details[key]; !exists {
+			result.Extra = append(result.Extra, Record{Key: key, Detail: target.details[key]})
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+
+	return result, nil
+}
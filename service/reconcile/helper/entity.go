@@ -0,0 +1,152 @@
+package helper
+
+import (
+	"github.com/ApesJs/go-migration-app/database"
+	packageHelper "github.com/ApesJs/go-migration-app/service/package/helper"
+)
+
+// sourceSide membuat sisi sumber, semua entitas dibaca dari prod existing umrah
+func sourceSide(query string) Side {
+	return Side{Label: "prod existing umrah", Connect: database.OpenProdExistingUmrahDB, Query: query}
+}
+
+// Entities berisi semua entitas yang bisa direkonsiliasi. Database target
+// mengikuti database yang dipakai service migrasinya masing-masing.
+var Entities = []Entity{
+	{
+		Name:        "user",
+		Description: "td_user (role user) vs user (role user/wukala)",
+		Key:         "id",
+		Source: sourceSide(`
+			SELECT id::text, name || ' <' || email || '>'
+			FROM td_user
+			WHERE role = 'user' AND soft_delete = false
+		`),
+		Target: Side{
+			Label:   "local identity",
+			Connect: database.OpenLocalIdentityDB,
+			Query: `
+				SELECT id::text, name || ' <' || email || '>'
+				FROM "user"
+				WHERE role IN ('user', 'wukala')
+			`,
+		},
+	},
+	{
+		Name:        "wukala",
+		Description: "td_travel_agent.user_id vs user (role wukala)",
+		Key:         "user id",
+		Source: sourceSide(`
+			SELECT t.user_id::text, u.name || ' <' || u.email || '>'
+			FROM td_travel_agent t
+			LEFT JOIN td_user u ON u.id = t.user_id
+		`),
+		Target: Side{
+			Label:   "local identity",
+			Connect: database.OpenLocalIdentityDB,
+			Query: `
+				SELECT id::text, name || ' <' || email || '>'
+				FROM "user"
+				WHERE role = 'wukala'
+			`,
+		},
+	},
+	{
+		Name:        "bdm",
+		Description: "tr_rda vs user (role bdm)",
+		Key:         "id",
+		Source: sourceSide(`
+			SELECT id::text, name || ' <' || email || '>'
+			FROM tr_rda
+		`),
+		Target: Side{
+			Label:   "prod identity",
+			Connect: database.OpenProdIdentityDB,
+			Query: `
+				SELECT id::text, name || ' <' || email || '>'
+				FROM "user"
+				WHERE role = 'bdm'
+			`,
+		},
+	},
+	{
+		Name:        "organization",
+		Description: "td_travel vs organization",
+		Key:         "id",
+		Source: sourceSide(`
+			SELECT id::text, name
+			FROM td_travel
+		`),
+		Target: Side{
+			Label:   "local identity",
+			Connect: database.OpenLocalIdentityDB,
+			Query: `
+				SELECT id::text, name
+				FROM organization
+			`,
+		},
+	},
+	{
+		Name:        "organization-user",
+		Description: "td_travel_user vs organization_user",
+		Key:         "travel id/user id",
+		Source: sourceSide(`
+			SELECT travel_id::text || '/' || user_id::text, role
+			FROM td_travel_user
+		`),
+		Target: Side{
+			Label:   "local identity",
+			Connect: database.OpenLocalIdentityDB,
+			Query: `
+				SELECT organization_id::text || '/' || user_id::text, role
+				FROM organization_user
+			`,
+		},
+	},
+	{
+		Name:        "package",
+		Description: "td_package (active, departing from " + packageHelper.MinDepartureDate + ") vs migrated package",
+		Key:         "slug",
+		Source: sourceSide(`
+			SELECT slug, name || ' (' || departure_date || ')'
+			FROM td_package
+			WHERE ` + packageHelper.ActivePackageFilter),
+		Target: Side{
+			Label:   "dev umrah",
+			Connect: database.OpenDevUmrahDB,
+			Query: `
+				SELECT slug, title
+				FROM package
+				WHERE created_by = 'migration'
+			`,
+		},
+	},
+	{
+		Name:        "hotel",
+		Description: "td_hotel vs hotel",
+		Key:         "name",
+		Source: sourceSide(`
+			SELECT name, address
+			FROM td_hotel
+			WHERE soft_delete = false
+		`),
+		Target: Side{
+			Label:   "dev general",
+			Connect: database.OpenDevGeneralDB,
+			Query: `
+				SELECT name, address
+				FROM hotel
+			`,
+		},
+	},
+}
+
+// FindEntity mencari definisi entitas berdasarkan nama
+func FindEntity(name string) (Entity, bool) {
+	for _, entity := range Entities {
+		if entity.Name == name {
+			return entity, true
+		}
+	}
+	return Entity{}, false
+}
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Side adalah salah satu sisi perbandingan (sumber atau target).
+// Query harus mengembalikan dua kolom: key dan detail yang ditampilkan di laporan.
+type Side struct {
+	Label   string
+	Connect func() (*sql.DB, error)
+	Query   string
+}
+
+// Entity mendefinisikan cara mencocokkan satu entitas antara sumber dan target
+type Entity struct {
+	Name        string
+	Description string
+	Key         string // penjelasan key yang dipakai untuk mencocokkan
+	Source      Side
+	Target      Side
+}
+
+// Record adalah satu baris hasil rekonsiliasi
+type Record struct {
+	Key    string
+	Detail string
+}
+
+// Result adalah hasil rekonsiliasi satu entitas
+type Result struct {
+	Entity           string
+	SourceCount      int
+	TargetCount      int
+	Matched          int
+	Missing          []Record // ada di sumber tapi tidak ada di target
+	Extra            []Record // ada di target tapi tidak ada di sumber
+	SourceDuplicates int      // key yang muncul lebih dari sekali di sumber
+	TargetDuplicates int
+	Duration         time.Duration
+}
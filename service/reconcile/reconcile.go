@@ -0,0 +1,131 @@
+package reconcile
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/service/reconcile/helper"
+	"time"
+)
+
+// ReconcileService membandingkan key sumber dan target untuk entitas yang dipilih.
+// limit membatasi jumlah detail missing/extra yang dicetak, 0 = cetak semua.
+// Jika runID diisi, hasilnya juga disimpan ke reconcile.json di folder run tersebut.
+func ReconcileService(names []string, limit int, runID string) {
+	var entities []helper.Entity
+	for _, name := range names {
+		if name == "all" {
+			entities = helper.Entities
+			break
+		}
+		entity, ok := helper.FindEntity(name)
+		if !ok {
+			logging.Fatal("Unknown entity", "entity", name)
+		}
+		entities = append(entities, entity)
+	}
+
+	startTime := time.Now()
+	saved := &report.ReconcileRun{RunID: runID, CheckedAt: startTime}
+	for _, entity := range entities {
+		result := reconcileEntity(entity)
+		printResult(entity, result, limit)
+		saved.Entities = append(saved.Entities, toReport(entity, result))
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("reconcile.finished", time.Since(startTime).Round(time.Millisecond)))
+
+	if runID != "" {
+		path, err := report.WriteReconciliation(saved)
+		if err != nil {
+			logging.Fatal("Error writing reconciliation report", "error", err)
+		}
+		fmt.Println(i18n.T("reconcile.saved", path))
+	}
+}
+
+func toReport(entity helper.Entity, result *helper.Result) report.Reconciliation {
+	return report.Reconciliation{
+		Entity:           entity.Name,
+		Description:      entity.Description,
+		Key:              entity.Key,
+		Source:           entity.Source.Label,
+		Target:           entity.Target.Label,
+		SourceCount:      result.SourceCount,
+		TargetCount:      result.TargetCount,
+		Matched:          result.Matched,
+		Missing:          len(result.Missing),
+		Extra:            len(result.Extra),
+		SourceDuplicates: result.SourceDuplicates,
+		TargetDuplicates: result.TargetDuplicates,
+		DurationMs:       result.Duration.Milliseconds(),
+		MissingRecords:   reportRecords(result.Missing),
+		ExtraRecords:     reportRecords(result.Extra),
+	}
+}
+
+func reportRecords(records []helper.Record) []report.ReconcileRecord {
+	var out []report.ReconcileRecord
+	for _, record := range records {
+		out = append(out, report.ReconcileRecord{Key: record.Key, Detail: record.Detail})
+	}
+	return report.ReconcileRecords(out)
+}
+
+func reconcileEntity(entity helper.Entity) *helper.Result {
+	fmt.Printf("\n%s\n", i18n.T("reconcile.running", entity.Name, entity.Description))
+
+	sourceDB, err := entity.Source.Connect()
+	if err != nil {
+		logging.Fatal("Database connection failed", "error", err)
+	}
+	defer sourceDB.Close()
+
+	targetDB, err := entity.Target.Connect()
+	if err != nil {
+		logging.Fatal("Database connection failed", "error", err)
+	}
+	defer targetDB.Close()
+
+	result, err := helper.Compare(entity, sourceDB, targetDB)
+	if err != nil {
+		logging.Fatal("Error reconciling", "error", err)
+	}
+
+	return result
+}
+
+func printResult(entity helper.Entity, result *helper.Result, limit int) {
+	fmt.Printf("\n%s\n", i18n.T("reconcile.result", entity.Name))
+	fmt.Printf("------------------------\n")
+	fmt.Println(i18n.T("reconcile.key", entity.Key))
+	fmt.Println(i18n.T("reconcile.source", entity.Source.Label, result.SourceCount))
+	fmt.Println(i18n.T("reconcile.target", entity.Target.Label, result.TargetCount))
+	fmt.Println(i18n.T("reconcile.matched", result.Matched))
+	fmt.Println(i18n.T("reconcile.missing", len(result.Missing)))
+	fmt.Println(i18n.T("reconcile.extra", len(result.Extra)))
+	if result.SourceDuplicates > 0 || result.TargetDuplicates > 0 {
+		fmt.Println(i18n.T("reconcile.duplicates", result.SourceDuplicates, result.TargetDuplicates))
+	}
+
+	printRecords(i18n.T("reconcile.missing_title"), result.Missing, limit)
+	printRecords(i18n.T("reconcile.extra_title"), result.Extra, limit)
+
+	fmt.Printf("\n%s\n", i18n.T("reconcile.completed", result.Duration.Round(time.Millisecond)))
+}
+
+func printRecords(title string, records []helper.Record, limit int) {
+	if len(records) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", title)
+	for i, record := range records {
+		if limit > 0 && i >= limit {
+			fmt.Println(i18n.T("check.more_limit", len(records)-limit))
+			break
+		}
+		fmt.Printf("%d. %s (%s)\n", i+1, record.Key, record.Detail)
+	}
+}
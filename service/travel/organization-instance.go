@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/service/orphan"
+	"log/slog"
 	"time"
 )
 
@@ -16,73 +21,10 @@ type LegalInfo struct {
 	PPIU string `json:"ppiu,omitempty"`
 }
 
-// Tambahkan fungsi ini di package yang sama
+// ListMissingRdaIds mencetak rda_id di td_travel yang tidak ada di tr_rda.
+// Sekarang bagian dari laporan orphan sumber, sama dengan `orphans td_travel.rda_id`.
 func ListMissingRdaIds() {
-	// Koneksi database
-	prodExistingUmrahDB := database.ConnectionProdExistingUmrahDB()
-	defer prodExistingUmrahDB.Close()
-
-	localIdentityDB := database.ConnectionLocalIdentityDB()
-	defer localIdentityDB.Close()
-
-	// Ambil semua rda_id dari td_travel
-	rows, err := prodExistingUmrahDB.Query(`
-        SELECT DISTINCT rda_id
-        FROM td_travel
-        WHERE rda_id IS NOT NULL
-    `)
-	if err != nil {
-		log.Fatal("Error saat query rda_id:", err)
-	}
-	defer rows.Close()
-
-	// Prepare statement untuk cek user
-	checkUserStmt, err := localIdentityDB.Prepare(`
-        SELECT COUNT(*) 
-        FROM "user" 
-        WHERE id = $1
-    `)
-	if err != nil {
-		log.Fatal("Error saat prepare statement:", err)
-	}
-	defer checkUserStmt.Close()
-
-	fmt.Println("\nDaftar RDA ID yang tidak ditemukan di tabel user:")
-	fmt.Println("-----------------------------------------------")
-
-	var (
-		rdaID        string
-		missingCount int
-		totalCount   int
-	)
-
-	for rows.Next() {
-		err := rows.Scan(&rdaID)
-		if err != nil {
-			log.Printf("Error saat scan rda_id: %v", err)
-			continue
-		}
-
-		totalCount++
-
-		// Cek apakah ada di tabel user
-		var count int
-		err = checkUserStmt.QueryRow(rdaID).Scan(&count)
-		if err != nil {
-			log.Printf("Error saat cek user: %v", err)
-			continue
-		}
-
-		if count == 0 {
-			missingCount++
-			fmt.Printf("%d. %s\n", missingCount, rdaID)
-		}
-	}
-
-	fmt.Printf("\nRingkasan:\n")
-	fmt.Printf("Total RDA ID yang dicek: %d\n", totalCount)
-	fmt.Printf("RDA ID yang tidak ditemukan: %d\n", missingCount)
-	fmt.Printf("Persentase tidak ditemukan: %.2f%%\n", float64(missingCount)/float64(totalCount)*100)
+	orphan.OrphanReportService([]string{"td_travel.rda_id"}, "", 0, "")
 }
 
 func OrganizationInstanceService() {
@@ -97,25 +39,15 @@ func OrganizationInstanceService() {
 	var totalRows int
 	err := prodExistingUmrahDB.QueryRow("SELECT COUNT(*) FROM td_travel WHERE rda_id IS NOT NULL").Scan(&totalRows)
 	if err != nil {
-		log.Fatal("Error counting rows:", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Found %d total records to transfer\n", totalRows)
+	fmt.Println(i18n.T("transfer.found", totalRows))
+
+	report.SetTotal(totalRows)
 
 	// Membuat progress bar
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/2][reset] Transferring data..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	bar := progress.Single("organization instances", totalRows)
 
 	// Mengambil data dari database sumber
 	rows, err := prodExistingUmrahDB.Query(`
@@ -130,21 +62,21 @@ func OrganizationInstanceService() {
 		WHERE rda_id IS NOT NULL
 	`)
 	if err != nil {
-		log.Fatal("Error querying source database:", err)
+		logging.Fatal("Error querying source database", "error", err)
 	}
 	defer rows.Close()
 
 	// Prepare statement untuk mengecek duplikasi (berdasarkan email karena unique)
 	checkStmt, err := localIdentityDB.Prepare(`SELECT COUNT(*) FROM organization_instance WHERE email = $1`)
 	if err != nil {
-		log.Fatal("Error preparing check statement:", err)
+		logging.Fatal("Error preparing check statement", "error", err)
 	}
 	defer checkStmt.Close()
 
 	// Prepare statement untuk mengecek keberadaan organization
 	checkOrgStmt, err := localIdentityDB.Prepare(`SELECT COUNT(*) FROM organization WHERE id = $1`)
 	if err != nil {
-		log.Fatal("Error preparing check organization statement:", err)
+		logging.Fatal("Error preparing check organization statement", "error", err)
 	}
 	defer checkOrgStmt.Close()
 
@@ -174,7 +106,7 @@ func OrganizationInstanceService() {
 		)
 	`)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
@@ -184,13 +116,12 @@ func OrganizationInstanceService() {
 		errorCount       int
 		skipCount        int
 		duplicateItems   []string
-		generatedSlugs   []string
 	)
 
 	// Begin transaction
 	tx, err := localIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Prepare statements dalam transaksi
@@ -198,8 +129,6 @@ func OrganizationInstanceService() {
 	txCheckOrgStmt := tx.Stmt(checkOrgStmt)
 	txInsertStmt := tx.Stmt(insertStmt)
 
-	startTime := time.Now()
-
 	// Memproses setiap baris data
 	var (
 		id              string
@@ -242,19 +171,22 @@ func OrganizationInstanceService() {
 			&cityID, &description,
 		)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			report.Errorf("Error scanning row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(id)
 
 		// Cek duplikasi berdasarkan email
 		if email.Valid && email.String != "" {
 			var count int
 			err = txCheckStmt.QueryRow(email.String).Scan(&count)
 			if err != nil {
-				log.Printf("Error checking for duplicates: %v", err)
+				report.Errorf("Error checking for duplicates: %v", err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
@@ -262,6 +194,7 @@ func OrganizationInstanceService() {
 			if count > 0 {
 				duplicateItems = append(duplicateItems, fmt.Sprintf("%s (%s)", name, email.String))
 				skipCount++
+				report.Add(report.OutcomeSkipped, 1)
 				bar.Add(1)
 				continue
 			}
@@ -269,7 +202,8 @@ func OrganizationInstanceService() {
 			// Jika email NULL atau empty string, generate unique identifier
 			timestamp := time.Now().UnixNano()
 			email.String = fmt.Sprintf("no-email-%s-%d@placeholder.com", id, timestamp)
-			log.Printf("Generated placeholder email for organization %s: %s", name, email.String)
+			slog.Warn("Generated placeholder email", "organization", name, "email", email.String)
+			report.Placeholder(fmt.Sprintf("email for %s (%s): %s", name, id, email.String))
 		}
 
 		// Cek keberadaan organization_id
@@ -278,14 +212,15 @@ func OrganizationInstanceService() {
 			var count int
 			err = txCheckOrgStmt.QueryRow(id).Scan(&count)
 			if err != nil {
-				log.Printf("Error checking organization: %v", err)
+				report.Errorf("Error checking organization: %v", err)
 				organizationID = nil
 			} else if count > 0 {
 				organizationID = id
 			} else {
 				// ID tidak ditemukan di tabel organizations
 				organizationID = "d0ac7aad-54ac-41f1-ba1a-a9070c3f464c"
-				log.Printf("Organization ID %s not found in organizations table, setting to NULL", id)
+				slog.Warn("Organization not found in organizations table, using fallback organization", "organization_id", id)
+				report.Placeholder(fmt.Sprintf("organization for %s (%s): fallback organization %s", name, id, organizationID))
 			}
 		} else {
 			organizationID = nil
@@ -301,7 +236,8 @@ func OrganizationInstanceService() {
 
 		// Generate slug dari nama
 		slug := createSlug(name)
-		generatedSlugs = append(generatedSlugs, fmt.Sprintf("%s -> %s", name, slug))
+		report.Item("Generated slugs", fmt.Sprintf("%s -> %s", name, slug))
+		generatedSlugList.Add("organization_instance", id, name, slug)
 
 		// Buat legal information JSON
 		legalInfo := LegalInfo{
@@ -312,8 +248,9 @@ func OrganizationInstanceService() {
 		// Marshal ke JSON
 		legalInfoJSON, err := json.Marshal(legalInfo)
 		if err != nil {
-			log.Printf("Error marshaling legal information: %v", err)
+			report.Errorf("Error marshaling legal information: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -354,53 +291,34 @@ func OrganizationInstanceService() {
 		)
 
 		if err != nil {
-			log.Printf("Error inserting row: %v", err)
+			report.Errorf("Error inserting row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		transferredCount++
+		report.Add(report.OutcomeTransferred, 1)
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
-	duration := time.Since(startTime)
-
 	// Update progress bar description for completion
 	bar.Finish()
-	fmt.Printf("\n[2/2] Transfer completed!\n")
-	fmt.Printf("\nTransfer Summary:\n")
-	fmt.Printf("----------------\n")
-	fmt.Printf("Total records: %d\n", totalRows)
-	fmt.Printf("Successfully transferred: %d\n", transferredCount)
-	fmt.Printf("Skipped (duplicates): %d\n", skipCount)
-	fmt.Printf("Failed transfers: %d\n", errorCount)
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f records/second\n", float64(transferredCount)/duration.Seconds())
+	fmt.Printf("\n%s\n", i18n.T("transfer.completed"))
 
-	// Menampilkan list item duplikat
-	if len(duplicateItems) > 0 {
-		fmt.Printf("\nDuplicate Items:\n")
-		fmt.Printf("---------------\n")
-		for i, item := range duplicateItems {
-			fmt.Printf("%d. %s\n", i+1, item)
-		}
-	}
-
-	// Menampilkan list item yang slugnya di-generate
-	if len(generatedSlugs) > 0 {
-		fmt.Printf("\nGenerated Slugs:\n")
-		fmt.Printf("---------------\n")
-		for i, item := range generatedSlugs {
-			fmt.Printf("%d. %s\n", i+1, item)
-		}
+	report.Inserted("organization_instance", transferredCount)
+	for _, item := range duplicateItems {
+		report.Duplicate(item)
 	}
+	report.Finish()
 }
@@ -4,13 +4,20 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
+	"github.com/ApesJs/go-migration-app/export"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 	"regexp"
 	"strings"
 	"time"
 )
 
+// generatedSlugList adalah slug yang dibuat migrasi karena tidak ada di sumber
+var generatedSlugList = export.List{Name: "generated-slugs", Columns: []string{"table", "id", "name", "slug"}}
+
 // createSlug membuat slug dari string yang diberikan
 func createSlug(name string) string {
 	// Mengkonversi ke lowercase
@@ -41,25 +48,15 @@ func OrganizationService() {
 	var totalRows int
 	err := prodExistingUmrahDB.QueryRow("SELECT COUNT(*) FROM td_travel").Scan(&totalRows)
 	if err != nil {
-		log.Fatal("Error counting rows:", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Found %d total records to transfer\n", totalRows)
+	fmt.Println(i18n.T("transfer.found", totalRows))
+
+	report.SetTotal(totalRows)
 
 	// Membuat progress bar
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/2][reset] Transferring data..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	bar := progress.Single("organizations", totalRows)
 
 	// Mengambil data dari database sumber
 	rows, err := prodExistingUmrahDB.Query(`
@@ -69,14 +66,14 @@ func OrganizationService() {
 		FROM td_travel
 	`)
 	if err != nil {
-		log.Fatal("Error querying source database:", err)
+		logging.Fatal("Error querying source database", "error", err)
 	}
 	defer rows.Close()
 
 	// Prepare statement untuk mengecek duplikasi (hanya berdasarkan id)
 	checkStmt, err := localIdentityDB.Prepare(`SELECT COUNT(*) FROM organization WHERE id = $1`)
 	if err != nil {
-		log.Fatal("Error preparing check statement:", err)
+		logging.Fatal("Error preparing check statement", "error", err)
 	}
 	defer checkStmt.Close()
 
@@ -93,7 +90,7 @@ func OrganizationService() {
 		)
 	`)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
@@ -103,21 +100,18 @@ func OrganizationService() {
 		errorCount       int
 		skipCount        int
 		duplicateItems   []string // Slice untuk menyimpan item yang duplikat
-		generatedSlugs   []string // Slice untuk menyimpan item yang slugnya di-generate
 	)
 
 	// Begin transaction
 	tx, err := localIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Prepare statements dalam transaksi
 	txCheckStmt := tx.Stmt(checkStmt)
 	txInsertStmt := tx.Stmt(insertStmt)
 
-	startTime := time.Now()
-
 	// Memproses setiap baris data
 	var (
 		id         string
@@ -134,18 +128,21 @@ func OrganizationService() {
 		// Scan data dari source database
 		err := rows.Scan(&id, &name, &slug, &desc, &isActive, &softDelete, &createdAt, &updatedAt)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			report.Errorf("Error scanning row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(id)
 
 		// Cek apakah id sudah ada di database target
 		var count int
 		err = txCheckStmt.QueryRow(id).Scan(&count)
 		if err != nil {
-			log.Printf("Error checking for duplicates: %v", err)
+			report.Errorf("Error checking for duplicates: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -154,6 +151,7 @@ func OrganizationService() {
 		if count > 0 {
 			duplicateItems = append(duplicateItems, fmt.Sprintf("%s (%s)", name, id))
 			skipCount++
+			report.Add(report.OutcomeSkipped, 1)
 			bar.Add(1)
 			continue
 		}
@@ -162,7 +160,8 @@ func OrganizationService() {
 		finalSlug := slug.String
 		if !slug.Valid || finalSlug == "" {
 			finalSlug = createSlug(name)
-			generatedSlugs = append(generatedSlugs, fmt.Sprintf("%s (%s) -> %s", name, id, finalSlug))
+			report.Item("Generated slugs", fmt.Sprintf("%s (%s) -> %s", name, id, finalSlug))
+			generatedSlugList.Add("organization", id, name, finalSlug)
 		}
 
 		// Insert ke target database
@@ -180,53 +179,34 @@ func OrganizationService() {
 			nil,         // modified_by
 		)
 		if err != nil {
-			log.Printf("Error inserting row: %v", err)
+			report.Errorf("Error inserting row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		transferredCount++
+		report.Add(report.OutcomeTransferred, 1)
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
-	duration := time.Since(startTime)
-
 	// Update progress bar description for completion
 	bar.Finish()
-	fmt.Printf("\n[2/2] Transfer completed!\n")
-	fmt.Printf("\nTransfer Summary:\n")
-	fmt.Printf("----------------\n")
-	fmt.Printf("Total records: %d\n", totalRows)
-	fmt.Printf("Successfully transferred: %d\n", transferredCount)
-	fmt.Printf("Skipped (duplicates): %d\n", skipCount)
-	fmt.Printf("Failed transfers: %d\n", errorCount)
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f records/second\n", float64(transferredCount)/duration.Seconds())
-
-	// Menampilkan list item duplikat
-	if len(duplicateItems) > 0 {
-		fmt.Printf("\nDuplicate Items:\n")
-		fmt.Printf("---------------\n")
-		for i, item := range duplicateItems {
-			fmt.Printf("%d. %s\n", i+1, item)
-		}
-	}
+	fmt.Printf("\n%s\n", i18n.T("transfer.completed"))
 
-	// Menampilkan list item yang slugnya di-generate
-	if len(generatedSlugs) > 0 {
-		fmt.Printf("\nGenerated Slugs:\n")
-		fmt.Printf("---------------\n")
-		for i, item := range generatedSlugs {
-			fmt.Printf("%d. %s\n", i+1, item)
-		}
+	report.Inserted("organization", transferredCount)
+	for _, item := range duplicateItems {
+		report.Duplicate(item)
 	}
+	report.Finish()
 }
@@ -3,9 +3,12 @@ package travel
 import (
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
-	"time"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
+	"log/slog"
 )
 
 func OrganizationUserService() {
@@ -23,25 +26,15 @@ func OrganizationUserService() {
 	var totalRows int
 	err := prodExistingUmrahDB.QueryRow("SELECT COUNT(*) FROM td_travel_user").Scan(&totalRows)
 	if err != nil {
-		log.Fatal("Error counting rows:", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Found %d total records to transfer\n", totalRows)
+	fmt.Println(i18n.T("transfer.found", totalRows))
+
+	report.SetTotal(totalRows)
 
 	// Membuat progress bar
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/2][reset] Transferring data..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	bar := progress.Single("organization users", totalRows)
 
 	// Mengambil data dari database sumber
 	rows, err := prodExistingUmrahDB.Query(`
@@ -50,21 +43,21 @@ func OrganizationUserService() {
 		FROM td_travel_user
 	`)
 	if err != nil {
-		log.Fatal("Error querying source database:", err)
+		logging.Fatal("Error querying source database", "error", err)
 	}
 	defer rows.Close()
 
 	// Prepare statement untuk mengecek keberadaan organization
 	checkOrgStmt, err := LocalIdentityDB.Prepare(`SELECT COUNT(*) FROM organization WHERE id = $1`)
 	if err != nil {
-		log.Fatal("Error preparing check organization statement:", err)
+		logging.Fatal("Error preparing check organization statement", "error", err)
 	}
 	defer checkOrgStmt.Close()
 
 	// Prepare statement untuk mengecek keberadaan user
 	checkUserStmt, err := LocalIdentityDB.Prepare(`SELECT COUNT(*) FROM "user" WHERE id = $1`)
 	if err != nil {
-		log.Fatal("Error preparing check user statement:", err)
+		logging.Fatal("Error preparing check user statement", "error", err)
 	}
 	defer checkUserStmt.Close()
 
@@ -74,7 +67,7 @@ func OrganizationUserService() {
 		WHERE organization_id = $1 AND user_id = $2
 	`)
 	if err != nil {
-		log.Fatal("Error preparing check duplicate statement:", err)
+		logging.Fatal("Error preparing check duplicate statement", "error", err)
 	}
 	defer checkDuplicateStmt.Close()
 
@@ -91,7 +84,7 @@ func OrganizationUserService() {
 		)
 	`)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
@@ -106,7 +99,7 @@ func OrganizationUserService() {
 	// Begin transaction
 	tx, err := LocalIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Prepare statements dalam transaksi
@@ -115,8 +108,6 @@ func OrganizationUserService() {
 	txCheckDuplicateStmt := tx.Stmt(checkDuplicateStmt)
 	txInsertStmt := tx.Stmt(insertStmt)
 
-	startTime := time.Now()
-
 	// Memproses setiap baris data
 	var (
 		travelID string
@@ -128,11 +119,13 @@ func OrganizationUserService() {
 		// Scan data dari source database
 		err := rows.Scan(&travelID, &userID, &role)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			report.Errorf("Error scanning row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(userID)
 
 		// Cek keberadaan organization_id
 		var organizationID interface{}
@@ -140,8 +133,9 @@ func OrganizationUserService() {
 			var count int
 			err = txCheckOrgStmt.QueryRow(travelID).Scan(&count)
 			if err != nil {
-				log.Printf("Error checking organization: %v", err)
+				report.Errorf("Error checking organization: %v", err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
@@ -149,10 +143,12 @@ func OrganizationUserService() {
 				organizationID = travelID
 			} else {
 				organizationID = "d0ac7aad-54ac-41f1-ba1a-a9070c3f464c"
-				log.Printf("Organization ID %s not found in organizations table, setting to default ID", travelID)
+				slog.Warn("Organization not found in organizations table, using default organization", "organization_id", travelID)
+				report.Placeholder(fmt.Sprintf("organization for user %s: fallback organization %s (travel %s)", userID, organizationID, travelID))
 			}
 		} else {
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -161,14 +157,17 @@ func OrganizationUserService() {
 		var count int
 		err = txCheckUserStmt.QueryRow(userID).Scan(&count)
 		if err != nil {
-			log.Printf("Error checking user: %v", err)
+			report.Errorf("Error checking user: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		// User yang tidak dimigrasi (misalnya soft delete) memang dilewati, bukan error
 		if count == 0 {
-			log.Printf("User ID %s not found in user table, skipping", userID)
-			errorCount++
+			report.Item("Skipped organization users (user not in user table)", fmt.Sprintf("org: %s, user: %s", organizationID, userID))
+			skipCount++
+			report.Add(report.OutcomeSkipped, 1)
 			bar.Add(1)
 			continue
 		}
@@ -176,8 +175,9 @@ func OrganizationUserService() {
 		// Cek duplikasi
 		err = txCheckDuplicateStmt.QueryRow(organizationID, userID).Scan(&count)
 		if err != nil {
-			log.Printf("Error checking for duplicates: %v", err)
+			report.Errorf("Error checking for duplicates: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -185,6 +185,7 @@ func OrganizationUserService() {
 		if count > 0 {
 			duplicateItems = append(duplicateItems, fmt.Sprintf("org: %s, user: %s", organizationID, userID))
 			skipCount++
+			report.Add(report.OutcomeSkipped, 1)
 			bar.Add(1)
 			continue
 		}
@@ -197,44 +198,34 @@ func OrganizationUserService() {
 		)
 
 		if err != nil {
-			log.Printf("Error inserting row: %v", err)
+			report.Errorf("Error inserting row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		transferredCount++
+		report.Add(report.OutcomeTransferred, 1)
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
-	duration := time.Since(startTime)
-
 	// Update progress bar description for completion
 	bar.Finish()
-	fmt.Printf("\n[2/2] Transfer completed!\n")
-	fmt.Printf("\nTransfer Summary:\n")
-	fmt.Printf("----------------\n")
-	fmt.Printf("Total records: %d\n", totalRows)
-	fmt.Printf("Successfully transferred: %d\n", transferredCount)
-	fmt.Printf("Skipped (duplicates): %d\n", skipCount)
-	fmt.Printf("Failed transfers: %d\n", errorCount)
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f records/second\n", float64(transferredCount)/duration.Seconds())
-
-	// Menampilkan list item duplikat
-	if len(duplicateItems) > 0 {
-		fmt.Printf("\nDuplicate Items:\n")
-		fmt.Printf("---------------\n")
-		for i, item := range duplicateItems {
-			fmt.Printf("%d. %s\n", i+1, item)
-		}
+	fmt.Printf("\n%s\n", i18n.T("transfer.completed"))
+
+	report.Inserted("organization_user", transferredCount)
+	for _, item := range duplicateItems {
+		report.Duplicate(item)
 	}
+	report.Finish()
 }
@@ -4,9 +4,12 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
-	"time"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/service/user/helper"
 )
 
 func BdmPersonaService() {
@@ -24,37 +27,27 @@ func BdmPersonaService() {
 	var totalBdmUsers int
 	err := localIdentityDB.QueryRow(`SELECT COUNT(*) FROM "user" WHERE role = 'bdm'`).Scan(&totalBdmUsers)
 	if err != nil {
-		log.Fatal("Error counting BDM users:", err)
+		logging.Fatal("Error counting BDM users", "error", err)
 	}
 
-	fmt.Printf("Found %d BDM users to process\n", totalBdmUsers)
+	fmt.Println(i18n.T("bdm_persona.found", totalBdmUsers))
+
+	report.SetTotal(totalBdmUsers)
 
 	// Create progress bar
-	bar := progressbar.NewOptions(totalBdmUsers,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/2][reset] Processing data..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	bar := progress.Single("bdm personas", totalBdmUsers)
 
 	// Get BDM users from target database
 	bdmRows, err := localIdentityDB.Query(`SELECT id FROM "user" WHERE role = 'bdm'`)
 	if err != nil {
-		log.Fatal("Error querying BDM users:", err)
+		logging.Fatal("Error querying BDM users", "error", err)
 	}
 	defer bdmRows.Close()
 
 	// Begin transaction
 	tx, err := localIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 	defer tx.Rollback()
 
@@ -65,7 +58,7 @@ func BdmPersonaService() {
 		WHERE phone_number = $1 AND id != $2
 	`)
 	if err != nil {
-		log.Fatal("Error preparing check statement:", err)
+		logging.Fatal("Error preparing check statement", "error", err)
 	}
 	defer checkStmt.Close()
 
@@ -77,7 +70,7 @@ func BdmPersonaService() {
 		SET phone_number = $2
 	`)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
@@ -88,7 +81,7 @@ func BdmPersonaService() {
 		WHERE CAST(id AS VARCHAR(255)) = $1
 	`)
 	if err != nil {
-		log.Fatal("Error preparing RDA phone statement:", err)
+		logging.Fatal("Error preparing RDA phone statement", "error", err)
 	}
 	defer getRdaPhoneStmt.Close()
 
@@ -100,8 +93,6 @@ func BdmPersonaService() {
 		noPhoneCount     int
 	)
 
-	startTime := time.Now()
-
 	// Process each BDM user
 	for bdmRows.Next() {
 		var userId string
@@ -109,22 +100,26 @@ func BdmPersonaService() {
 		// Get BDM user ID
 		err := bdmRows.Scan(&userId)
 		if err != nil {
-			log.Printf("Error scanning BDM user ID: %v", err)
+			report.Errorf("Error scanning BDM user ID: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(userId)
 
 		// Get phone from tr_rda using user ID
 		var phone sql.NullString
 		err = getRdaPhoneStmt.QueryRow(userId).Scan(&phone)
 		if err == sql.ErrNoRows {
 			noPhoneCount++
+			report.Add("no_phone_found", 1)
 			bar.Add(1)
 			continue
 		} else if err != nil {
-			log.Printf("Error getting RDA phone: %v", err)
+			report.Errorf("Error getting RDA phone: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -134,14 +129,17 @@ func BdmPersonaService() {
 			var count int
 			err = checkStmt.QueryRow(phone.String, userId).Scan(&count)
 			if err != nil {
-				log.Printf("Error checking for duplicate phone: %v", err)
+				report.Errorf("Error checking for duplicate phone: %v", err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 				bar.Add(1)
 				continue
 			}
 
 			// If duplicate found, set phone to empty string (will be NULL in database)
 			if count > 0 {
+				report.Duplicate(fmt.Sprintf("%s (phone %s)", userId, phone.String))
+				helper.DuplicatePhones.Add(userId, phone.String)
 				phone.String = ""
 				phone.Valid = false
 				duplicateCount++
@@ -151,41 +149,38 @@ func BdmPersonaService() {
 		// Insert or update record
 		_, err = insertStmt.Exec(userId, phone)
 		if err != nil {
-			log.Printf("Error inserting/updating row: %v", err)
+			report.Errorf("Error inserting/updating row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		transferredCount++
+		report.Add(report.OutcomeTransferred, 1)
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Check for errors from bdmRows.Next()
 	if err = bdmRows.Err(); err != nil {
-		log.Printf("Error iterating BDM rows: %v", err)
+		report.Errorf("Error iterating BDM rows: %v", err)
 		return
 	}
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		return
 	}
 
-	duration := time.Since(startTime)
-
 	// Update progress bar description for completion
 	bar.Finish()
-	fmt.Printf("\n[2/2] Processing completed!\n")
-	fmt.Printf("\nProcessing Summary:\n")
-	fmt.Printf("------------------\n")
-	fmt.Printf("Total BDM users: %d\n", totalBdmUsers)
-	fmt.Printf("Successfully processed: %d\n", transferredCount)
-	fmt.Printf("Duplicate phone numbers handled: %d\n", duplicateCount)
-	fmt.Printf("No phone numbers found: %d\n", noPhoneCount)
-	fmt.Printf("Failed processes: %d\n", errorCount)
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f records/second\n", float64(transferredCount)/duration.Seconds())
+	fmt.Printf("\n%s\n", i18n.T("processing.completed"))
+
+	report.Counter("duplicate_phones_cleared", duplicateCount)
+	// Insert atau update (upsert), dicatat sebagai insert
+	report.Inserted("user_persona", transferredCount)
+	report.Finish()
 }
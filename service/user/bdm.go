@@ -4,8 +4,12 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/service/user/helper"
 	"time"
 )
 
@@ -33,7 +37,7 @@ func BDMService() {
 	//var roleExists bool
 	//err := prodIdentityDB.QueryRow(`SELECT EXISTS(SELECT 1 FROM "role" WHERE slug = $1)`, roleSlug).Scan(&roleExists)
 	//if err != nil {
-	//	log.Fatal("Error checking role existence:", err)
+	//	logging.Fatal("Error checking role existence", "error", err)
 	//}
 
 	// Jika role belum ada, insert role terlebih dahulu
@@ -42,7 +46,7 @@ func BDMService() {
 	//						   VALUES ($1, $2)`,
 	//		roleName, roleSlug)
 	//	if err != nil {
-	//		log.Fatal("Error inserting role:", err)
+	//		logging.Fatal("Error inserting role", "error", err)
 	//	}
 	//	fmt.Printf("Created role with name '%s' and slug '%s'\n", roleName, roleSlug)
 	//}
@@ -51,47 +55,37 @@ func BDMService() {
 	var totalRows int
 	err := prodExistingUmrahDB.QueryRow("SELECT COUNT(*) FROM tr_rda").Scan(&totalRows)
 	if err != nil {
-		log.Fatal("Error counting rows:", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Found %d records to transfer\n", totalRows)
+	fmt.Println(i18n.T("transfer.found", totalRows))
+
+	report.SetTotal(totalRows)
 
 	// Membuat progress bar
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/2][reset] Transferring data..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	bar := progress.Single("bdm users", totalRows)
 
 	// Mengambil data dari database sumber
 	rows, err := prodExistingUmrahDB.Query("SELECT id, name, email, phone, created_at, updated_at FROM tr_rda")
 	if err != nil {
-		log.Fatal("Error querying source database:", err)
+		logging.Fatal("Error querying source database", "error", err)
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
 		if err != nil {
-			log.Fatal("Error when closing connection to source database:", err)
+			logging.Fatal("Error when closing connection to source database", "error", err)
 		}
 	}(rows)
 
 	// Prepare statement untuk mengecek duplikasi
 	checkStmt, err := prodIdentityDB.Prepare(`SELECT COUNT(*) FROM "user" WHERE email = $1`)
 	if err != nil {
-		log.Fatal("Error preparing check statement:", err)
+		logging.Fatal("Error preparing check statement", "error", err)
 	}
 	defer func(checkStmt *sql.Stmt) {
 		err := checkStmt.Close()
 		if err != nil {
-			log.Fatal("Error when closing connection to checkStmt:", err)
+			logging.Fatal("Error when closing connection to checkStmt", "error", err)
 		}
 	}(checkStmt)
 
@@ -112,7 +106,7 @@ func BDMService() {
 		)
 	`)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
@@ -126,15 +120,13 @@ func BDMService() {
 	// Begin transaction
 	tx, err := prodIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Prepare statements dalam transaksi
 	txCheckStmt := tx.Stmt(checkStmt)
 	txInsertStmt := tx.Stmt(insertStmt)
 
-	startTime := time.Now()
-
 	// Memproses setiap baris data
 	var (
 		id        string
@@ -149,25 +141,31 @@ func BDMService() {
 		// Scan data dari source database
 		err := rows.Scan(&id, &name, &email, &phone, &createdAt, &updatedAt)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			report.Errorf("Error scanning row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(id)
 
 		// Cek apakah email sudah ada di database target
 		var count int
 		err = txCheckStmt.QueryRow(email).Scan(&count)
 		if err != nil {
-			log.Printf("Error checking for duplicate email: %v", err)
+			report.Errorf("Error checking for duplicate email: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		// Jika email sudah ada, skip record ini
 		if count > 0 {
+			report.Duplicate(fmt.Sprintf("%s (%s)", email, name))
+			helper.DuplicateEmails.Add(id, email, name)
 			skipCount++
+			report.Add(report.OutcomeSkipped, 1)
 			bar.Add(1)
 			continue
 		}
@@ -183,35 +181,31 @@ func BDMService() {
 			updatedAt, // modified_at
 		)
 		if err != nil {
-			log.Printf("Error inserting row: %v", err)
+			report.Errorf("Error inserting row: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		transferredCount++
+		report.Add(report.OutcomeTransferred, 1)
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
-	duration := time.Since(startTime)
-
 	// Update progress bar description for completion
 	bar.Finish()
-	fmt.Printf("\n[2/2] Transfer completed!\n")
-	fmt.Printf("\nTransfer Summary:\n")
-	fmt.Printf("----------------\n")
-	fmt.Printf("Total records: %d\n", totalRows)
-	fmt.Printf("Successfully transferred: %d\n", transferredCount)
-	fmt.Printf("Skipped (duplicates): %d\n", skipCount)
-	fmt.Printf("Failed transfers: %d\n", errorCount)
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f records/second\n", float64(transferredCount)/duration.Seconds())
+	fmt.Printf("\n%s\n", i18n.T("transfer.completed"))
+
+	report.Inserted("user", transferredCount)
+	report.Finish()
 }
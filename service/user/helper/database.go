@@ -3,6 +3,7 @@ package helper
 import (
 	"database/sql"
 	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
 )
 
 func EnsureWukalaRole(db *sql.DB) error {
@@ -17,7 +18,7 @@ func EnsureWukalaRole(db *sql.DB) error {
 		if err != nil {
 			return fmt.Errorf("error inserting wukala role: %v", err)
 		}
-		fmt.Println("Created 'wukala' role")
+		fmt.Println(i18n.T("user.created_wukala_role"))
 	}
 	return nil
 }
@@ -3,16 +3,14 @@ package helper
 import (
 	"database/sql"
 	"fmt"
-	"github.com/schollz/progressbar/v3"
-	"log"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 	"time"
 )
 
-func TransferData(sourceDB *sql.DB, txStmts *TxStatements, bar *progressbar.ProgressBar) *TransferStats {
-	stats := &TransferStats{
-		SkippedTravelAgent: make(map[string]string),
-	}
-
+// TransferData memindahkan user dan mencatat hasilnya di run report
+func TransferData(sourceDB *sql.DB, txStmts *TxStatements, bar *progress.Bar) {
 	// Query untuk mengambil data user
 	rows, err := sourceDB.Query(`
 		SELECT id, name, email, role, image, soft_delete, created_at, updated_at 
@@ -20,7 +18,7 @@ func TransferData(sourceDB *sql.DB, txStmts *TxStatements, bar *progressbar.Prog
 		WHERE role = 'user' AND soft_delete = 'false'
 	`)
 	if err != nil {
-		log.Fatal("Error querying source database:", err)
+		logging.Fatal("Error querying source database", "error", err)
 	}
 	defer rows.Close()
 
@@ -33,23 +31,21 @@ func TransferData(sourceDB *sql.DB, txStmts *TxStatements, bar *progressbar.Prog
 		)
 	`)
 	if err != nil {
-		log.Fatal("Error preparing travel agent check statement:", err)
+		logging.Fatal("Error preparing travel agent check statement", "error", err)
 	}
 	defer checkTravelAgentStmt.Close()
 
 	for rows.Next() {
-		processRow(rows, txStmts, checkTravelAgentStmt, stats, bar)
+		processRow(rows, txStmts, checkTravelAgentStmt, bar)
 	}
-
-	return stats
+	logging.SetRecord("")
 }
 
 func processRow(
 	rows *sql.Rows,
 	txStmts *TxStatements,
 	checkTravelAgentStmt *sql.Stmt,
-	stats *TransferStats,
-	bar *progressbar.ProgressBar,
+	bar *progress.Bar,
 ) {
 	var (
 		id         string
@@ -73,18 +69,19 @@ func processRow(
 		&createdAt,
 		&updatedAt,
 	); err != nil {
-		log.Printf("Error scanning row: %v", err)
-		stats.ErrorCount++
+		report.Errorf("Error scanning row: %v", err)
+		report.Add(report.OutcomeFailed, 1)
 		bar.Add(1)
 		return
 	}
+	logging.SetRecord(id)
 
 	// Cek apakah user adalah travel agent
 	var isTravelAgent bool
 	err := checkTravelAgentStmt.QueryRow(id).Scan(&isTravelAgent)
 	if err != nil {
-		log.Printf("Error checking travel agent status: %v", err)
-		stats.ErrorCount++
+		report.Errorf("Error checking travel agent status: %v", err)
+		report.Add(report.OutcomeFailed, 1)
 		bar.Add(1)
 		return
 	}
@@ -93,22 +90,21 @@ func processRow(
 	var count int
 	err = txStmts.Check.QueryRow(email).Scan(&count)
 	if err != nil {
-		log.Printf("Error checking for duplicate email: %v", err)
-		stats.ErrorCount++
+		report.Errorf("Error checking for duplicate email: %v", err)
+		report.Add(report.OutcomeFailed, 1)
 		bar.Add(1)
 		return
 	}
 
 	// Jika email sudah ada dan user adalah travel agent, catat dalam skippedTravelAgent
 	if count > 0 {
-		stats.DuplicateEmails = append(
-			stats.DuplicateEmails,
-			fmt.Sprintf("%s (%s)", email, name),
-		)
+		report.Duplicate(fmt.Sprintf("%s (%s)", email, name))
+		DuplicateEmails.Add(id, email, name)
 		if isTravelAgent {
-			stats.SkippedTravelAgent[email] = name
+			report.Item("Skipped travel agents (already exist)", fmt.Sprintf("%s (%s)", email, name))
+			SkippedTravelAgents.Add(id, email, name)
 		}
-		stats.SkipCount++
+		report.Add(report.OutcomeSkipped, 1)
 		bar.Add(1)
 		return
 	}
@@ -117,7 +113,7 @@ func processRow(
 	finalRole := role
 	if isTravelAgent {
 		finalRole = "wukala"
-		stats.WukalaCount++
+		report.Counter("converted_to_wukala", 1)
 	}
 
 	// Insert ke target database
@@ -134,12 +130,13 @@ func processRow(
 		"migration", // created_by
 	)
 	if err != nil {
-		log.Printf("Error inserting row: %v", err)
-		stats.ErrorCount++
+		report.Errorf("Error inserting row: %v", err)
+		report.Add(report.OutcomeFailed, 1)
 		bar.Add(1)
 		return
 	}
 
-	stats.TransferredCount++
+	report.Add(report.OutcomeTransferred, 1)
+	report.Inserted("user", 1)
 	bar.Add(1)
 }
@@ -2,6 +2,14 @@ package helper
 
 import (
 	"database/sql"
+	"github.com/ApesJs/go-migration-app/export"
+)
+
+// Daftar yang ditulis ke --export-dir oleh service user
+var (
+	DuplicateEmails     = export.List{Name: "duplicate-emails", Columns: []string{"id", "email", "name"}}
+	SkippedTravelAgents = export.List{Name: "skipped-travel-agents", Columns: []string{"id", "email", "name"}}
+	DuplicatePhones     = export.List{Name: "duplicate-phones", Columns: []string{"user_id", "phone_number"}}
 )
 
 type Statements struct {
@@ -15,15 +23,6 @@ type TxStatements struct {
 	Insert *sql.Stmt
 }
 
-type TransferStats struct {
-	TransferredCount   int
-	ErrorCount         int
-	SkipCount          int
-	WukalaCount        int
-	DuplicateEmails    []string
-	SkippedTravelAgent map[string]string
-}
-
 func (s *Statements) CloseAll() {
 	if s.CheckTravelAgent != nil {
 		s.CheckTravelAgent.Close()
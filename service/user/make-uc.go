@@ -5,9 +5,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
-	"time"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 )
 
 func MakeUCService() {
@@ -30,31 +32,22 @@ func MakeUCService() {
         WHERE uc.id IS NULL AND u.deleted = false
     `).Scan(&totalRows)
 	if err != nil {
-		log.Fatal("Error counting rows:", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Found %d users without credentials\n", totalRows)
+	fmt.Println(i18n.T("make_uc.found", totalRows))
 
 	// Jika tidak ada data yang perlu diproses
 	if totalRows == 0 {
-		fmt.Println("No users need credentials. Exiting...")
+		fmt.Println(i18n.T("make_uc.none"))
+		report.Finish()
 		return
 	}
 
+	report.SetTotal(totalRows)
+
 	// Membuat progress bar
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/1][reset] Generating credentials..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	bar := progress.Single("user credentials", totalRows)
 
 	// Prepare statement untuk insert
 	insertStmt, err := prodIdentityDB.Prepare(`
@@ -62,14 +55,14 @@ func MakeUCService() {
         VALUES ($1, $2, $3)
     `)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
 	// Begin transaction
 	tx, err := prodIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Prepare statement dalam transaksi
@@ -81,8 +74,6 @@ func MakeUCService() {
 		errorCount   int
 	)
 
-	startTime := time.Now()
-
 	// Query untuk mendapatkan user yang belum memiliki credentials
 	rows, err := prodIdentityDB.Query(`
         SELECT u.id 
@@ -91,7 +82,7 @@ func MakeUCService() {
         WHERE uc.id IS NULL AND u.deleted = false
     `)
 	if err != nil {
-		log.Fatal("Error querying users:", err)
+		logging.Fatal("Error querying users", "error", err)
 	}
 	defer rows.Close()
 
@@ -100,17 +91,20 @@ func MakeUCService() {
 		var userID string
 		err := rows.Scan(&userID)
 		if err != nil {
-			log.Printf("Error scanning user ID: %v", err)
+			report.Errorf("Error scanning user ID: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(userID)
 
 		// Generate salt (16 bytes = 32 chars hex)
 		salt := make([]byte, 16)
 		if _, err := rand.Read(salt); err != nil {
-			log.Printf("Error generating salt: %v", err)
+			report.Errorf("Error generating salt: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -119,8 +113,9 @@ func MakeUCService() {
 		// Generate hashed password (32 bytes = 64 chars hex)
 		hashedPw := make([]byte, 32)
 		if _, err := rand.Read(hashedPw); err != nil {
-			log.Printf("Error generating hashed password: %v", err)
+			report.Errorf("Error generating hashed password: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -129,33 +124,30 @@ func MakeUCService() {
 		// Insert credentials
 		_, err = txInsertStmt.Exec(userID, saltHex, hashedPwHex)
 		if err != nil {
-			log.Printf("Error inserting credentials for user %s: %v", userID, err)
+			report.Errorf("Error inserting credentials for user %s: %v", userID, err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
 		successCount++
+		report.Add("generated", 1)
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// Commit transaction
-	err = tx.Commit()
+	err = metrics.Commit(tx)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
-	duration := time.Since(startTime)
-
 	// Update progress bar description for completion
 	bar.Finish()
-	fmt.Printf("\nCredentials Generation Summary:\n")
-	fmt.Printf("-----------------------------\n")
-	fmt.Printf("Total users processed: %d\n", totalRows)
-	fmt.Printf("Successfully generated: %d\n", successCount)
-	fmt.Printf("Failed generations: %d\n", errorCount)
-	fmt.Printf("Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("Average speed: %.2f records/second\n", float64(successCount)/duration.Seconds())
+
+	report.Inserted("user_credentials", successCount)
+	report.Finish()
 }
@@ -4,9 +4,12 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
-	"time"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/ledger"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/service/user/helper"
 )
 
 type DuplicatePhoneInfo struct {
@@ -27,7 +30,7 @@ func UserPersonaService() {
 	//localIdentityDB := database.ConnectionLocalIdentityDB()
 	//defer localIdentityDB.Close()
 
-	fmt.Println("Memulai proses transfer data persona user...")
+	fmt.Println(i18n.T("user_persona.start"))
 
 	alterTableQueries := []string{
 		`ALTER TABLE "user_persona" ADD COLUMN IF NOT EXISTS address TEXT`,
@@ -38,38 +41,27 @@ func UserPersonaService() {
 	for _, query := range alterTableQueries {
 		_, err := prodIdentityDB.Exec(query)
 		if err != nil {
-			log.Fatal("Error saat menambahkan kolom:", err)
+			logging.Fatal("Error adding column", "error", err)
 		}
 	}
 
 	var totalRows int
 	err := prodIdentityDB.QueryRow(`SELECT COUNT(*) FROM "user"`).Scan(&totalRows)
 	if err != nil {
-		log.Fatal("Error saat menghitung total rows:", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Total user yang akan diproses: %d\n", totalRows)
-
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/1][reset] Memproses data persona..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	fmt.Println(i18n.T("user_persona.total", totalRows))
+
+	report.SetTotal(totalRows)
+	bar := progress.Single("user personas", totalRows)
 
 	// Query untuk mengecek nomor telepon yang sudah ada
 	checkPhoneStmt, err := prodIdentityDB.Prepare(`
 		SELECT id FROM "user_persona" WHERE phone_number = $1 LIMIT 1
 	`)
 	if err != nil {
-		log.Fatal("Error preparing check phone statement:", err)
+		logging.Fatal("Error preparing check phone statement", "error", err)
 	}
 	defer checkPhoneStmt.Close()
 
@@ -77,7 +69,7 @@ func UserPersonaService() {
 		SELECT COUNT(*) FROM "user_persona" WHERE id = $1
 	`)
 	if err != nil {
-		log.Fatal("Error preparing check statement:", err)
+		logging.Fatal("Error preparing check statement", "error", err)
 	}
 	defer checkStmt.Close()
 
@@ -88,7 +80,7 @@ func UserPersonaService() {
 		) VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`)
 	if err != nil {
-		log.Fatal("Error preparing insert statement:", err)
+		logging.Fatal("Error preparing insert statement", "error", err)
 	}
 	defer insertStmt.Close()
 
@@ -103,7 +95,7 @@ func UserPersonaService() {
 		WHERE id = $1
 	`)
 	if err != nil {
-		log.Fatal("Error preparing update statement:", err)
+		logging.Fatal("Error preparing update statement", "error", err)
 	}
 	defer updateStmt.Close()
 
@@ -112,8 +104,6 @@ func UserPersonaService() {
 		updateCount     int
 		errorCount      int
 		skippedCount    int
-		startTime       = time.Now()
-		processedRows   = 0
 		duplicatePhones = make([]DuplicatePhoneInfo, 0)
 	)
 
@@ -125,12 +115,12 @@ func UserPersonaService() {
 		SELECT id, phone_number FROM "user_persona" WHERE phone_number IS NOT NULL
 	`)
 	if err != nil {
-		log.Fatal("Error querying existing phone numbers:", err)
+		logging.Fatal("Error querying existing phone numbers", "error", err)
 	}
 	for existingPhones.Next() {
 		var id, phone string
 		if err := existingPhones.Scan(&id, &phone); err != nil {
-			log.Fatal("Error scanning existing phone numbers:", err)
+			logging.Fatal("Error scanning existing phone numbers", "error", err)
 		}
 		if phone != "" {
 			usedPhoneNumbers[phone] = id
@@ -140,7 +130,7 @@ func UserPersonaService() {
 
 	rows, err := prodIdentityDB.Query(`SELECT id FROM "user"`)
 	if err != nil {
-		log.Fatal("Error querying user data:", err)
+		logging.Fatal("Error querying user data", "error", err)
 	}
 	defer rows.Close()
 
@@ -148,11 +138,13 @@ func UserPersonaService() {
 		var userID string
 		err := rows.Scan(&userID)
 		if err != nil {
-			log.Printf("Error scanning user ID: %v", err)
+			report.Errorf("Error scanning user ID: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
+		logging.SetRecord(userID)
 
 		var (
 			phone   sql.NullString
@@ -170,11 +162,13 @@ func UserPersonaService() {
 
 		if err == sql.ErrNoRows {
 			skippedCount++
+			report.Add(report.OutcomeSkipped, 1)
 			bar.Add(1)
 			continue
 		} else if err != nil {
-			log.Printf("Error querying source data for user %s: %v", userID, err)
+			report.Errorf("Error querying source data for user %s: %v", userID, err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
@@ -182,12 +176,17 @@ func UserPersonaService() {
 		var exists int
 		err = checkStmt.QueryRow(userID).Scan(&exists)
 		if err != nil {
-			log.Printf("Error checking existing record: %v", err)
+			report.Errorf("Error checking existing record: %v", err)
 			errorCount++
+			report.Add(report.OutcomeFailed, 1)
 			bar.Add(1)
 			continue
 		}
 
+		// Tanpa transaksi setiap baris langsung tersimpan, jadi event ditulis ke
+		// ledger setelah insert atau update berhasil dan dibuang jika gagal
+		var lossy ledger.Buffer
+
 		// Cek apakah phone number valid dan sudah digunakan
 		if phone.Valid && phone.String != "" {
 			if existingID, exists := usedPhoneNumbers[phone.String]; exists && existingID != userID {
@@ -197,6 +196,7 @@ func UserPersonaService() {
 					PhoneNumber: phone.String,
 				})
 				// Set phone number menjadi NULL
+				lossy.Record("user_persona", userID, "phone_number", phone.String, "", ledger.ReasonDuplicate)
 				phone.Valid = false
 				phone.String = ""
 			} else if !exists {
@@ -205,16 +205,16 @@ func UserPersonaService() {
 			}
 		}
 
-		if gender.Valid && len(gender.String) > 16 {
-			gender.String = gender.String[:16]
+		if gender.Valid {
+			gender.String = lossy.Truncate("user_persona", userID, "gender", gender.String, 16)
 		}
 
-		if phone.Valid && len(phone.String) > 16 {
-			phone.String = phone.String[:16]
+		if phone.Valid {
+			phone.String = lossy.Truncate("user_persona", userID, "phone_number", phone.String, 16)
 		}
 
-		if pob.Valid && len(pob.String) > 10 {
-			pob.String = pob.String[:10]
+		if pob.Valid {
+			pob.String = lossy.Truncate("user_persona", userID, "pob", pob.String, 10)
 		}
 
 		var phoneValue interface{}
@@ -235,10 +235,13 @@ func UserPersonaService() {
 				dob.Time,
 			)
 			if err != nil {
-				log.Printf("Error updating record for user %s: %v", userID, err)
+				report.Errorf("Error updating record for user %s: %v", userID, err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 			} else {
 				updateCount++
+				report.Add(report.OutcomeUpdated, 1)
+				lossy.Flush()
 			}
 		} else {
 			_, err = insertStmt.Exec(
@@ -251,39 +254,28 @@ func UserPersonaService() {
 				dob.Time,
 			)
 			if err != nil {
-				log.Printf("Error inserting record for user %s: %v", userID, err)
+				report.Errorf("Error inserting record for user %s: %v", userID, err)
 				errorCount++
+				report.Add(report.OutcomeFailed, 1)
 			} else {
 				insertCount++
+				report.Add(report.OutcomeInserted, 1)
+				lossy.Flush()
 			}
 		}
 
-		processedRows++
 		bar.Add(1)
 	}
-
-	duration := time.Since(startTime)
+	logging.SetRecord("")
 
 	bar.Finish()
-	fmt.Printf("\nTransfer data persona selesai!\n")
-	fmt.Printf("\nRingkasan Hasil:\n")
-	fmt.Printf("---------------\n")
-	fmt.Printf("Total records diproses: %d\n", processedRows)
-	fmt.Printf("Records berhasil ditambahkan: %d\n", insertCount)
-	fmt.Printf("Records berhasil diperbarui: %d\n", updateCount)
-	fmt.Printf("Records dilewati: %d\n", skippedCount)
-	fmt.Printf("Records gagal: %d\n", errorCount)
-	fmt.Printf("Durasi proses: %s\n", duration.Round(time.Second))
-	fmt.Printf("Kecepatan rata-rata: %.2f records/detik\n", float64(processedRows)/duration.Seconds())
-
-	if len(duplicatePhones) > 0 {
-		fmt.Printf("\nDaftar Nomor Telepon Duplikat:\n")
-		fmt.Printf("--------------------------------\n")
-		fmt.Printf("%-36s | %s\n", "User ID", "Nomor Telepon")
-		fmt.Printf("--------------------------------\n")
-		for _, dup := range duplicatePhones {
-			fmt.Printf("%-36s | %s\n", dup.UserID, dup.PhoneNumber)
-		}
-		fmt.Printf("\nTotal nomor telepon duplikat: %d\n", len(duplicatePhones))
+	fmt.Printf("\n%s\n", i18n.T("user_persona.completed"))
+
+	report.Inserted("user_persona", insertCount)
+	report.Updated("user_persona", updateCount)
+	for _, dup := range duplicatePhones {
+		report.Duplicate(fmt.Sprintf("%s (phone %s)", dup.UserID, dup.PhoneNumber))
+		helper.DuplicatePhones.Add(dup.UserID, dup.PhoneNumber)
 	}
+	report.Finish()
 }
@@ -3,9 +3,12 @@ package user
 import (
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
 	"github.com/ApesJs/go-migration-app/service/user/helper"
-	"log"
-	"time"
 )
 
 func UserService() {
@@ -22,51 +25,50 @@ func UserService() {
 	// Cek dan buat role wukala jika belum ada
 	err := helper.EnsureWukalaRole(localIdentityDB)
 	if err != nil {
-		log.Fatal("Error ensuring wukala role:", err)
+		logging.Fatal("Error ensuring wukala role", "error", err)
 	}
 
 	// Menghitung total records
 	totalRows, totalTravelAgents, err := helper.CountTotalRecords(prodExistingUmrahDB)
 	if err != nil {
-		log.Fatal("Error counting records:", err)
+		logging.Fatal("Error counting records", "error", err)
 	}
 
-	fmt.Printf("Found %d total records to transfer\n", totalRows)
-	fmt.Printf("Found %d wukala in source database\n", totalTravelAgents)
+	fmt.Println(i18n.T("transfer.found", totalRows))
+	fmt.Println(i18n.T("user.found_wukala", totalTravelAgents))
+	report.SetTotal(totalRows)
+	report.Counter("travel_agents_in_source", totalTravelAgents)
 
 	// Membuat progress bar
-	bar := helper.CreateProgressBar(totalRows)
+	bar := progress.Single("users", totalRows)
 
 	// Prepare statements
 	stmts, err := helper.PrepareStatements(prodExistingUmrahDB, localIdentityDB)
 	if err != nil {
-		log.Fatal("Error preparing statements:", err)
+		logging.Fatal("Error preparing statements", "error", err)
 	}
 	defer stmts.CloseAll()
 
 	// Begin transaction
 	tx, err := localIdentityDB.Begin()
 	if err != nil {
-		log.Fatal("Error starting transaction:", err)
+		logging.Fatal("Error starting transaction", "error", err)
 	}
 
 	// Prepare statements dalam transaksi
 	txStmts := helper.CreateTxStatements(tx, stmts)
 
-	startTime := time.Now()
-
 	// Transfer data
-	stats := helper.TransferData(prodExistingUmrahDB, txStmts, bar)
+	helper.TransferData(prodExistingUmrahDB, txStmts, bar)
+	bar.Finish()
 
 	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		log.Printf("Error committing transaction: %v", err)
+	if err = metrics.Commit(tx); err != nil {
+		report.Errorf("Error committing transaction: %v", err)
 		tx.Rollback()
 		return
 	}
 
-	duration := time.Since(startTime)
-
-	// Print summary
-	helper.PrintSummary(stats, totalRows, totalTravelAgents, duration)
+	fmt.Printf("\n%s\n", i18n.T("transfer.completed"))
+	report.Finish()
 }
@@ -4,8 +4,14 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/ApesJs/go-migration-app/database"
-	"github.com/schollz/progressbar/v3"
-	"log"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/ledger"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/metrics"
+	"github.com/ApesJs/go-migration-app/progress"
+	"github.com/ApesJs/go-migration-app/report"
+	"github.com/ApesJs/go-migration-app/service/user/helper"
+	"log/slog"
 	"math"
 	"strings"
 	"time"
@@ -38,18 +44,18 @@ func WukalaPersonaService() {
 	//localUmrahDB := database.ConnectionLocalUmrahDB()
 	//defer localUmrahDB.Close()
 
-	fmt.Println("Memulai proses transfer data persona wukala...")
+	fmt.Println(i18n.T("wukala_persona.start"))
 
 	// Start transactions for both target databases
 	identityTx, err := devIdentityDB.Begin()
 	if err != nil {
-		log.Fatalf("error starting identity transaction: %v", err)
+		logging.Fatal("Error starting identity transaction", "error", err)
 	}
 	defer identityTx.Rollback()
 
 	umrahTx, err := devUmrahDB.Begin()
 	if err != nil {
-		log.Fatalf("error starting umrah transaction: %v", err)
+		logging.Fatal("Error starting umrah transaction", "error", err)
 	}
 	defer umrahTx.Rollback()
 
@@ -75,38 +81,27 @@ func WukalaPersonaService() {
 	for _, query := range alterTableQueries {
 		_, err := identityTx.Exec(query)
 		if err != nil {
-			log.Fatalf("error saat menambahkan kolom: %v", err)
+			logging.Fatal("Error adding column", "error", err)
 		}
 	}
 
 	var totalRows int
 	err = devIdentityDB.QueryRow(`SELECT COUNT(*) FROM "user" WHERE role = 'wukala'`).Scan(&totalRows)
 	if err != nil {
-		log.Fatalf("error saat menghitung total rows: %v", err)
+		logging.Fatal("Error counting rows", "error", err)
 	}
 
-	fmt.Printf("Total wukala yang akan diproses: %d\n", totalRows)
-
-	bar := progressbar.NewOptions(totalRows,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(15),
-		progressbar.OptionSetDescription("[cyan][1/1][reset] Memproses data persona wukala..."),
-		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
-			SaucerPadding: " ",
-			BarStart:      "[",
-			BarEnd:        "]",
-		}),
-	)
+	fmt.Println(i18n.T("wukala_persona.total", totalRows))
+
+	report.SetTotal(totalRows)
+	bar := progress.Single("wukala personas", totalRows)
 
 	// Prepare statements for user_persona
 	checkPersonaStmt, err := identityTx.Prepare(`
 		SELECT COUNT(*) FROM "user_persona" WHERE id = $1
 	`)
 	if err != nil {
-		log.Fatalf("error preparing check persona statement: %v", err)
+		logging.Fatal("Error preparing check persona statement", "error", err)
 	}
 	defer checkPersonaStmt.Close()
 
@@ -115,7 +110,7 @@ func WukalaPersonaService() {
     SELECT COUNT(*) FROM "user_persona" WHERE code = $1 AND id != $2
 `)
 	if err != nil {
-		log.Fatalf("error preparing check code statement: %v", err)
+		logging.Fatal("Error preparing check code statement", "error", err)
 	}
 	defer checkCodeStmt.Close()
 
@@ -123,7 +118,7 @@ func WukalaPersonaService() {
 		SELECT COUNT(*) FROM "user_persona" WHERE phone_number = $1 AND id != $2
 	`)
 	if err != nil {
-		log.Fatalf("error preparing check phone statement: %v", err)
+		logging.Fatal("Error preparing check phone statement", "error", err)
 	}
 	defer checkPhoneStmt.Close()
 
@@ -142,7 +137,7 @@ func WukalaPersonaService() {
 		)
 	`)
 	if err != nil {
-		log.Fatalf("error preparing insert persona statement: %v", err)
+		logging.Fatal("Error preparing insert persona statement", "error", err)
 	}
 	defer insertPersonaStmt.Close()
 
@@ -173,7 +168,7 @@ func WukalaPersonaService() {
 		WHERE id = $1
 	`)
 	if err != nil {
-		log.Fatalf("error preparing update persona statement: %v", err)
+		logging.Fatal("Error preparing update persona statement", "error", err)
 	}
 	defer updatePersonaStmt.Close()
 
@@ -182,7 +177,7 @@ func WukalaPersonaService() {
 		SELECT COUNT(*) FROM "wukala_setting" WHERE referral_code = $1
 	`)
 	if err != nil {
-		log.Fatalf("error preparing check setting statement: %v", err)
+		logging.Fatal("Error preparing check setting statement", "error", err)
 	}
 	defer checkSettingStmt.Close()
 
@@ -196,24 +191,22 @@ func WukalaPersonaService() {
 		)
 	`)
 	if err != nil {
-		log.Fatalf("error preparing insert setting statement: %v", err)
+		logging.Fatal("Error preparing insert setting statement", "error", err)
 	}
 	defer insertSettingStmt.Close()
 
 	var (
 		insertCount     int
 		updateCount     int
-		errorCount      int
 		skippedCount    int
 		duplicateCount  int
-		startTime       = time.Now()
-		processedRows   = 0
 		duplicatePhones = make([]DuplicatePhoneInfoWukala, 0)
+		lossy           ledger.Buffer // perubahan lossy, ditulis ke ledger setelah kedua transaksi di-commit
 	)
 
 	rows, err := devIdentityDB.Query(`SELECT id FROM "user" WHERE role = 'wukala'`)
 	if err != nil {
-		log.Fatalf("error querying user data: %v", err)
+		logging.Fatal("Error querying user data", "error", err)
 	}
 	defer rows.Close()
 
@@ -221,8 +214,12 @@ func WukalaPersonaService() {
 		var userID string
 		err := rows.Scan(&userID)
 		if err != nil {
-			log.Fatalf("error scanning user ID: %v", err)
+			logging.Fatal("Error scanning user ID", "error", err)
 		}
+		logging.SetRecord(userID)
+
+		// Event dicatat ke ledger hanya untuk baris yang benar-benar ditulis
+		var recordLossy, settingLossy ledger.Buffer
 
 		var (
 			travelID      sql.NullString
@@ -271,10 +268,11 @@ func WukalaPersonaService() {
 
 		if err == sql.ErrNoRows {
 			skippedCount++
+			report.Add(report.OutcomeSkipped, 1)
 			bar.Add(1)
 			continue
 		} else if err != nil {
-			log.Fatalf("error querying source data for user %s: %v", userID, err)
+			logging.Fatal("Error querying source data", "user_id", userID, "error", err)
 		}
 
 		// Check for duplicate phone number
@@ -282,13 +280,14 @@ func WukalaPersonaService() {
 			var count int
 			err = checkPhoneStmt.QueryRow(phone.String, userID).Scan(&count)
 			if err != nil {
-				log.Fatalf("error checking duplicate phone: %v", err)
+				logging.Fatal("Error checking duplicate phone", "error", err)
 			}
 			if count > 0 {
 				duplicatePhones = append(duplicatePhones, DuplicatePhoneInfoWukala{
 					UserID:      userID,
 					PhoneNumber: phone.String,
 				})
+				recordLossy.Record("user_persona", userID, "phone_number", phone.String, "", ledger.ReasonDuplicate)
 				phone.Valid = false
 				phone.String = ""
 				duplicateCount++
@@ -296,8 +295,8 @@ func WukalaPersonaService() {
 		}
 
 		// Truncate phone number if necessary
-		if phone.Valid && len(phone.String) > 16 {
-			phone.String = phone.String[:16]
+		if phone.Valid {
+			phone.String = recordLossy.Truncate("user_persona", userID, "phone_number", phone.String, 16)
 		}
 
 		// Process wukala_setting first
@@ -305,54 +304,53 @@ func WukalaPersonaService() {
 			var codeCount int
 			err = checkCodeStmt.QueryRow(code.String, userID).Scan(&codeCount)
 			if err != nil {
-				log.Fatalf("error checking duplicate code: %v", err)
+				logging.Fatal("Error checking duplicate code", "error", err)
 			}
 			if codeCount > 0 {
 				// Jika code duplikat, set menjadi empty string
+				recordLossy.Record("user_persona", userID, "code", code.String, "", ledger.ReasonDuplicate)
+				report.Duplicate(fmt.Sprintf("%s (code %s)", userID, code.String))
 				code.String = ""
 				code.Valid = false
-				log.Printf("Warning: Duplicate code found for user %s, setting to empty", userID)
+				slog.Warn("Duplicate code found, setting to empty", "user_id", userID)
 			}
 
 			// Truncate code to 8 chars if needed
-			referralCode := code.String
-			if len(referralCode) > 8 {
-				referralCode = referralCode[:8]
-			}
+			referralCode := settingLossy.Truncate("wukala_setting", userID, "referral_code", code.String, 8)
 
 			// Convert fee and discount to integer
 			feeAmount := 0
 			if fee.Valid {
 				feeAmount = int(math.Round(fee.Float64))
+				if float64(feeAmount) != fee.Float64 {
+					settingLossy.Record("wukala_setting", userID, "fee_amount", fmt.Sprint(fee.Float64), fmt.Sprint(feeAmount), ledger.ReasonRounded)
+				}
 			}
 
 			discountAmount := 0
 			if discount.Valid {
 				discountAmount = int(math.Round(discount.Float64))
+				if float64(discountAmount) != discount.Float64 {
+					settingLossy.Record("wukala_setting", userID, "discount", fmt.Sprint(discount.Float64), fmt.Sprint(discountAmount), ledger.ReasonRounded)
+				}
 			}
 
 			// Truncate types to 12 chars if needed
 			feeTypeStr := "nominal"
 			if feeType.Valid {
-				feeTypeStr = feeType.String
-				if len(feeTypeStr) > 12 {
-					feeTypeStr = feeTypeStr[:12]
-				}
+				feeTypeStr = settingLossy.Truncate("wukala_setting", userID, "fee_type", feeType.String, 12)
 			}
 
 			discountTypeStr := "nominal"
 			if discountType.Valid {
-				discountTypeStr = discountType.String
-				if len(discountTypeStr) > 12 {
-					discountTypeStr = discountTypeStr[:12]
-				}
+				discountTypeStr = settingLossy.Truncate("wukala_setting", userID, "discount_type", discountType.String, 12)
 			}
 
 			// Check if referral_code already exists
 			var codeExists int
 			err = checkSettingStmt.QueryRow(referralCode).Scan(&codeExists)
 			if err != nil {
-				log.Fatalf("error checking existing referral code: %v", err)
+				logging.Fatal("Error checking existing referral code", "error", err)
 			}
 
 			if codeExists == 0 {
@@ -386,8 +384,10 @@ func WukalaPersonaService() {
 					nil,
 				)
 				if err != nil {
-					log.Fatalf("error inserting wukala setting for user %s: %v", userID, err)
+					logging.Fatal("Error inserting wukala setting", "user_id", userID, "error", err)
 				}
+				report.Inserted("wukala_setting", 1)
+				recordLossy.Append(&settingLossy)
 			}
 		}
 
@@ -395,7 +395,7 @@ func WukalaPersonaService() {
 		var exists int
 		err = checkPersonaStmt.QueryRow(userID).Scan(&exists)
 		if err != nil {
-			log.Fatalf("error checking existing record: %v", err)
+			logging.Fatal("Error checking existing record", "error", err)
 		}
 
 		// Handle UUID values
@@ -446,9 +446,10 @@ func WukalaPersonaService() {
 				discount,     // discount
 			)
 			if err != nil {
-				log.Fatalf("error updating record for user %s: %v", userID, err)
+				logging.Fatal("Error updating record", "user_id", userID, "error", err)
 			}
 			updateCount++
+			report.Add(report.OutcomeUpdated, 1)
 		} else {
 			_, err = insertPersonaStmt.Exec(
 				userID, sql.NullString{String: phone.String, Valid: phone.Valid},
@@ -464,49 +465,38 @@ func WukalaPersonaService() {
 				discount,     // discount
 			)
 			if err != nil {
-				log.Fatalf("error inserting record for user %s: %v", userID, err)
+				logging.Fatal("Error inserting record", "user_id", userID, "error", err)
 			}
 			insertCount++
+			report.Add(report.OutcomeInserted, 1)
 		}
 
-		processedRows++
+		lossy.Append(&recordLossy)
 		bar.Add(1)
 	}
+	logging.SetRecord("")
 
 	// If we've made it here, commit both transactions
-	err = identityTx.Commit()
+	err = metrics.Commit(identityTx)
 	if err != nil {
-		log.Fatalf("error committing identity transaction: %v", err)
+		logging.Fatal("Error committing identity transaction", "error", err)
 	}
 
-	err = umrahTx.Commit()
+	err = metrics.Commit(umrahTx)
 	if err != nil {
-		log.Fatalf("error committing umrah transaction: %v", err)
+		logging.Fatal("Error committing umrah transaction", "error", err)
 	}
-
-	duration := time.Since(startTime)
+	lossy.Flush()
 
 	bar.Finish()
-	fmt.Printf("\nTransfer data persona wukala selesai!\n")
-	fmt.Printf("\nRingkasan Hasil:\n")
-	fmt.Printf("---------------\n")
-	fmt.Printf("Total records diproses: %d\n", processedRows)
-	fmt.Printf("Records berhasil ditambahkan: %d\n", insertCount)
-	fmt.Printf("Records berhasil diperbarui: %d\n", updateCount)
-	fmt.Printf("Records dilewati: %d\n", skippedCount)
-	fmt.Printf("Records gagal: %d\n", errorCount)
-	fmt.Printf("Nomor telepon duplikat: %d\n", duplicateCount)
-	fmt.Printf("Durasi proses: %s\n", duration.Round(time.Second))
-	fmt.Printf("Kecepatan rata-rata: %.2f records/detik\n", float64(processedRows)/duration.Seconds())
-
-	if len(duplicatePhones) > 0 {
-		fmt.Printf("\nDaftar Nomor Telepon Duplikat:\n")
-		fmt.Printf("--------------------------------\n")
-		fmt.Printf("%-36s | %s\n", "User ID", "Nomor Telepon")
-		fmt.Printf("--------------------------------\n")
-		for _, dup := range duplicatePhones {
-			fmt.Printf("%-36s | %s\n", dup.UserID, dup.PhoneNumber)
-		}
-		fmt.Printf("\nTotal nomor telepon duplikat: %d\n", len(duplicatePhones))
+	fmt.Printf("\n%s\n", i18n.T("wukala_persona.completed"))
+
+	report.Counter("duplicate_phones_cleared", duplicateCount)
+	report.Inserted("user_persona", insertCount)
+	report.Updated("user_persona", updateCount)
+	for _, dup := range duplicatePhones {
+		report.Duplicate(fmt.Sprintf("%s (phone %s)", dup.UserID, dup.PhoneNumber))
+		helper.DuplicatePhones.Add(dup.UserID, dup.PhoneNumber)
 	}
+	report.Finish()
 }
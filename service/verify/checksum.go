@@ -0,0 +1,109 @@
+package verify
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/verify/helper"
+	"math"
+	"sort"
+	"time"
+)
+
+// ChecksumService membandingkan agregat field uang antara sumber dan target.
+// Mengembalikan true jika ada kehilangan nilai karena pembulatan/pemotongan
+// atau jumlah per organisasi yang berbeda.
+func ChecksumService(names []string, limit int) bool {
+	checks := helper.MoneyChecks
+	if len(names) > 0 && names[0] != "all" {
+		checks = nil
+		for _, name := range names {
+			check, ok := helper.FindMoneyCheck(name)
+			if !ok {
+				logging.Fatal("Unknown checksum", "checksum", name)
+			}
+			checks = append(checks, check)
+		}
+	}
+
+	startTime := time.Now()
+	var flagged []string
+	for _, check := range checks {
+		fmt.Printf("\n%s\n", i18n.T("checksum.running", check.Name, check.Description))
+
+		sourceDB := check.SourceConnect()
+		targetDB := check.TargetConnect()
+
+		result, err := helper.RunChecksum(check, sourceDB, targetDB)
+		sourceDB.Close()
+		targetDB.Close()
+		if err != nil {
+			logging.Fatal("Error computing checksum", "error", err)
+		}
+
+		printChecksum(check, result, limit)
+		if result.Flagged() {
+			flagged = append(flagged, check.Name)
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("checksum.finished", time.Since(startTime).Round(time.Millisecond)))
+	if len(flagged) > 0 {
+		fmt.Println(i18n.T("checksum.flagged", flagged))
+		return true
+	}
+	fmt.Println(i18n.T("checksum.ok"))
+	return false
+}
+
+func printChecksum(check helper.MoneyCheck, result *helper.ChecksumResult, limit int) {
+	fmt.Printf("\n%s\n", i18n.T("checksum.result", check.Name))
+	fmt.Printf("------------------------\n")
+	fmt.Printf("%s\n\n", i18n.T("checksum.labels", check.SourceLabel, check.TargetLabel, result.GroupCount))
+
+	fmt.Printf("%-14s %-7s %8s %20s %16s %16s %9s %18s\n",
+		"FIELD", "SIDE", "COUNT", "SUM", "MIN", "MAX", "LOSS ROWS", "LOSS AMOUNT")
+	for _, field := range result.Fields {
+		fmt.Printf("%-14s %-7s %8d %20.2f %16.2f %16.2f\n",
+			field.Field, "source", field.Source.Count, field.Source.Sum, field.Source.Min, field.Source.Max)
+		fmt.Printf("%-14s %-7s %8d %20.2f %16.2f %16.2f %9d %18.2f\n",
+			"", "target", field.Target.Count, field.Target.Sum, field.Target.Min, field.Target.Max,
+			field.LossRows, field.LossAmount)
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("checksum.distribution"))
+	fmt.Printf("%-14s", "FIELD")
+	for _, bucket := range helper.MoneyBuckets {
+		fmt.Printf(" %15s", bucket.Label)
+	}
+	fmt.Println()
+	for _, field := range result.Fields {
+		fmt.Printf("%-14s", field.Field)
+		for i := range helper.MoneyBuckets {
+			fmt.Printf(" %15s", fmt.Sprintf("%d/%d", field.Source.Distribution[i], field.Target.Distribution[i]))
+		}
+		fmt.Println()
+	}
+
+	if len(result.Groups) == 0 {
+		fmt.Printf("\n%s\n", i18n.T("checksum.all_match"))
+	} else {
+		groups := result.Groups
+		sort.Slice(groups, func(i, j int) bool {
+			return math.Abs(groups[i].SourceSum-groups[i].TargetSum) > math.Abs(groups[j].SourceSum-groups[j].TargetSum)
+		})
+
+		fmt.Printf("\n%s\n", i18n.T("checksum.different", len(groups)))
+		fmt.Printf("%-38s %-14s %18s %18s %14s\n", "ORGANIZATION", "FIELD", "SOURCE SUM", "TARGET SUM", "DIFF")
+		for i, group := range groups {
+			if limit > 0 && i >= limit {
+				fmt.Println(i18n.T("check.more_limit", len(groups)-limit))
+				break
+			}
+			fmt.Printf("%-38s %-14s %18.2f %18.2f %14.2f\n",
+				group.Group, group.Field, group.SourceSum, group.TargetSum, group.TargetSum-group.SourceSum)
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("reconcile.completed", result.Duration.Round(time.Millisecond)))
+}
@@ -0,0 +1,328 @@
+package helper
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/ApesJs/go-migration-app/database"
+	packageHelper "github.com/ApesJs/go-migration-app/service/package/helper"
+	"math"
+	"time"
+)
+
+// unmatchedGroup dipakai untuk baris target yang key-nya tidak ada di sumber
+const unmatchedGroup = "(not in source)"
+
+// lossTolerance adalah selisih terkecil yang dianggap kehilangan nilai (setengah sen)
+const lossTolerance = 0.005
+
+// MoneyCheck membandingkan agregat field uang antara sumber dan target.
+// SourceQuery mengembalikan key, organisasi, lalu nilai setiap field.
+// TargetQuery mengembalikan key lalu nilai setiap field; organisasi diambil
+// dari baris sumber dengan key yang sama.
+type MoneyCheck struct {
+	Name          string
+	Description   string
+	Fields        []string
+	SourceLabel   string
+	SourceConnect func() *sql.DB
+	SourceQuery   string
+	TargetLabel   string
+	TargetConnect func() *sql.DB
+	TargetQuery   string
+}
+
+// Bucket distribusi nilai per orde besaran
+var MoneyBuckets = []struct {
+	Label string
+	Upper float64
+}{
+	{"0", 0},
+	{"< 1K", 1e3},
+	{"< 100K", 1e5},
+	{"< 1M", 1e6},
+	{"< 10M", 1e7},
+	{"< 100M", 1e8},
+	{">= 100M", math.Inf(1)},
+}
+
+// MoneyStats adalah agregat satu field di satu sisi
+type MoneyStats struct {
+	Count        int
+	Sum          float64
+	Min          float64
+	Max          float64
+	Distribution []int // jumlah baris per MoneyBuckets
+}
+
+func newMoneyStats() *MoneyStats {
+	return &MoneyStats{Distribution: make([]int, len(MoneyBuckets))}
+}
+
+func (s *MoneyStats) add(value float64) {
+	if s.Count == 0 || value < s.Min {
+		s.Min = value
+	}
+	if s.Count == 0 || value > s.Max {
+		s.Max = value
+	}
+	s.Count++
+	s.Sum += value
+
+	abs := math.Abs(value)
+	if abs == 0 {
+		s.Distribution[0]++
+		return
+	}
+	for i := 1; i < len(MoneyBuckets); i++ {
+		if abs < MoneyBuckets[i].Upper {
+			s.Distribution[i]++
+			return
+		}
+	}
+}
+
+// FieldChecksum adalah hasil perbandingan satu field uang
+type FieldChecksum struct {
+	Field      string
+	Source     *MoneyStats
+	Target     *MoneyStats
+	LossRows   int     // baris berpasangan yang nilainya berubah karena pembulatan/pemotongan
+	LossAmount float64 // total selisih absolut baris berpasangan
+}
+
+// GroupChecksum adalah selisih jumlah satu field untuk satu organisasi
+type GroupChecksum struct {
+	Group     string
+	Field     string
+	SourceSum float64
+	TargetSum float64
+}
+
+// ChecksumResult adalah hasil satu MoneyCheck
+type ChecksumResult struct {
+	Check      string
+	Fields     []FieldChecksum
+	Groups     []GroupChecksum // hanya organisasi yang jumlahnya berbeda
+	GroupCount int
+	Duration   time.Duration
+}
+
+// Flagged true jika ada nilai yang hilang karena pembulatan atau jumlah yang berbeda
+func (r *ChecksumResult) Flagged() bool {
+	if len(r.Groups) > 0 {
+		return true
+	}
+	for _, field := range r.Fields {
+		if field.LossRows > 0 || !sameAmount(field.Source.Sum, field.Target.Sum) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) < lossTolerance
+}
+
+// MoneyChecks berisi field uang yang dikonversi ke integer oleh migrasi
+var MoneyChecks = []MoneyCheck{
+	{
+		Name:          "package",
+		Description:   "td_package -> package + package_variant, grouped by travel",
+		Fields:        []string{"price_double", "price_triple", "price_quad", "dp_amount", "fee_amount"},
+		SourceLabel:   "prod existing umrah",
+		SourceConnect: database.ConnectionProdExistingUmrahDB,
+		SourceQuery: `
+			SELECT slug, travel_id::text,
+			       price_double, price_triple, price_quad, dp_amount, fee_amount
+			FROM td_package
+			WHERE ` + packageHelper.ActivePackageFilter,
+		TargetLabel:   "dev umrah",
+		TargetConnect: database.ConnectionDevUmrahDB,
+		TargetQuery: `
+			SELECT p.slug,
+			       v.price_double, v.price_triple, v.price_quad, p.dp_amount, p.fee_amount
+			FROM package p
+			JOIN package_variant v ON v.package_id = p.id
+			WHERE p.created_by = 'migration'
+		`,
+	},
+	{
+		Name:          "wukala-setting",
+		Description:   "td_travel_agent -> wukala_setting, grouped by travel",
+		Fields:        []string{"fee", "discount"},
+		SourceLabel:   "prod existing umrah",
+		SourceConnect: database.ConnectionProdExistingUmrahDB,
+		SourceQuery: `
+			SELECT left(code, 8), travel_id::text,
+			       COALESCE(fee, 0), COALESCE(discount, 0)
+			FROM td_travel_agent
+			WHERE code IS NOT NULL AND code <> ''
+		`,
+		TargetLabel:   "dev umrah",
+		TargetConnect: database.ConnectionDevUmrahDB,
+		TargetQuery: `
+			SELECT referral_code, fee_amount, discount_amount
+			FROM wukala_setting
+			WHERE created_by = 'migration'
+		`,
+	},
+}
+
+// FindMoneyCheck mencari MoneyCheck berdasarkan nama
+func FindMoneyCheck(name string) (MoneyCheck, bool) {
+	for _, check := range MoneyChecks {
+		if check.Name == name {
+			return check, true
+		}
+	}
+	return MoneyCheck{}, false
+}
+
+type moneyRow struct {
+	group  string
+	values []float64
+	paired bool
+}
+
+func scanMoneyRow(rows *sql.Rows, withGroup bool, fieldCount int) (string, *moneyRow, error) {
+	var key, group sql.NullString
+	values := make([]sql.NullFloat64, fieldCount)
+
+	dest := []interface{}{&key}
+	if withGroup {
+		dest = append(dest, &group)
+	}
+	for i := range values {
+		dest = append(dest, &values[i])
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return "", nil, err
+	}
+
+	row := &moneyRow{group: group.String, values: make([]float64, fieldCount)}
+	for i, value := range values {
+		row.values[i] = value.Float64
+	}
+	return key.String, row, nil
+}
+
+// RunChecksum menghitung jumlah, min, max dan distribusi setiap field uang di
+// kedua sisi, per organisasi, serta selisih per baris untuk baris yang berpasangan
+func RunChecksum(check MoneyCheck, sourceDB, targetDB *sql.DB) (*ChecksumResult, error) {
+	startTime := time.Now()
+	fieldCount := len(check.Fields)
+
+	result := &ChecksumResult{Check: check.Name}
+	for _, field := range check.Fields {
+		result.Fields = append(result.Fields, FieldChecksum{
+			Field:  field,
+			Source: newMoneyStats(),
+			Target: newMoneyStats(),
+		})
+	}
+
+	sourceSums := make(map[string][]float64)
+	targetSums := make(map[string][]float64)
+	addSum := func(sums map[string][]float64, group string, values []float64) {
+		if sums[group] == nil {
+			sums[group] = make([]float64, fieldCount)
+		}
+		for i, value := range values {
+			sums[group][i] += value
+		}
+	}
+
+	sourceRows, err := sourceDB.Query(check.SourceQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s source rows: %v", check.Name, err)
+	}
+	defer sourceRows.Close()
+
+	source := make(map[string]*moneyRow)
+	for sourceRows.Next() {
+		key, row, err := scanMoneyRow(sourceRows, true, fieldCount)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning %s source row: %v", check.Name, err)
+		}
+		for i, value := range row.values {
+			result.Fields[i].Source.add(value)
+		}
+		addSum(sourceSums, row.group, row.values)
+		if _, exists := source[key]; !exists {
+			source[key] = row
+		}
+	}
+	if err := sourceRows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s source rows: %v", check.Name, err)
+	}
+
+	targetRows, err := targetDB.Query(check.TargetQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s target rows: %v", check.Name, err)
+	}
+	defer targetRows.Close()
+
+	for targetRows.Next() {
+		key, row, err := scanMoneyRow(targetRows, false, fieldCount)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning %s target row: %v", check.Name, err)
+		}
+
+		group := unmatchedGroup
+		if sourceRow, exists := source[key]; exists {
+			group = sourceRow.group
+			if !sourceRow.paired {
+				sourceRow.paired = true
+				for i := range row.values {
+					if loss := math.Abs(sourceRow.values[i] - row.values[i]); loss >= lossTolerance {
+						result.Fields[i].LossRows++
+						result.Fields[i].LossAmount += loss
+					}
+				}
+			}
+		}
+
+		for i, value := range row.values {
+			result.Fields[i].Target.add(value)
+		}
+		addSum(targetSums, group, row.values)
+	}
+	if err := targetRows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s target rows: %v", check.Name, err)
+	}
+
+	groups := make(map[string]bool)
+	for group := range sourceSums {
+		groups[group] = true
+	}
+	for group := range targetSums {
+		groups[group] = true
+	}
+	result.GroupCount = len(groups)
+
+	for group := range groups {
+		for i, field := range check.Fields {
+			var sourceSum, targetSum float64
+			if sums := sourceSums[group]; sums != nil {
+				sourceSum = sums[i]
+			}
+			if sums := targetSums[group]; sums != nil {
+				targetSum = sums[i]
+			}
+			if !sameAmount(sourceSum, targetSum) {
+				result.Groups = append(result.Groups, GroupChecksum{
+					Group:     group,
+					Field:     field,
+					SourceSum: sourceSum,
+					TargetSum: targetSum,
+				})
+			}
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+
+	return result, nil
+}
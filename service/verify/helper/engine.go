@@ -0,0 +1,147 @@
+package helper
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type hashedRow struct {
+	values []sql.NullString
+	hash   string
+	seen   bool
+}
+
+// buildQuery menyusun SELECT key, field... dengan semua nilai di-cast ke text
+func buildQuery(side Side, exprs []string) string {
+	columns := []string{fmt.Sprintf("(%s)::text", side.Key)}
+	for _, expr := range exprs {
+		columns = append(columns, fmt.Sprintf("(%s)::text", expr))
+	}
+	return fmt.Sprintf("SELECT %s %s", strings.Join(columns, ", "), side.From)
+}
+
+// hashValues menghitung hash satu baris. NULL dan string kosong dianggap sama,
+// karena migrasi memang menyimpan NULL sumber sebagai string kosong.
+func hashValues(values []sql.NullString) string {
+	h := sha256.New()
+	for _, value := range values {
+		h.Write([]byte(value.String))
+		h.Write([]byte{0x1f})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func scanRow(rows *sql.Rows, fieldCount int) (string, []sql.NullString, error) {
+	var key sql.NullString
+	values := make([]sql.NullString, fieldCount)
+
+	dest := []interface{}{&key}
+	for i := range values {
+		dest = append(dest, &values[i])
+	}
+
+	err := rows.Scan(dest...)
+	return key.String, values, err
+}
+
+// Verify menghitung hash per baris dari nilai yang dipetakan di kedua sisi,
+// lalu membandingkan field satu per satu untuk baris yang hash-nya berbeda
+func Verify(mapping Mapping, sourceDB, targetDB *sql.DB) (*Result, error) {
+	startTime := time.Now()
+
+	var sourceExprs, targetExprs []string
+	for _, field := range mapping.Fields {
+		sourceExprs = append(sourceExprs, field.Source)
+		targetExprs = append(targetExprs, field.Target)
+	}
+
+	result := &Result{Mapping: mapping.Name, FieldCounts: make(map[string]int)}
+
+	sourceRows, err := sourceDB.Query(buildQuery(mapping.Source, sourceExprs))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s source rows: %v", mapping.Name, err)
+	}
+	defer sourceRows.Close()
+
+	source := make(map[string]*hashedRow)
+	for sourceRows.Next() {
+		key, values, err := scanRow(sourceRows, len(mapping.Fields))
+		if err != nil {
+			return nil, fmt.Errorf("error scanning %s source row: %v", mapping.Name, err)
+		}
+		if _, exists := source[key]; exists {
+			result.SourceDuplicates++
+			continue
+		}
+		source[key] = &hashedRow{values: values, hash: hashValues(values)}
+	}
+	if err := sourceRows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s source rows: %v", mapping.Name, err)
+	}
+
+	targetRows, err := targetDB.Query(buildQuery(mapping.Target, targetExprs))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s target rows: %v", mapping.Name, err)
+	}
+	defer targetRows.Close()
+
+	for targetRows.Next() {
+		key, values, err := scanRow(targetRows, len(mapping.Fields))
+		if err != nil {
+			return nil, fmt.Errorf("error scanning %s target row: %v", mapping.Name, err)
+		}
+
+		sourceRow, exists := source[key]
+		if !exists {
+			result.MissingInSource++
+			continue
+		}
+		if sourceRow.seen {
+			result.TargetDuplicates++
+			continue
+		}
+		sourceRow.seen = true
+		result.Compared++
+
+		targetHash := hashValues(values)
+		if targetHash == sourceRow.hash {
+			result.Identical++
+			continue
+		}
+
+		diff := RowDiff{Key: key, SourceHash: sourceRow.hash, TargetHash: targetHash}
+		for i, field := range mapping.Fields {
+			if sourceRow.values[i].String == values[i].String {
+				continue
+			}
+			diff.Fields = append(diff.Fields, FieldDiff{
+				Field:  field.Name,
+				Source: sourceRow.values[i],
+				Target: values[i],
+			})
+			result.FieldCounts[field.Name]++
+		}
+		result.Different = append(result.Different, diff)
+	}
+	if err := targetRows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s target rows: %v", mapping.Name, err)
+	}
+
+	for _, row := range source {
+		if !row.seen {
+			result.MissingInTarget++
+		}
+	}
+
+	sort.Slice(result.Different, func(i, j int) bool {
+		return result.Different[i].Key < result.Different[j].Key
+	})
+	result.Duration = time.Since(startTime)
+
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package helper
+
+import (
+	"github.com/ApesJs/go-migration-app/database"
+	packageHelper "github.com/ApesJs/go-migration-app/service/package/helper"
+)
+
+// epoch menyamakan timestamp dengan/tanpa time zone menjadi detik unix
+func epoch(column string) string {
+	return "extract(epoch from " + column + ")::bigint"
+}
+
+// amount menyamakan angka double/integer/numeric menjadi 2 desimal
+func amount(column string) string {
+	return "round((" + column + ")::numeric, 2)"
+}
+
+func sourceSide(key, from string) Side {
+	return Side{Label: "prod existing umrah", Connect: database.ConnectionProdExistingUmrahDB, Key: key, From: from}
+}
+
+// Mappings berisi pemetaan kolom setiap migrasi, mengikuti insert di service masing-masing
+var Mappings = []Mapping{
+	{
+		Name:        "user",
+		Description: "td_user -> user",
+		Source: sourceSide("id", `
+			FROM td_user
+			WHERE role = 'user' AND soft_delete = false
+		`),
+		Target: Side{
+			Label:   "local identity",
+			Connect: database.ConnectionLocalIdentityDB,
+			Key:     "id",
+			From:    `FROM "user" WHERE created_by = 'migration'`,
+		},
+		Fields: []Field{
+			{Name: "name", Source: "name", Target: "name"},
+			{Name: "email", Source: "email", Target: "email"},
+			{Name: "username", Source: "email", Target: "username"},
+			{Name: "avatar_provider", Source: "image", Target: "avatar_provider"},
+			{Name: "deleted", Source: "soft_delete", Target: "deleted"},
+			{Name: "created_at", Source: epoch("created_at"), Target: epoch("created_at")},
+			{Name: "modified_at", Source: epoch("updated_at"), Target: epoch("modified_at")},
+		},
+	},
+	{
+		Name:        "bdm",
+		Description: "tr_rda -> user (role bdm)",
+		Source:      sourceSide("id", `FROM tr_rda`),
+		Target: Side{
+			Label:   "prod identity",
+			Connect: database.ConnectionProdIdentityDB,
+			Key:     "id",
+			From:    `FROM "user" WHERE role = 'bdm'`,
+		},
+		Fields: []Field{
+			{Name: "name", Source: "name", Target: "name"},
+			{Name: "email", Source: "email", Target: "email"},
+			{Name: "username", Source: "email", Target: "username"},
+			{Name: "created_at", Source: epoch("created_at"), Target: epoch("created_at")},
+			{Name: "modified_at", Source: epoch("updated_at"), Target: epoch("modified_at")},
+		},
+	},
+	{
+		Name:        "organization",
+		Description: "td_travel -> organization",
+		Source:      sourceSide("id", `FROM td_travel`),
+		Target: Side{
+			Label:   "local identity",
+			Connect: database.ConnectionLocalIdentityDB,
+			Key:     "id",
+			From:    `FROM organization`,
+		},
+		Fields: []Field{
+			{Name: "name", Source: "name", Target: "name"},
+			{Name: "description", Source: `"desc"`, Target: "description"},
+			{Name: "is_active", Source: "is_active", Target: "is_active"},
+			{Name: "deleted", Source: "soft_delete", Target: "deleted"},
+			{Name: "created_at", Source: epoch("created_at"), Target: epoch("created_at")},
+			{Name: "modified_at", Source: epoch("updated_at"), Target: epoch("modified_at")},
+		},
+	},
+	{
+		Name:        "organization-instance",
+		Description: "td_travel (with rda_id) -> organization_instance",
+		Source:      sourceSide("id", `FROM td_travel WHERE rda_id IS NOT NULL`),
+		Target: Side{
+			Label:   "local identity",
+			Connect: database.ConnectionLocalIdentityDB,
+			Key:     "organization_id",
+			From:    `FROM organization_instance WHERE created_by = 'migration'`,
+		},
+		Fields: []Field{
+			{Name: "name", Source: "name", Target: "name"},
+			{Name: "address", Source: "address", Target: "address"},
+			{Name: "email", Source: "email", Target: "email"},
+			{Name: "phone_number", Source: "phone", Target: "phone_number"},
+			{Name: "thumbnail", Source: "image", Target: "thumbnail"},
+			{Name: "bdm_id", Source: "rda_id", Target: "bdm_id"},
+			{Name: "bank_channel", Source: "xendit_channel", Target: "bank_channel"},
+			{Name: "bank_account_number", Source: "xendit_account_number", Target: "bank_account_number"},
+			{Name: "bank_account_name", Source: "xendit_account_name", Target: "bank_account_name"},
+			{Name: "pic_name", Source: "pic_name", Target: "pic_name"},
+			{Name: "pic_phone", Source: "pic_phone", Target: "pic_phone"},
+			{Name: "tagline", Source: "tagline", Target: "tagline"},
+			{Name: "action_profile", Source: "action_profile", Target: "action_profile"},
+			{Name: "action_package", Source: "action_package", Target: "action_package"},
+			{Name: "own_guide", Source: "own_guide", Target: "own_guide"},
+			{Name: "fee_type", Source: "fee_type", Target: "fee_type"},
+			{Name: "fee_amount", Source: amount("fee_amount"), Target: amount("fee_amount")},
+			{Name: "is_consultation", Source: "is_consultation", Target: "is_consultation"},
+			{Name: "description", Source: `"desc"`, Target: "description"},
+			{Name: "legal_information.ppiu", Source: "ppiu", Target: "legal_information->>'ppiu'"},
+			{Name: "legal_information.pihk", Source: "pihk", Target: "legal_information->>'pihk'"},
+			{Name: "deleted", Source: "soft_delete", Target: "deleted"},
+		},
+	},
+	{
+		Name:        "wukala-persona",
+		Description: "td_travel_agent -> user_persona",
+		Source:      sourceSide("user_id", `FROM td_travel_agent`),
+		Target: Side{
+			Label:   "dev identity",
+			Connect: database.ConnectionDevIdentityDB,
+			Key:     "id",
+			From:    `FROM user_persona`,
+		},
+		Fields: []Field{
+			{Name: "phone_number", Source: "phone", Target: "phone_number"},
+			{Name: "travel_id", Source: "travel_id", Target: "travel_id"},
+			{Name: "desc", Source: `"desc"`, Target: `"desc"`},
+			{Name: "web_visit", Source: "web_visit", Target: "web_visit"},
+			{Name: "activated_at", Source: epoch("activated_at"), Target: epoch("activated_at")},
+			{Name: "parent_id", Source: "parent_id", Target: "parent_id"},
+			{Name: "bdm_user_id", Source: "rda_id", Target: "bdm_user_id"},
+			{Name: "alias", Source: "alias", Target: "alias"},
+			{Name: "nik", Source: "nik", Target: "nik"},
+			{Name: "instagram", Source: "instagram", Target: "instagram"},
+			{Name: "account_bank", Source: "account_bank", Target: "account_bank"},
+			{Name: "account_number", Source: "account_number", Target: "account_number"},
+			{Name: "account_name", Source: "account_name", Target: "account_name"},
+			{Name: "address", Source: "address", Target: "address"},
+			{Name: "city_id", Source: "city_id", Target: "city_id"},
+			{Name: "approved_by", Source: "approved_by", Target: "approved_by"},
+			{Name: "approved_at", Source: epoch("approved_at"), Target: epoch("approved_at")},
+			{Name: "code", Source: "code", Target: "code"},
+			{Name: "fee_type", Source: "fee_type", Target: "fee_type"},
+			{Name: "fee", Source: amount("fee"), Target: amount("fee")},
+			{Name: "discount_type", Source: "discount_type", Target: "discount_type"},
+			{Name: "discount", Source: amount("discount"), Target: amount("discount")},
+		},
+	},
+	{
+		Name:        "package",
+		Description: "td_package -> package + package_variant (by slug)",
+		Source: sourceSide("slug", `
+			FROM td_package
+			WHERE `+packageHelper.ActivePackageFilter),
+		Target: Side{
+			Label:   "dev umrah",
+			Connect: database.ConnectionDevUmrahDB,
+			Key:     "p.slug",
+			From: `
+				FROM package p
+				JOIN package_variant v ON v.package_id = p.id
+				WHERE p.created_by = 'migration'
+			`,
+		},
+		Fields: []Field{
+			{Name: "title", Source: "name", Target: "p.title"},
+			{Name: "thumbnail", Source: "image", Target: "p.thumbnail"},
+			{Name: "description", Source: "share_desc", Target: "p.description"},
+			{Name: "terms_condition", Source: "term_condition", Target: "p.terms_condition"},
+			{Name: "facility", Source: "facility", Target: "p.facility"},
+			{Name: "currency", Source: "currency", Target: "p.currency"},
+			{Name: "dp_type", Source: "CASE WHEN dp_type = 'fixed' THEN 'nominal' ELSE 'percentage' END", Target: "p.dp_type"},
+			{Name: "dp_amount", Source: amount("dp_amount"), Target: amount("p.dp_amount")},
+			{Name: "fee_type", Source: "fee_type", Target: "p.fee_type"},
+			{Name: "fee_amount", Source: amount("fee_amount"), Target: amount("p.fee_amount")},
+			{Name: "departure_date", Source: "departure_date::date", Target: "v.departure_date::date"},
+			{Name: "arrival_date", Source: "arrival_date::date", Target: "v.arrival_date::date"},
+			{Name: "price_double", Source: amount("price_double"), Target: amount("v.price_double")},
+			{Name: "price_triple", Source: amount("price_triple"), Target: amount("v.price_triple")},
+			{Name: "price_quad", Source: amount("price_quad"), Target: amount("v.price_quad")},
+			{Name: "created_at", Source: epoch("created_at"), Target: epoch("p.created_at")},
+		},
+	},
+}
+
+// FindMapping mencari mapping berdasarkan nama
+func FindMapping(name string) (Mapping, bool) {
+	for _, mapping := range Mappings {
+		if mapping.Name == name {
+			return mapping, true
+		}
+	}
+	return Mapping{}, false
+}
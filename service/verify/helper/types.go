@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Field adalah satu kolom yang dipetakan dari sumber ke target. Source dan Target
+// berupa ekspresi SQL, sehingga transformasi yang disengaja oleh migrasi
+// (misalnya dp_type fixed -> nominal) bisa ditiru di sisi sumber.
+type Field struct {
+	Name   string
+	Source string
+	Target string
+}
+
+// Side adalah salah satu sisi verifikasi. From berisi klausa FROM/WHERE,
+// Key adalah ekspresi yang dipakai untuk memasangkan baris sumber dan target.
+type Side struct {
+	Label   string
+	Connect func() *sql.DB
+	Key     string
+	From    string
+}
+
+// Mapping adalah pemetaan kolom satu migrasi
+type Mapping struct {
+	Name        string
+	Description string
+	Source      Side
+	Target      Side
+	Fields      []Field
+}
+
+// FieldDiff adalah nilai satu field yang berbeda
+type FieldDiff struct {
+	Field  string
+	Source sql.NullString
+	Target sql.NullString
+}
+
+// RowDiff adalah satu baris yang hash-nya berbeda antara sumber dan target
+type RowDiff struct {
+	Key        string
+	SourceHash string
+	TargetHash string
+	Fields     []FieldDiff
+}
+
+// Result adalah hasil verifikasi satu mapping
+type Result struct {
+	Mapping          string
+	Compared         int // baris yang ada di kedua sisi
+	Identical        int
+	Different        []RowDiff
+	MissingInTarget  int
+	MissingInSource  int
+	SourceDuplicates int
+	TargetDuplicates int
+	FieldCounts      map[string]int // jumlah baris berbeda per field
+	Duration         time.Duration
+}
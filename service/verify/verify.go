@@ -0,0 +1,103 @@
+package verify
+
+import (
+	"fmt"
+	"github.com/ApesJs/go-migration-app/i18n"
+	"github.com/ApesJs/go-migration-app/logging"
+	"github.com/ApesJs/go-migration-app/service/verify/helper"
+	"sort"
+	"time"
+)
+
+const valueWidth = 40
+
+// VerifyService membandingkan nilai setiap field yang dipetakan antara sumber dan target.
+// limit membatasi jumlah baris berbeda yang dicetak, 0 = cetak semua.
+func VerifyService(names []string, limit int) {
+	var mappings []helper.Mapping
+	for _, name := range names {
+		if name == "all" {
+			mappings = helper.Mappings
+			break
+		}
+		mapping, ok := helper.FindMapping(name)
+		if !ok {
+			logging.Fatal("Unknown mapping", "mapping", name)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	startTime := time.Now()
+	for _, mapping := range mappings {
+		fmt.Printf("\n%s\n", i18n.T("verify.running", mapping.Name, mapping.Description, len(mapping.Fields)))
+
+		sourceDB := mapping.Source.Connect()
+		targetDB := mapping.Target.Connect()
+
+		result, err := helper.Verify(mapping, sourceDB, targetDB)
+		sourceDB.Close()
+		targetDB.Close()
+		if err != nil {
+			logging.Fatal("Error verifying", "error", err)
+		}
+
+		printResult(mapping, result, limit)
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("verify.finished", time.Since(startTime).Round(time.Millisecond)))
+}
+
+func printResult(mapping helper.Mapping, result *helper.Result, limit int) {
+	fmt.Printf("\n%s\n", i18n.T("verify.result", mapping.Name))
+	fmt.Printf("------------------------\n")
+	fmt.Println(i18n.T("verify.compared", result.Compared))
+	fmt.Println(i18n.T("verify.identical", result.Identical))
+	fmt.Println(i18n.T("verify.different", len(result.Different)))
+	fmt.Println(i18n.T("verify.only_source", result.MissingInTarget))
+	fmt.Println(i18n.T("verify.only_target", result.MissingInSource))
+	if result.SourceDuplicates > 0 || result.TargetDuplicates > 0 {
+		fmt.Println(i18n.T("verify.duplicates", result.SourceDuplicates, result.TargetDuplicates))
+	}
+
+	if len(result.FieldCounts) > 0 {
+		fields := make([]string, 0, len(result.FieldCounts))
+		for field := range result.FieldCounts {
+			fields = append(fields, field)
+		}
+		sort.Slice(fields, func(i, j int) bool {
+			return result.FieldCounts[fields[i]] > result.FieldCounts[fields[j]]
+		})
+
+		fmt.Printf("\n%s\n", i18n.T("verify.fields"))
+		for _, field := range fields {
+			fmt.Println(i18n.T("verify.field_rows", field, result.FieldCounts[field]))
+		}
+	}
+
+	for i, diff := range result.Different {
+		if limit > 0 && i >= limit {
+			fmt.Printf("\n%s\n", i18n.T("verify.more_rows", len(result.Different)-limit))
+			break
+		}
+
+		fmt.Printf("\n%s\n", i18n.T("verify.diff", i+1, diff.Key, diff.SourceHash[:12], diff.TargetHash[:12]))
+		fmt.Printf("   %-24s %-*s %s\n", "FIELD", valueWidth, "SOURCE", "TARGET")
+		for _, field := range diff.Fields {
+			fmt.Printf("   %-24s %-*s %s\n", field.Field, valueWidth, formatValue(field.Source.String, field.Source.Valid),
+				formatValue(field.Target.String, field.Target.Valid))
+		}
+	}
+
+	fmt.Printf("\n%s\n", i18n.T("reconcile.completed", result.Duration.Round(time.Millisecond)))
+}
+
+func formatValue(value string, valid bool) string {
+	if !valid {
+		return "NULL"
+	}
+	quoted := fmt.Sprintf("%q", value)
+	if runes := []rune(quoted); len(runes) > valueWidth {
+		quoted = string(runes[:valueWidth-3]) + "..."
+	}
+	return quoted
+}
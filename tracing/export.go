@@ -0,0 +1,190 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultEndpoint adalah endpoint OTLP/HTTP collector lokal
+const DefaultEndpoint = "http://localhost:4318/v1/traces"
+
+// Endpoint mengembalikan endpoint OTLP traces dengan variabel standar OpenTelemetry:
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT apa adanya, atau OTEL_EXPORTER_OTLP_ENDPOINT + /v1/traces.
+// Exporter OTLP membaca variabel yang sama, fungsi ini untuk ditampilkan ke pengguna.
+func Endpoint() string {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		return strings.TrimRight(endpoint, "/") + "/v1/traces"
+	}
+	return DefaultEndpoint
+}
+
+// newOTLPExporter mengirim span ke collector lewat OTLP/HTTP
+func newOTLPExporter() (sdktrace.SpanExporter, error) {
+	return otlptracehttp.New(context.Background())
+}
+
+// fileExporter menulis satu request OTLP JSON per baris, format yang bisa
+// diputar ulang ke collector atau dibaca offline
+type fileExporter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileExporter{file: file}, nil
+}
+
+func (e *fileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(encode(spans))
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.file.Write(append(line, '\n'))
+	return err
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
+
+// Struktur JSON ExportTraceServiceRequest OTLP. Id ditulis dalam hex dan
+// angka 64-bit sebagai string sesuai encoding JSON OTLP.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"` // 2 = error
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// encode menyusun span dari satu provider menjadi satu request, dikelompokkan per scope
+func encode(spans []sdktrace.ReadOnlySpan) otlpRequest {
+	var (
+		scopes []otlpScopeSpans
+		index  = make(map[string]int)
+	)
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           s.SpanContext().TraceID().String(),
+			SpanID:            s.SpanContext().SpanID().String(),
+			Name:              s.Name(),
+			Kind:              int(s.SpanKind()),
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime().UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime().UnixNano(), 10),
+			Attributes:        encodeAttributes(s.Attributes()),
+		}
+		if s.Parent().IsValid() {
+			span.ParentSpanID = s.Parent().SpanID().String()
+		}
+		if s.Status().Code == codes.Error {
+			span.Status = &otlpStatus{Code: 2, Message: s.Status().Description}
+		}
+
+		scope := s.InstrumentationScope().Name
+		i, ok := index[scope]
+		if !ok {
+			i = len(scopes)
+			index[scope] = i
+			scopes = append(scopes, otlpScopeSpans{Scope: otlpScope{Name: scope}})
+		}
+		scopes[i].Spans = append(scopes[i].Spans, span)
+	}
+
+	var resource otlpResource
+	if len(spans) > 0 && spans[0].Resource() != nil {
+		resource.Attributes = encodeAttributes(spans[0].Resource().Attributes())
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{Resource: resource, ScopeSpans: scopes}}}
+}
+
+func encodeAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	var encoded []otlpKeyValue
+	for _, attr := range attrs {
+		var value otlpValue
+		switch attr.Value.Type() {
+		case attribute.INT64:
+			s := strconv.FormatInt(attr.Value.AsInt64(), 10)
+			value.IntValue = &s
+		case attribute.BOOL:
+			b := attr.Value.AsBool()
+			value.BoolValue = &b
+		case attribute.FLOAT64:
+			f := attr.Value.AsFloat64()
+			value.DoubleValue = &f
+		default:
+			s := attr.Value.Emit()
+			value.StringValue = &s
+		}
+		encoded = append(encoded, otlpKeyValue{Key: string(attr.Key), Value: value})
+	}
+	return encoded
+}
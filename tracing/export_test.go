@@ -0,0 +1,166 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testTraceID = trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	testRootID  = trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	testChildID = trace.SpanID{0x53, 0x99, 0x5c, 0x3f, 0x42, 0xcd, 0x8a, 0xd8}
+)
+
+func stub(name string, id, parent trace.SpanID, kind trace.SpanKind) tracetest.SpanStub {
+	start := time.Unix(1760875200, 123456789)
+	s := tracetest.SpanStub{
+		Name:                 name,
+		SpanKind:             kind,
+		StartTime:            start,
+		EndTime:              start.Add(1500 * time.Millisecond),
+		SpanContext:          trace.NewSpanContext(trace.SpanContextConfig{TraceID: testTraceID, SpanID: id, TraceFlags: trace.FlagsSampled}),
+		InstrumentationScope: instrumentation.Scope{Name: scopeName},
+		Resource:             resource.NewSchemaless(attribute.String("service.name", "go-migration-app")),
+	}
+	if parent.IsValid() {
+		s.Parent = trace.NewSpanContext(trace.SpanContextConfig{TraceID: testTraceID, SpanID: parent, TraceFlags: trace.FlagsSampled})
+	}
+	return s
+}
+
+func TestEncode(t *testing.T) {
+	root := stub("run", testRootID, trace.SpanID{}, trace.SpanKindInternal)
+	root.Attributes = []attribute.KeyValue{
+		attribute.String("migration.run_id", "20261019T120000.000-ab12"),
+		attribute.Int("process.pid", 4242),
+		attribute.Bool("dry_run", false),
+		attribute.Float64("ratio", 0.5),
+		attribute.StringSlice("migration.selected", []string{"user", "package"}),
+	}
+
+	child := stub("SELECT umrah", testChildID, testRootID, trace.SpanKindClient)
+	child.Status = sdktrace.Status{Code: codes.Error, Description: "connection refused"}
+
+	tests := []struct {
+		name  string
+		spans tracetest.SpanStubs
+		want  []otlpSpan
+	}{
+		{
+			name:  "root span without parent, attribute types",
+			spans: tracetest.SpanStubs{root},
+			want: []otlpSpan{{
+				TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:            "00f067aa0ba902b7",
+				Name:              "run",
+				Kind:              1,
+				StartTimeUnixNano: "1760875200123456789",
+				EndTimeUnixNano:   "1760875201623456789",
+				Attributes: []otlpKeyValue{
+					{Key: "migration.run_id", Value: otlpValue{StringValue: ptr("20261019T120000.000-ab12")}},
+					{Key: "process.pid", Value: otlpValue{IntValue: ptr("4242")}},
+					{Key: "dry_run", Value: otlpValue{BoolValue: ptr(false)}},
+					{Key: "ratio", Value: otlpValue{DoubleValue: ptr(0.5)}},
+					{Key: "migration.selected", Value: otlpValue{StringValue: ptr(`["user","package"]`)}},
+				},
+			}},
+		},
+		{
+			name:  "client span with parent and error status",
+			spans: tracetest.SpanStubs{child},
+			want: []otlpSpan{{
+				TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:            "53995c3f42cd8ad8",
+				ParentSpanID:      "00f067aa0ba902b7",
+				Name:              "SELECT umrah",
+				Kind:              3,
+				StartTimeUnixNano: "1760875200123456789",
+				EndTimeUnixNano:   "1760875201623456789",
+				Status:            &otlpStatus{Code: 2, Message: "connection refused"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encode(tt.spans.Snapshots())
+			if len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans) != 1 {
+				t.Fatalf("encode() = %+v, want one resource with one scope", got)
+			}
+			resource := got.ResourceSpans[0]
+			if want := []otlpKeyValue{{Key: "service.name", Value: otlpValue{StringValue: ptr("go-migration-app")}}}; !jsonEqual(resource.Resource.Attributes, want) {
+				t.Errorf("resource = %s", mustJSON(resource.Resource.Attributes))
+			}
+			scope := resource.ScopeSpans[0]
+			if scope.Scope.Name != scopeName {
+				t.Errorf("scope = %q, want %q", scope.Scope.Name, scopeName)
+			}
+			if !jsonEqual(scope.Spans, tt.want) {
+				t.Errorf("spans =\n%s\nwant\n%s", mustJSON(scope.Spans), mustJSON(tt.want))
+			}
+		})
+	}
+}
+
+// TestFileExporter memastikan setiap batch menjadi satu baris request OTLP JSON
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run-1", "user.jsonl")
+	exp, err := newFileExporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := stub("run", testRootID, trace.SpanID{}, trace.SpanKindInternal)
+	child := stub("SELECT umrah", testChildID, testRootID, trace.SpanKindClient)
+	for _, batch := range []tracetest.SpanStubs{{root, child}, {}, {child}} {
+		if err := exp.ExportSpans(context.Background(), batch.Snapshots()); err != nil {
+			t.Fatalf("ExportSpans() error = %v", err)
+		}
+	}
+	if err := exp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (empty batches are not written):\n%s", len(lines), data)
+	}
+	for i, want := range []int{2, 1} {
+		var request otlpRequest
+		if err := json.Unmarshal([]byte(lines[i]), &request); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if got := len(request.ResourceSpans[0].ScopeSpans[0].Spans); got != want {
+			t.Errorf("line %d has %d spans, want %d", i+1, got, want)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func mustJSON(v interface{}) string {
+	data, _ := json.MarshalIndent(v, "", "  ")
+	return string(data)
+}
+
+func jsonEqual(a, b interface{}) bool {
+	return mustJSON(a) == mustJSON(b)
+}
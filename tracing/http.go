@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"fmt"
+	"go.opentelemetry.io/otel/propagation"
+	"net/http"
+)
+
+// Transport membungkus base (nil = http.DefaultTransport) sehingga setiap request
+// menjadi span client anak span di context request, dan membawa header
+// traceparent ke server tujuan
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Query string dan user info tidak ikut dicatat karena bisa berisi token
+	url := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	ctx, span := StartClient(req.Context(), req.Method+" "+req.URL.Host,
+		"http.request.method", req.Method,
+		"url.full", url,
+		"server.address", req.URL.Hostname(),
+	)
+	if span == nil {
+		return t.base.RoundTrip(req)
+	}
+	defer span.End()
+
+	req = req.Clone(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	span.SetAttr("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= 400 {
+		span.SetError(fmt.Errorf("%s", resp.Status))
+	}
+	return resp, nil
+}
@@ -0,0 +1,353 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exporter yang bisa dipilih lewat --trace atau TRACE_EXPORTER, boleh lebih dari satu dipisah koma
+const (
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// Nilai default jika TRACE_* tidak diisi
+const (
+	DefaultDir       = "traces"
+	DefaultBatchSize = 100
+)
+
+// scopeName adalah nama scope semua span aplikasi ini
+const scopeName = "github.com/ApesJs/go-migration-app/tracing"
+
+// shutdownTimeout membatasi pengiriman span terakhir saat proses berhenti
+const shutdownTimeout = 10 * time.Second
+
+// propagator membaca dan menulis header W3C traceparent
+var propagator = propagation.TraceContext{}
+
+// Satu proses menjalankan satu migrasi, jadi provider dan span root disimpan di
+// level package seperti report dan metrics. Parent span lain diberikan lewat context.
+var (
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	root     *Span
+	rootCtx  = context.Background()
+	active   context.Context    // context batch progress yang berjalan, lihat Activate
+	open     = map[*Span]bool{} // span yang belum di-End, ditutup oleh Close
+)
+
+// Exporters mengembalikan exporter dari TRACE_EXPORTER, kosong berarti tracing mati
+func Exporters() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv("TRACE_EXPORTER"), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Enabled bernilai true setelah Setup berhasil membuka exporter
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return provider != nil
+}
+
+// Supported mengecek nilai --trace, misalnya "otlp", "file" atau "otlp,file"
+func Supported(value string) bool {
+	for _, name := range strings.Split(value, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case ExporterOTLP, ExporterFile:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Dir mengembalikan folder file exporter dari TRACE_DIR
+func Dir() string {
+	if dir := os.Getenv("TRACE_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultDir
+}
+
+// Path mengembalikan file trace satu proses: <Dir>/<run-id>/<name>.jsonl
+func Path(runID, name string) string {
+	return filepath.Join(Dir(), runID, name+".jsonl")
+}
+
+// BatchSize adalah jumlah record per span batch dari TRACE_BATCH_SIZE
+func BatchSize() int {
+	if n, err := strconv.Atoi(os.Getenv("TRACE_BATCH_SIZE")); err == nil && n > 0 {
+		return n
+	}
+	return DefaultBatchSize
+}
+
+// Setup menyalakan tracing jika TRACE_EXPORTER diisi dan membuka span root
+// bernama name. Jika TRACEPARENT diisi (oleh runner untuk proses migrasi),
+// span root menjadi anak span tersebut sehingga satu run menjadi satu trace.
+func Setup(runID, name string) {
+	names := Exporters()
+	if len(names) == 0 {
+		return
+	}
+
+	var options []sdktrace.TracerProviderOption
+	for _, exporterName := range names {
+		switch exporterName {
+		case ExporterOTLP:
+			// Endpoint dan header dibaca exporter dari OTEL_EXPORTER_OTLP_*
+			exp, err := newOTLPExporter()
+			if err != nil {
+				slog.Warn("Warning: could not create OTLP trace exporter", "error", err)
+				continue
+			}
+			options = append(options, sdktrace.WithBatcher(exp))
+		case ExporterFile:
+			exp, err := newFileExporter(Path(runID, name))
+			if err != nil {
+				slog.Warn("Warning: could not open trace file", "error", err)
+				continue
+			}
+			options = append(options, sdktrace.WithBatcher(exp))
+		default:
+			slog.Warn("Warning: unknown trace exporter", "exporter", exporterName)
+		}
+	}
+	if len(options) == 0 {
+		return
+	}
+
+	parent := propagator.Extract(context.Background(), propagation.MapCarrier{"traceparent": os.Getenv("TRACEPARENT")})
+	start(parent, runID, name, append(options, sdktrace.WithResource(newResource(runID)))...)
+}
+
+// start membuat provider dan span root, dipisah dari Setup supaya test bisa memakai exporter sendiri
+func start(parent context.Context, runID, name string, options ...sdktrace.TracerProviderOption) {
+	p := sdktrace.NewTracerProvider(options...)
+
+	mu.Lock()
+	provider = p
+	tracer = p.Tracer(scopeName)
+	mu.Unlock()
+
+	ctx, span := Start(parent, name, "migration.run_id", runID, "process.pid", os.Getpid())
+
+	mu.Lock()
+	root, rootCtx = span, ctx
+	mu.Unlock()
+}
+
+// newResource menyusun atribut proses. OTEL_SERVICE_NAME dan OTEL_RESOURCE_ATTRIBUTES
+// menimpa nilai default di sini.
+func newResource(runID string) *resource.Resource {
+	host, _ := os.Hostname()
+	attrs := []attribute.KeyValue{
+		attribute.String("service.name", "go-migration-app"),
+		attribute.String("host.name", host),
+		attribute.Int("process.pid", os.Getpid()),
+		attribute.String("migration.run_id", runID),
+	}
+	if env := os.Getenv("MIGRATION_ENV"); env != "" {
+		attrs = append(attrs, attribute.String("deployment.environment", env))
+	}
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(attrs...),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		slog.Warn("Warning: could not read trace resource attributes", "error", err)
+	}
+	return res
+}
+
+// Close menutup semua span yang masih terbuka, termasuk root, lalu mengirim sisa span.
+// Dipanggil juga oleh logging.Exit sehingga span tidak hilang saat logging.Fatal.
+func Close() {
+	mu.Lock()
+	p := provider
+	remaining := make([]*Span, 0, len(open))
+	for s := range open {
+		if s != root {
+			remaining = append(remaining, s)
+		}
+	}
+	r := root
+	mu.Unlock()
+
+	if p == nil {
+		return
+	}
+	for _, s := range remaining {
+		s.End()
+	}
+	r.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := p.Shutdown(ctx); err != nil {
+		slog.Warn("Warning: could not export trace spans", "error", err)
+	}
+
+	mu.Lock()
+	provider, tracer, root = nil, nil, nil
+	rootCtx, active = context.Background(), nil
+	mu.Unlock()
+}
+
+// Root mengembalikan span root proses ini, nil jika tracing mati
+func Root() *Span {
+	mu.Lock()
+	defer mu.Unlock()
+	return root
+}
+
+// TraceID mengembalikan id trace proses ini dalam hex, kosong jika tracing mati
+func TraceID() string {
+	mu.Lock()
+	defer mu.Unlock()
+	if root == nil {
+		return ""
+	}
+	return root.span.SpanContext().TraceID().String()
+}
+
+// Context mengembalikan parent untuk kode yang tidak punya context sendiri,
+// misalnya query database/sql tanpa ...Context dari service: batch progress
+// yang sedang berjalan, atau span root.
+func Context() context.Context {
+	mu.Lock()
+	defer mu.Unlock()
+	if active != nil {
+		return active
+	}
+	return rootCtx
+}
+
+// Activate menjadikan ctx hasil Context sampai Activate dipanggil lagi, ctx nil
+// kembali ke span root. Dipakai progress saat membuka dan menutup batch.
+func Activate(ctx context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+	active = ctx
+}
+
+// Span adalah satu operasi dalam trace. Semua method aman dipanggil dengan
+// Span nil, sehingga pemanggil tidak perlu mengecek apakah tracing aktif.
+type Span struct {
+	span trace.Span
+	ctx  context.Context
+}
+
+// Start membuka span anak dari span di ctx. Jika ctx tidak membawa span, parent-nya
+// Context. Context yang dikembalikan membawa span baru untuk dipakai sebagai parent
+// span berikutnya. attrs diberikan berpasangan: nama, nilai, ...
+func Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, *Span) {
+	return newSpan(ctx, name, trace.SpanKindInternal, attrs)
+}
+
+// StartClient sama dengan Start untuk panggilan keluar seperti query SQL dan HTTP
+func StartClient(ctx context.Context, name string, attrs ...interface{}) (context.Context, *Span) {
+	return newSpan(ctx, name, trace.SpanKindClient, attrs)
+}
+
+func newSpan(ctx context.Context, name string, kind trace.SpanKind, attrs []interface{}) (context.Context, *Span) {
+	if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = Context()
+	}
+
+	mu.Lock()
+	t := tracer
+	mu.Unlock()
+	if t == nil {
+		return ctx, nil
+	}
+
+	ctx, span := t.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes(attrs)...))
+	s := &Span{span: span, ctx: ctx}
+
+	mu.Lock()
+	open[s] = true
+	mu.Unlock()
+	return ctx, s
+}
+
+// SetAttr menambah atribut span, diberikan berpasangan: nama, nilai, ...
+func (s *Span) SetAttr(attrs ...interface{}) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attributes(attrs)...)
+}
+
+// SetError menandai span gagal, err nil diabaikan
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End menutup span dan mengantrekannya untuk dikirim
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	mu.Lock()
+	delete(open, s)
+	mu.Unlock()
+	s.span.End()
+}
+
+// Traceparent mengembalikan span ini dalam format W3C traceparent, untuk
+// header HTTP dan environment proses anak
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(s.ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+func attributes(attrs []interface{}) []attribute.KeyValue {
+	var converted []attribute.KeyValue
+	for i := 0; i+1 < len(attrs); i += 2 {
+		key := fmt.Sprint(attrs[i])
+		switch v := attrs[i+1].(type) {
+		case string:
+			converted = append(converted, attribute.String(key, v))
+		case int:
+			converted = append(converted, attribute.Int(key, v))
+		case int64:
+			converted = append(converted, attribute.Int64(key, v))
+		case bool:
+			converted = append(converted, attribute.Bool(key, v))
+		case float64:
+			converted = append(converted, attribute.Float64(key, v))
+		default:
+			converted = append(converted, attribute.String(key, fmt.Sprint(v)))
+		}
+	}
+	return converted
+}
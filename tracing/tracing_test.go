@@ -0,0 +1,236 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recorder menyimpan span yang diekspor. Berbeda dengan InMemoryExporter,
+// isinya tidak dihapus saat Shutdown sehingga bisa dibaca setelah Close.
+type recorder struct {
+	mu    sync.Mutex
+	spans map[string]sdktrace.ReadOnlySpan
+}
+
+func (r *recorder) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range spans {
+		r.spans[s.Name()] = s
+	}
+	return nil
+}
+
+func (r *recorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// get mengembalikan span bernama name, test gagal jika span itu tidak diekspor
+func (r *recorder) get(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.spans[name]
+	if !ok {
+		t.Fatalf("span %q was not exported", name)
+	}
+	return s
+}
+
+// setupTest menyalakan tracing dengan recorder, parent adalah context TRACEPARENT
+func setupTest(t *testing.T, parent context.Context) *recorder {
+	t.Helper()
+	r := &recorder{spans: make(map[string]sdktrace.ReadOnlySpan)}
+	start(parent, "run-1", "run", sdktrace.WithSyncer(r))
+	t.Cleanup(Close)
+	return r
+}
+
+func assertParent(t *testing.T, child, parent sdktrace.ReadOnlySpan) {
+	t.Helper()
+	if got, want := child.Parent().SpanID(), parent.SpanContext().SpanID(); got != want {
+		t.Errorf("%s has parent %s, want %s (%s)", child.Name(), got, want, parent.Name())
+	}
+	if got, want := child.SpanContext().TraceID(), parent.SpanContext().TraceID(); got != want {
+		t.Errorf("%s is in trace %s, want %s", child.Name(), got, want)
+	}
+}
+
+// TestConcurrentParents memastikan parent setiap span diambil dari context yang
+// diberikan, bukan dari span yang kebetulan dibuka goroutine lain
+func TestConcurrentParents(t *testing.T) {
+	r := setupTest(t, context.Background())
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, worker := Start(Context(), fmt.Sprintf("migration %d", i))
+			for j := 0; j < 20; j++ {
+				_, query := StartClient(ctx, fmt.Sprintf("SELECT %d.%d", i, j))
+				query.End()
+			}
+			worker.End()
+		}(i)
+	}
+	wg.Wait()
+	Close()
+
+	root := r.get(t, "run")
+	if root.Parent().IsValid() {
+		t.Errorf("root has parent %s, want none", root.Parent().SpanID())
+	}
+	for i := 0; i < workers; i++ {
+		worker := r.get(t, fmt.Sprintf("migration %d", i))
+		assertParent(t, worker, root)
+		for j := 0; j < 20; j++ {
+			assertParent(t, r.get(t, fmt.Sprintf("SELECT %d.%d", i, j)), worker)
+		}
+	}
+}
+
+// TestDefaultParent memastikan span tanpa context menjadi anak batch yang aktif
+func TestDefaultParent(t *testing.T) {
+	r := setupTest(t, context.Background())
+
+	ctx, phase := Start(Context(), "users")
+	Activate(ctx)
+	batchCtx, batch := Start(ctx, "users batch 1")
+	Activate(batchCtx)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"nil context", nil},
+		{"context without span", context.Background()},
+	}
+	for _, tt := range tests {
+		_, s := StartClient(tt.ctx, tt.name)
+		s.End()
+	}
+
+	batch.End()
+	Activate(nil)
+	phase.End()
+	_, after := StartClient(context.Background(), "after phase")
+	after.End()
+	Close()
+
+	root := r.get(t, "run")
+	assertParent(t, r.get(t, "users"), root)
+	assertParent(t, r.get(t, "users batch 1"), r.get(t, "users"))
+	for _, tt := range tests {
+		assertParent(t, r.get(t, tt.name), r.get(t, "users batch 1"))
+	}
+	assertParent(t, r.get(t, "after phase"), root)
+}
+
+// TestTraceparent memastikan proses migrasi melanjutkan trace runner
+func TestTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	parent := propagator.Extract(context.Background(), propagation.MapCarrier{"traceparent": "00-" + traceID + "-" + spanID + "-01"})
+	r := setupTest(t, parent)
+
+	if got := TraceID(); got != traceID {
+		t.Errorf("TraceID() = %s, want %s", got, traceID)
+	}
+	_, child := Start(Context(), "child")
+	want := fmt.Sprintf("00-%s-%s-01", traceID, child.span.SpanContext().SpanID())
+	if got := child.Traceparent(); got != want {
+		t.Errorf("Traceparent() = %s, want %s", got, want)
+	}
+	child.End()
+	Close()
+
+	root := r.get(t, "run")
+	if got := root.Parent().SpanID().String(); got != spanID || !root.Parent().IsRemote() {
+		t.Errorf("root parent = %s (remote %v), want remote %s", got, root.Parent().IsRemote(), spanID)
+	}
+	if got := root.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("root trace = %s, want %s", got, traceID)
+	}
+}
+
+// TestCloseEndsOpenSpans meniru logging.Fatal: fase dan batch belum di-End saat Close
+func TestCloseEndsOpenSpans(t *testing.T) {
+	r := setupTest(t, context.Background())
+
+	ctx, _ := Start(Context(), "phase")
+	Start(ctx, "phase batch 3")
+	Root().SetError(fmt.Errorf("migration user aborted"))
+	Close()
+
+	for _, name := range []string{"run", "phase", "phase batch 3"} {
+		if s := r.get(t, name); s.EndTime().IsZero() {
+			t.Errorf("%s has no end time", name)
+		}
+	}
+	if status := r.get(t, "run").Status(); status.Code != codes.Error || status.Description != "migration user aborted" {
+		t.Errorf("root status = %+v, want error", status)
+	}
+	if Enabled() || Root() != nil || TraceID() != "" {
+		t.Error("tracing still enabled after Close")
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx, s := Start(context.Background(), "nothing")
+	if s != nil {
+		t.Fatalf("Start() = %v while tracing is off, want nil", s)
+	}
+	if ctx == nil {
+		t.Error("Start() returned a nil context")
+	}
+	// Method Span nil tidak boleh panic
+	s.SetAttr("key", "value")
+	s.SetError(fmt.Errorf("ignored"))
+	s.End()
+	if got := s.Traceparent(); got != "" {
+		t.Errorf("Traceparent() = %q, want empty", got)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header.Get("traceparent")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	r := setupTest(t, context.Background())
+	client := &http.Client{Transport: Transport(nil)}
+	resp, err := client.Get(server.URL + "/organization?token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	Close()
+
+	span := r.get(t, "GET "+server.Listener.Addr().String())
+	assertParent(t, span, r.get(t, "run"))
+	if want := fmt.Sprintf("00-%s-%s-01", span.SpanContext().TraceID(), span.SpanContext().SpanID()); header != want {
+		t.Errorf("traceparent header = %q, want %q", header, want)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %+v, want error for 502", span.Status())
+	}
+	for _, attr := range span.Attributes() {
+		if attr.Key == "url.full" && attr.Value.AsString() != server.URL+"/organization" {
+			t.Errorf("url.full = %s, want it without the query string", attr.Value.AsString())
+		}
+	}
+}